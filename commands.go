@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/migrations"
+	"github.com/mrojasb2000/GinRecipes/recipeio"
+	"github.com/mrojasb2000/GinRecipes/searchindex"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// command is a recipes-api subcommand. run receives the arguments that
+// follow the command name.
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{name: "serve", usage: "run the HTTP API (default)", run: runServe},
+	{name: "reindex", usage: "rebuild the embedded search index from the store", run: runReindex},
+	{name: "import", usage: "load recipes from a JSON, NDJSON or CSV file", run: runImport},
+	{name: "export", usage: "write every recipe to a JSON, NDJSON or CSV file", run: runExport},
+	{name: "migrate", usage: "apply, revert or list MongoDB migrations: migrate up|down|status", run: runMigrate},
+}
+
+// runCommand dispatches args, the command line without the program name,
+// to the matching subcommand. Without a command name the API is served.
+func runCommand(ctx context.Context, args []string) error {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(ctx, args)
+		}
+	}
+	var usage strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&usage, "\n  %-10s %s", cmd.name, cmd.usage)
+	}
+	return fmt.Errorf("unknown command %q, available commands:%s", name, usage.String())
+}
+
+// runReindex rebuilds the search index at -index, SEARCH_INDEX_PATH by
+// default, from every recipe of the configured store.
+func runReindex(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	path := flags.String("index", os.Getenv("SEARCH_INDEX_PATH"), "search index file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("reindex: set -index or SEARCH_INDEX_PATH")
+	}
+	backends, err := newStore(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(*path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	index, err := searchindex.Open(*path)
+	if err != nil {
+		return err
+	}
+	n, err := searchindex.Rebuild(ctx, backends.recipes, index)
+	if err != nil {
+		return err
+	}
+	log.Printf("Indexed %d recipes into %s", n, *path)
+	return nil
+}
+
+// runImport upserts the recipes of a JSON or NDJSON file into the
+// configured store and prints a summary of the outcome. It refuses to run
+// without MONGO_URI, as the in-memory store is lost when it exits.
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report changes without writing them")
+	format := flags.String("format", "", "input format: json, ndjson or csv (default: from the file extension or content)")
+	defaultPublished := flags.String("default-published-at", "", "RFC 3339 publishedAt for records without one (default: now)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: recipes-api import [flags] file")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("import: expected exactly one input file")
+	}
+	path := flags.Arg(0)
+
+	opts := recipeio.ImportOptions{DryRun: *dryRun, DefaultPublishedAt: time.Now()}
+	if *defaultPublished != "" {
+		t, err := time.Parse(time.RFC3339, *defaultPublished)
+		if err != nil {
+			return fmt.Errorf("import: -default-published-at: %w", err)
+		}
+		opts.DefaultPublishedAt = t
+	}
+	inputFormat := recipeio.FormatFromPath(path)
+	if *format != "" {
+		f, err := recipeio.ParseFormat(*format)
+		if err != nil {
+			return fmt.Errorf("import: %w", err)
+		}
+		inputFormat = f
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rd, err := recipeio.NewReader(f, inputFormat)
+	if err != nil {
+		return err
+	}
+	backends, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	if backends.migrator == nil {
+		return errors.New("import: MONGO_URI is not set; recipes imported into the in-memory store would be lost on exit")
+	}
+	report, err := recipeio.Import(ctx, backends.recipes, rd, opts)
+	printImportReport(os.Stdout, report, opts.DryRun)
+	if closeErr := backends.close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func printImportReport(w io.Writer, report recipeio.ImportReport, dryRun bool) {
+	for _, recordErr := range report.Errors {
+		fmt.Fprintln(w, "invalid", recordErr.Error())
+	}
+	prefix := ""
+	if dryRun {
+		prefix = "dry run: "
+	}
+	fmt.Fprintf(w, "%screated %d, updated %d, skipped %d, invalid %d\n",
+		prefix, report.Created, report.Updated, report.Skipped, report.Invalid)
+}
+
+// runExport writes every recipe of the configured store to -o, or to
+// standard output when -o is "-". It refuses to run without MONGO_URI, as
+// the in-memory store starts out empty.
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("o", "-", "output file, - for standard output")
+	format := flags.String("format", "", "output format: json, ndjson or csv (default: from the -o extension, else json)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	outputFormat := recipeio.FormatFromPath(*output)
+	if *format != "" {
+		f, err := recipeio.ParseFormat(*format)
+		if err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		outputFormat = f
+	}
+	if outputFormat == "" {
+		outputFormat = recipeio.JSON
+	}
+
+	backends, err := newStore(ctx)
+	if err != nil {
+		return err
+	}
+	if backends.migrator == nil {
+		return errors.New("export: MONGO_URI is not set; the in-memory store has no recipes to export")
+	}
+	out := os.Stdout
+	if *output != "-" {
+		if out, err = os.Create(*output); err != nil {
+			return err
+		}
+	}
+	w, err := recipeio.NewWriter(out, outputFormat)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	n, err := recipeio.Export(ctx, backends.recipes, w, store.Filter{}, nil)
+	if out != os.Stdout {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("Exported %d recipes", n)
+	return nil
+}
+
+// openStore returns the configured stores, with the recipe store wrapped
+// with the embedded search index when SEARCH_INDEX_PATH is set so that
+// writes made by commands keep it in sync. Pending MongoDB migrations are
+// applied first unless MIGRATE_ON_START is false.
+func openStore(ctx context.Context) (stores, error) {
+	backends, err := newStore(ctx)
+	if err != nil {
+		return stores{}, err
+	}
+	changed := 0
+	if backends.migrator != nil && migrateOnStart() {
+		results, err := backends.migrator.Up(ctx, false)
+		for _, result := range results {
+			log.Printf("Applied migration %d %s to %d recipes", result.Version, result.Name, result.Changed)
+			changed += result.Changed
+		}
+		if err != nil {
+			return stores{}, err
+		}
+	}
+	if path := os.Getenv("SEARCH_INDEX_PATH"); path != "" {
+		if changed > 0 {
+			// The migrated recipes are indexed as they were; start over.
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return stores{}, err
+			}
+		}
+		if backends.index, err = openSearchIndex(ctx, backends.recipes, path); err != nil {
+			return stores{}, err
+		}
+		backends.recipes = searchindex.NewStore(backends.recipes, backends.index)
+	}
+	return backends, nil
+}
+
+// migrateOnStart reports whether openStore applies pending migrations,
+// which it does unless MIGRATE_ON_START is false.
+func migrateOnStart() bool {
+	on, err := strconv.ParseBool(os.Getenv("MIGRATE_ON_START"))
+	return err != nil || on
+}
+
+// openSearchIndex opens the embedded search index at path. A missing or
+// outdated index is rebuilt from backend first.
+func openSearchIndex(ctx context.Context, backend store.RecipeStore, path string) (*searchindex.Index, error) {
+	index, err := searchindex.Open(path)
+	if errors.Is(err, searchindex.ErrStaleIndex) {
+		log.Println("Search index is outdated, rebuilding")
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+		index, err = searchindex.Open(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if index.Len() == 0 {
+		n, err := searchindex.Rebuild(ctx, backend, index)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Indexed %d recipes into %s", n, path)
+	}
+	return index, nil
+}
+
+// runMigrate applies pending migrations (up), reverts applied ones (down)
+// or lists them (status) on the MongoDB recipes collection.
+func runMigrate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the recipes that would change without writing them")
+	steps := flags.Int("steps", 1, "number of migrations down reverts")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: recipes-api migrate up|down|status [flags]")
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("migrate: expected up, down or status")
+	}
+	action := args[0]
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *steps < 1 {
+		return errors.New("migrate: -steps must be positive")
+	}
+	backends, err := newStore(ctx)
+	if err != nil {
+		return err
+	}
+	if backends.migrator == nil {
+		return errors.New("migrate: MONGO_URI is not set; the in-memory store needs no migrations")
+	}
+
+	var results []migrations.Result
+	switch action {
+	case "status":
+		statuses, err := backends.migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(os.Stdout, statuses)
+		return nil
+	case "up":
+		results, err = backends.migrator.Up(ctx, *dryRun)
+	case "down":
+		results, err = backends.migrator.Down(ctx, *steps, *dryRun)
+	default:
+		flags.Usage()
+		return fmt.Errorf("migrate: unknown action %q", action)
+	}
+	printMigrationResults(os.Stdout, action, results, *dryRun)
+	if err == nil && len(results) > 0 && !*dryRun && os.Getenv("SEARCH_INDEX_PATH") != "" {
+		fmt.Println("run recipes-api reindex to update the search index")
+	}
+	return err
+}
+
+// maxListedIDs caps the recipe ids printed per migration.
+const maxListedIDs = 10
+
+func printMigrationResults(w io.Writer, action string, results []migrations.Result, dryRun bool) {
+	if len(results) == 0 {
+		fmt.Fprintf(w, "%s: nothing to do\n", action)
+		return
+	}
+	prefix := ""
+	if dryRun {
+		prefix = "dry run: "
+	}
+	for _, result := range results {
+		fmt.Fprintf(w, "%s%s %d %s: %d recipes\n", prefix, action, result.Version, result.Name, result.Changed)
+		ids := result.IDs
+		if len(ids) > maxListedIDs {
+			ids = ids[:maxListedIDs]
+		}
+		for _, id := range ids {
+			fmt.Fprintln(w, "  ", id)
+		}
+		if more := len(result.IDs) - len(ids); more > 0 {
+			fmt.Fprintf(w, "   and %d more\n", more)
+		}
+	}
+}
+
+func printMigrationStatus(w io.Writer, statuses []migrations.Status) {
+	for _, status := range statuses {
+		state := "pending"
+		if !status.AppliedAt.IsZero() {
+			state = fmt.Sprintf("applied %s, %d recipes", status.AppliedAt.Format(time.RFC3339), status.Changed)
+		}
+		fmt.Fprintf(w, "%4d %-26s %s\n", status.Version, status.Name, state)
+	}
+}
@@ -0,0 +1,75 @@
+// Package convert renders recipes in metric or US customary units,
+// converting both parsed ingredient amounts and temperatures mentioned in
+// free text such as "Preheat the oven to 475°F".
+package convert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+)
+
+// ParseSystem parses a units parameter: "metric", or "us" (also
+// "imperial" or "customary") for US customary units.
+func ParseSystem(name string) (ingredient.System, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "metric":
+		return ingredient.Metric, nil
+	case "us", "imperial", "customary":
+		return ingredient.USCustomary, nil
+	default:
+		return 0, fmt.Errorf("unknown unit system %q; expected metric or us", name)
+	}
+}
+
+// usCustomaryRegions are the regions that cook with US customary units.
+var usCustomaryRegions = map[string]bool{"US": true, "LR": true, "MM": true}
+
+// SystemForLanguage picks the unit system for an Accept-Language header
+// from its most preferred language: US customary for English without a
+// region or in the United States, Liberia or Myanmar, metric everywhere
+// else. It returns false for an empty or unusable header.
+func SystemForLanguage(header string) (ingredient.System, bool) {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if tag != "" && tag != "*" && q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	lang, region, _ := strings.Cut(strings.ReplaceAll(best, "_", "-"), "-")
+	region, _, _ = strings.Cut(region, "-")
+	switch {
+	case region != "":
+		if usCustomaryRegions[strings.ToUpper(region)] {
+			return ingredient.USCustomary, true
+		}
+		return ingredient.Metric, true
+	case strings.EqualFold(lang, "en"):
+		return ingredient.USCustomary, true
+	default:
+		return ingredient.Metric, true
+	}
+}
+
+// Ingredient converts the amounts of ing to sys and rewrites temperatures
+// in its item and note.
+func Ingredient(ing ingredient.Ingredient, sys ingredient.System) ingredient.Ingredient {
+	ing = ing.Convert(sys)
+	ing.Item = Temperatures(ing.Item, sys)
+	ing.Note = Temperatures(ing.Note, sys)
+	return ing
+}
@@ -0,0 +1,113 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+)
+
+// The inputs below are lines from recipes.json.
+
+func TestTemperatures(t *testing.T) {
+	tests := []struct {
+		text     string
+		sys      ingredient.System
+		expected string
+	}{
+		{"Preheat the oven to 475°F", ingredient.Metric, "Preheat the oven to 250°C"},
+		{"Preheat the oven to 375 or 400 degrees F", ingredient.Metric, "Preheat the oven to 190 or 200°C"},
+		{"Preheat your oven to 350°F (176°C), and lightly coat an individual sized ramekin or oven-safe bowl with cooking spray or oil", ingredient.Metric, "Preheat your oven to 180°C, and lightly coat an individual sized ramekin or oven-safe bowl with cooking spray or oil"},
+		{"Preheat oven to 450°F (230°C)", ingredient.USCustomary, "Preheat oven to 450°F (230°C)"},
+		{"\r\n\r\nFry one or two at a time in hot melted shortening (about 360°F) for approximately 1 minute, turning to brown all sides", ingredient.Metric, "\r\n\r\nFry one or two at a time in hot melted shortening (about 180°C) for approximately 1 minute, turning to brown all sides"},
+		{"\r\n\r\nBake in a preheated 350-degree oven for 25 minutes", ingredient.Metric, "\r\n\r\nBake in a preheated 180°C oven for 25 minutes"},
+		{" Bake in a preheated 350 degree F oven until lightly toasted, about 6 to 8 minutes Cool completely before using", ingredient.Metric, " Bake in a preheated 180°C oven until lightly toasted, about 6 to 8 minutes Cool completely before using"},
+		{"Bake potatoes at 400° for 1 hour", ingredient.Metric, "Bake potatoes at 200°C for 1 hour"},
+		{" Set the oven to 400° F and line a baking sheet with parchment", ingredient.Metric, " Set the oven to 200°C and line a baking sheet with parchment"},
+		{"  Bake until center of custard jiggles slightly when shaken and custard registers 180 degrees, 1 1/4 to 1 1/2 hours", ingredient.Metric, "  Bake until center of custard jiggles slightly when shaken and custard registers 82°C, 1 1/4 to 1 1/2 hours"},
+		{"105F°-115F°", ingredient.Metric, "41°C-46°C"},
+		{")\r\n\r\nRotate each sandwich 90 degrees and cut each sandwich into thirds", ingredient.Metric, ")\r\n\r\nRotate each sandwich 90 degrees and cut each sandwich into thirds"},
+		{"Preheat oven to 180°C", ingredient.USCustomary, "Preheat oven to 350°F"},
+		{"Preheat oven to 220 degrees C", ingredient.USCustomary, "Preheat oven to 425°F"},
+		{"Heat the milk to 40°C", ingredient.USCustomary, "Heat the milk to 104°F"},
+	}
+
+	for _, tt := range tests {
+		if got := Temperatures(tt.text, tt.sys); got != tt.expected {
+			t.Errorf("Temperatures(%q, %v) = %q, expected %q", tt.text, tt.sys, got, tt.expected)
+		}
+	}
+}
+
+func TestIngredient(t *testing.T) {
+	tests := []struct {
+		line     string
+		sys      ingredient.System
+		expected string
+	}{
+		{"1 1/2 cups (355 ml) warm water (105F°-115F°)", ingredient.Metric, "355 milliliters warm water, 41°C-46°C"},
+		{"1 1/2 cups (355 ml) warm water (105F°-115F°)", ingredient.USCustomary, "1 1/2 cups warm water, 105F°-115F°"},
+		{"3 3/4 cups (490 g) bread flour", ingredient.Metric, "490 grams bread flour"},
+		{"3 3/4 cups (490 g) bread flour", ingredient.USCustomary, "3 3/4 cups (1 1/8 pounds) bread flour"},
+		{"1/2 cup Parmesan cheese (about 2 ounces), grated\r", ingredient.Metric, "120 milliliters (57 grams) Parmesan cheese, grated"},
+		{"2 bunches kale (1 1/2 lbs), tough stems and ribs removed, leaves coarsely chopped\r", ingredient.Metric, "2 bunches (680 grams) kale, tough stems and ribs removed, leaves coarsely chopped"},
+		{"1/2 pound bacon, cut into large lardons", ingredient.Metric, "225 grams bacon, cut into large lardons"},
+		{"4 (6 to 7-ounce) boneless skinless chicken breasts\r", ingredient.Metric, "4 (170 to 200 grams each) boneless skinless chicken breasts"},
+		{"2 tbsp tomato paste\r", ingredient.Metric, "30 milliliters tomato paste"},
+		{"1/4 tsp salt\r", ingredient.Metric, "1 milliliter salt"},
+		{"2 lb Yukon gold potatoes, peeled and diced\r", ingredient.Metric, "905 grams Yukon gold potatoes, peeled and diced"},
+		{"4 cups chicken stock", ingredient.Metric, "945 milliliters chicken stock"},
+		{"2 quarts water", ingredient.Metric, "1.9 liters water"},
+		{"1 liter milk", ingredient.USCustomary, "4 1/4 cups milk"},
+		{"2 cloves garlic, minced", ingredient.Metric, "2 cloves garlic, minced"},
+		{"salt and pepper", ingredient.Metric, "salt and pepper"},
+	}
+
+	for _, tt := range tests {
+		if got := Ingredient(ingredient.Parse(tt.line), tt.sys).String(); got != tt.expected {
+			t.Errorf("Ingredient(%q, %v) = %q, expected %q", tt.line, tt.sys, got, tt.expected)
+		}
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected ingredient.System
+		wantErr  bool
+	}{
+		{name: "metric", expected: ingredient.Metric},
+		{name: "US", expected: ingredient.USCustomary},
+		{name: "imperial", expected: ingredient.USCustomary},
+		{name: "kelvin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSystem(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ParseSystem(%q) = %v, %v, expected %v", tt.name, got, err, tt.expected)
+		}
+	}
+}
+
+func TestSystemForLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected ingredient.System
+		ok       bool
+	}{
+		{"en-US,en;q=0.9", ingredient.USCustomary, true},
+		{"en", ingredient.USCustomary, true},
+		{"en-GB", ingredient.Metric, true},
+		{"es-CL,es;q=0.9,en-US;q=0.8", ingredient.Metric, true},
+		{"fr;q=0.5, en-US;q=0.8", ingredient.USCustomary, true},
+		{"*", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := SystemForLanguage(tt.header)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("SystemForLanguage(%q) = %v, %v, expected %v, %v", tt.header, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
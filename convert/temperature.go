@@ -0,0 +1,74 @@
+package convert
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+)
+
+// temperatureRe matches the ways the seed data writes temperatures:
+// "475°F", "400° F", "350 degrees F", "350-degree oven", "105F°" and
+// ranges such as "375 or 400 degrees F". A parenthesized equivalent, as in
+// "350°F (180°C)", is matched along with the temperature it restates.
+var temperatureRe = regexp.MustCompile(
+	`(?:(\d+)(\s*(?:-|–|to|or)\s*))?` +
+		`(\d+(?:\.\d+)?)` +
+		`(?:\s*[°º˚](?:\s*([FC])\b)?|([FC])[°º˚]|-?\s*degrees?(?:\s+(F|C|Fahrenheit|Celsius)\b)?)` +
+		`(?:\s*\(\s*(?:about\s+)?\d+(?:\.\d+)?\s*(?:[°º˚]|degrees?)?\s*[FC]\s*\))?`)
+
+// Temperatures rewrites the temperatures in text into the scale of sys,
+// Celsius for metric and Fahrenheit for US customary. Degrees without a
+// scale are taken as Fahrenheit from 100 up and otherwise left alone, so
+// that "rotate 90 degrees" survives.
+func Temperatures(text string, sys ingredient.System) string {
+	return temperatureRe.ReplaceAllStringFunc(text, func(match string) string {
+		m := temperatureRe.FindStringSubmatch(match)
+		value, _ := strconv.ParseFloat(m[3], 64)
+		scale := m[4] + m[5] + m[6]
+		if scale == "" {
+			if value < 100 {
+				return match
+			}
+			scale = "F"
+		}
+		from := scale[:1]
+		to := "F"
+		if sys == ingredient.Metric {
+			to = "C"
+		}
+		if from == to {
+			return match
+		}
+		converted := formatDegrees(convertDegrees(value, to))
+		if m[1] != "" {
+			low, _ := strconv.ParseFloat(m[1], 64)
+			converted = formatDegrees(convertDegrees(low, to)) + m[2] + converted
+		}
+		return converted + "°" + to
+	})
+}
+
+// convertDegrees converts value into the scale to from the other one,
+// rounding oven temperatures the way recipes print them: Celsius to 10
+// degrees above 100°C and Fahrenheit to 25 degrees above 200°F.
+func convertDegrees(value float64, to string) float64 {
+	if to == "C" {
+		c := (value - 32) * 5 / 9
+		if c >= 100 {
+			return math.Round(c/10) * 10
+		}
+		return math.Round(c)
+	}
+	f := value*9/5 + 32
+	if f >= 200 {
+		return math.Round(f/25) * 25
+	}
+	return math.Round(f)
+}
+
+func formatDegrees(v float64) string {
+	return strings.TrimSuffix(strconv.FormatFloat(v, 'f', 1, 64), ".0")
+}
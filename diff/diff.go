@@ -0,0 +1,117 @@
+// Package diff compares recipes field by field, with line diffs of their
+// tags, ingredients and instructions.
+package diff
+
+import (
+	"slices"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Op is what happened to a line.
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line is one line of a line diff. Old and New are its 1-based positions
+// in the old and new list; inserted lines have no Old and deleted lines no
+// New.
+type Line struct {
+	Op   Op     `json:"op" enums:"equal,insert,delete" example:"equal"`
+	Text string `json:"text" example:"Bake for 12 minutes."`
+	Old  int    `json:"old,omitempty" example:"2"`
+	New  int    `json:"new,omitempty" example:"3"`
+}
+
+// Lines returns the diff turning a into b that keeps the longest common
+// subsequence of lines, with deletions before insertions where lines
+// were replaced.
+func Lines(a, b []string) []Line {
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	lines := make([]Line, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, Line{Op: Equal, Text: a[i], Old: i + 1, New: j + 1})
+			i, j = i+1, j+1
+		case j == len(b) || (i < len(a) && common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, Line{Op: Delete, Text: a[i], Old: i + 1})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: b[j], New: j + 1})
+			j++
+		}
+	}
+	return lines
+}
+
+// Change is a field that differs between two recipes. Fields holding a
+// single value report it in From and To; tags, ingredients and
+// instructions report the line diff in Lines instead.
+type Change struct {
+	Field string `json:"field" example:"instructions"`
+	From  any    `json:"from,omitempty" swaggertype:"string" example:"Chocolate Cake"`
+	To    any    `json:"to,omitempty" swaggertype:"string" example:"Dark Chocolate Cake"`
+	Lines []Line `json:"lines,omitempty"`
+}
+
+// Recipes lists the fields that differ between a and b, in the order of
+// models.Recipe. The id, publishedAt and revision are not compared, as a
+// recipe keeps them across its revisions.
+func Recipes(a, b models.Recipe) []Change {
+	changes := make([]Change, 0)
+	if a.Name != b.Name {
+		changes = append(changes, Change{Field: "name", From: a.Name, To: b.Name})
+	}
+	for _, list := range []struct {
+		field    string
+		from, to []string
+	}{
+		{"tags", a.Tags, b.Tags},
+		{"ingredients", a.Ingredients, b.Ingredients},
+		{"instructions", a.Instructions, b.Instructions},
+	} {
+		if !slices.Equal(list.from, list.to) {
+			changes = append(changes, Change{Field: list.field, Lines: Lines(list.from, list.to)})
+		}
+	}
+	for _, field := range []struct {
+		name     string
+		from, to any
+	}{
+		{"servings", a.Servings, b.Servings},
+		{"yield", a.Yield, b.Yield},
+		{"prepTime", string(a.PrepTime), string(b.PrepTime)},
+		{"cookTime", string(a.CookTime), string(b.CookTime)},
+		{"totalTime", string(a.TotalTime), string(b.TotalTime)},
+		{"difficulty", string(a.Difficulty), string(b.Difficulty)},
+		{"cuisine", a.Cuisine, b.Cuisine},
+		{"author", a.Author, b.Author},
+		{"sourceUrl", a.SourceURL, b.SourceURL},
+		{"attribution", a.Attribution, b.Attribution},
+	} {
+		if field.from != field.to {
+			changes = append(changes, Change{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// render writes a line diff in the style of a unified diff body.
+func render(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		switch line.Op {
+		case Equal:
+			b.WriteString(" ")
+		case Insert:
+			b.WriteString("+")
+		case Delete:
+			b.WriteString("-")
+		}
+		b.WriteString(line.Text + "\n")
+	}
+	return b.String()
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []string
+		expected string
+	}{
+		{"Equal", []string{"a", "b"}, []string{"a", "b"}, " a\n b\n"},
+		{"Empty", nil, nil, ""},
+		{"All inserted", nil, []string{"a", "b"}, "+a\n+b\n"},
+		{"All deleted", []string{"a"}, nil, "-a\n"},
+		{"Replaced", []string{"a", "b", "c"}, []string{"a", "x", "c"}, " a\n-b\n+x\n c\n"},
+		{"Inserted and deleted", []string{"a", "b", "c", "d"}, []string{"b", "c", "e", "d"}, "-a\n b\n c\n+e\n d\n"},
+		{"Moved", []string{"a", "b", "c"}, []string{"c", "a", "b"}, "+c\n a\n b\n-c\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(Lines(tt.a, tt.b)); got != tt.expected {
+				t.Errorf("Lines() =\n%s\nexpected\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLines_Positions(t *testing.T) {
+	got := Lines([]string{"a", "b"}, []string{"x", "b", "c"})
+	expected := []Line{
+		{Op: Delete, Text: "a", Old: 1},
+		{Op: Insert, Text: "x", New: 1},
+		{Op: Equal, Text: "b", Old: 2, New: 2},
+		{Op: Insert, Text: "c", New: 3},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lines() = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestRecipes(t *testing.T) {
+	a := models.Recipe{
+		ID:           "a",
+		Name:         "Pizza",
+		Tags:         models.Tags{"italian"},
+		Ingredients:  models.Ingredients{"dough", "cheese"},
+		Instructions: models.Instructions{"prepare dough", "bake"},
+		Servings:     2,
+		Revision:     1,
+	}
+	b := a
+	b.Revision = 2
+	b.Instructions = models.Instructions{"prepare dough", "add toppings", "bake"}
+	b.Servings = 4
+	b.Difficulty = models.Easy
+
+	got := Recipes(a, b)
+	var fields []string
+	for _, change := range got {
+		fields = append(fields, change.Field)
+	}
+	if strings.Join(fields, ",") != "instructions,servings,difficulty" {
+		t.Fatalf("Recipes() fields = %v, expected instructions, servings and difficulty", fields)
+	}
+	if render(got[0].Lines) != " prepare dough\n+add toppings\n bake\n" {
+		t.Errorf("Recipes() instructions =\n%s", render(got[0].Lines))
+	}
+	if got[1].From != 2 || got[1].To != 4 {
+		t.Errorf("Recipes() servings = %v -> %v, expected 2 -> 4", got[1].From, got[1].To)
+	}
+	if got[2].From != "" || got[2].To != "easy" {
+		t.Errorf("Recipes() difficulty = %q -> %q, expected easy", got[2].From, got[2].To)
+	}
+	if changes := Recipes(a, a); len(changes) != 0 {
+		t.Errorf("Recipes() of equal recipes = %+v, expected none", changes)
+	}
+}
@@ -24,9 +24,44 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/recipes": {
+        "/mealplans": {
             "get": {
-                "description": "Return a recipes list",
+                "description": "Return the meal plans of an owner, or of everyone, ordered by start date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mealplans"
+                ],
+                "summary": "Operation GET /mealplans mealplans.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only plans of this owner",
+                        "name": "owner",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MealPlan"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new meal plan covering at most 31 days. Every planned recipe must exist.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,14 +69,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "mealplans"
+                ],
+                "summary": "Operation POST /mealplans mealplans.",
+                "parameters": [
+                    {
+                        "description": "Add meal plan",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlan"
+                        }
+                    }
                 ],
-                "summary": "Operation GET /recipes returns a list of recipes.",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/models.MealPlan"
                         }
                     },
                     "400": {
@@ -50,6 +96,47 @@ const docTemplate = `{
                             "$ref": "#/definitions/httputil.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/mealplans/{id}": {
+            "get": {
+                "description": "Return a single meal plan. Meals whose recipe has since been deleted are flagged with recipeDeleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mealplans"
+                ],
+                "summary": "Operation GET /mealplans/{id} mealplans.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlan"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -64,8 +151,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Add a new recipe.",
+            "put": {
+                "description": "Replace the owner, name, dates and meals of a meal plan. Every planned recipe must exist, so meals\nflagged with recipeDeleted have to be replaced or removed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -73,17 +160,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "mealplans"
                 ],
-                "summary": "Operation POST /recipes recipes.",
+                "summary": "Operation PUT /mealplans/{id} mealplans.",
                 "parameters": [
                     {
-                        "description": "Add recipe",
-                        "name": "models.Recipe",
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update meal plan",
+                        "name": "plan",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/models.MealPlan"
                         }
                     }
                 ],
@@ -91,7 +185,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/models.MealPlan"
                         }
                     },
                     "400": {
@@ -106,6 +200,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/httputil.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a meal plan. The planned recipes are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mealplans"
+                ],
+                "summary": "Operation DELETE /mealplans/{id} mealplans.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -115,40 +251,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/recipes/search": {
+        "/mealplans/{id}/calendar.ics": {
             "get": {
-                "description": "Search an existing recipe.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Export a meal plan as an iCalendar (RFC 5545) file. Every meal becomes an event at the usual time of its\nslot, titled with the recipe name, linking to the recipe and describing its ingredients, rescaled to the\nmeal's servings, and instructions. Meals whose recipe was deleted are left out.",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "recipes"
+                    "mealplans"
                 ],
-                "summary": "Operation Search Recipe GET /recipes/search?={tag} recipes.",
+                "summary": "Operation GET /mealplans/{id}/calendar.ics mealplans.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tag Recipe",
-                        "name": "tag",
-                        "in": "query",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "iCalendar file",
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/httputil.HTTPError"
                         }
+                    }
+                }
+            }
+        },
+        "/mealplans/{id}/nutrition": {
+            "get": {
+                "description": "Estimate the nutrients one person eats on each day of a meal plan, counting one serving of every meal,\nand the average over the days with estimated meals. Meals of recipes that do not give their servings\nare listed without an estimate.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mealplans"
+                ],
+                "summary": "Operation GET /mealplans/{id}/nutrition mealplans.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mealplan.Nutrition"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -165,42 +333,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/recipes/{id}": {
-            "put": {
-                "description": "Update an existing recipe.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/mealplans/{id}/shopping-list": {
+            "get": {
+                "description": "Build the shopping list of a meal plan, with every meal rescaled to its servings. Meals whose recipe\nwas deleted are left out.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain",
+                    "text/markdown"
                 ],
                 "tags": [
-                    "recipes"
+                    "mealplans"
                 ],
-                "summary": "Operation PUT /recipes/{id} recipes.",
+                "summary": "Operation GET /mealplans/{id}/shopping-list mealplans.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Recipe ID",
+                        "description": "Meal plan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update recipe",
-                        "name": "models.Recipe",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Recipe"
-                        }
+                        "enum": [
+                            "json",
+                            "text",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "us"
+                        ],
+                        "type": "string",
+                        "description": "Unit system to total amounts in",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Picks the unit system when units is not given",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/shopping.List"
                         }
                     },
                     "400": {
@@ -215,6 +401,53 @@ const docTemplate = `{
                             "$ref": "#/definitions/httputil.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pantries/{owner}": {
+            "get": {
+                "description": "Return the ingredients an owner has on hand and the ingredients they exclude.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pantries"
+                ],
+                "summary": "Operation GET /pantries/{owner} pantries.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pantry owner",
+                        "name": "owner",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pantry"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -223,8 +456,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete an existing recipe.",
+            "put": {
+                "description": "Create or replace the pantry of an owner. The owner in the body, if any, is ignored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -232,33 +465,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "pantries"
                 ],
-                "summary": "Operation DELETE /recipes/{id} recipes.",
+                "summary": "Operation PUT /pantries/{owner} pantries.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Recipe ID",
-                        "name": "id",
+                        "description": "Pantry owner",
+                        "name": "owner",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Ingredients on hand",
+                        "name": "pantry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Pantry"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/models.Pantry"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/httputil.HTTPError"
+                            "$ref": "#/definitions/models.Pantry"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/httputil.HTTPError"
                         }
@@ -270,30 +512,2358 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "httputil.HTTPError": {
-            "type": "object",
-            "properties": {
+            },
+            "delete": {
+                "description": "Delete the pantry of an owner.",
+                "tags": [
+                    "pantries"
+                ],
+                "summary": "Operation DELETE /pantries/{owner} pantries.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pantry owner",
+                        "name": "owner",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes": {
+            "get": {
+                "description": "Return a page of filtered recipes. Pass the returned next_cursor, with the same sort, to fetch the following page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes returns a list of recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags, -tag to exclude",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match any (default) or all of the tags",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name contains (case-insensitive)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "An ingredient contains (case-insensitive)",
+                        "name": "ingredient",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Published at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "published_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Published at or before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "published_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "easy",
+                            "medium",
+                            "hard"
+                        ],
+                        "type": "string",
+                        "description": "Difficulty",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cuisine (case-insensitive)",
+                        "name": "cuisine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author contains (case-insensitive)",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total time at most, as an ISO 8601 duration like PT30M; recipes without a total time are left out",
+                        "name": "max_total_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "publishedAt",
+                            "-publishedAt",
+                            "name",
+                            "-name"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new recipe. Times are ISO 8601 durations; a missing totalTime is the sum of prepTime and\ncookTime, and a missing cuisine is taken from a tag naming one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation POST /recipes recipes.",
+                "parameters": [
+                    {
+                        "description": "Add recipe",
+                        "name": "models.Recipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the change, recorded in the history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the recipe"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/batch": {
+            "post": {
+                "description": "Create, update and delete recipes in one request. Each operation is validated and answered like the\nsingle request would be, with the status and error in its result.\nBy default the operations are applied one after the other and the ones that fail are skipped; the\nresponse is 200 when all succeeded and 207 otherwise. With atomic=true they are applied in a\ntransaction that stops at the first failure: nothing is written, the other operations are answered\nwith 424 and the response is 409, or the status of the failure when the server failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation POST /recipes/batch recipes.",
+                "parameters": [
+                    {
+                        "description": "Operations to apply",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Apply all operations or none",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the changes, recorded in the history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some operations failed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "An operation failed and the batch was rolled back",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/export": {
+            "get": {
+                "description": "Stream every recipe matching the list filters as JSON, NDJSON or CSV. The CSV layout flattens\ntags, ingredients and instructions into \"|\"-separated cells and can be imported back unchanged.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/export recipes.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags, -tag to exclude",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match any (default) or all of the tags",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name contains (case-insensitive)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "An ingredient contains (case-insensitive)",
+                        "name": "ingredient",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Published at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "published_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Published at or before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "published_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "easy",
+                            "medium",
+                            "hard"
+                        ],
+                        "type": "string",
+                        "description": "Difficulty",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cuisine (case-insensitive)",
+                        "name": "cuisine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author contains (case-insensitive)",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total time at most, as an ISO 8601 duration like PT30M",
+                        "name": "max_total_time",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/search": {
+            "get": {
+                "description": "Search recipes by tags. Repeat tag or separate values with commas; prefix a tag with \"-\" to exclude it.\nWhen q is given, run a full-text search over name, ingredients and instructions instead and return\nhandlers.SearchResult items ordered by relevance. Quote words in q to search for a phrase.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation Search Recipe GET /recipes/search?={tag} recipes.",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags to match, -tag to exclude",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match any (default) or all of the tags",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full-text query; quote words to match a phrase",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum full-text results (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/search/ingredients": {
+            "get": {
+                "description": "Score recipes by the share of the included ingredients they use, highest first, returning the\nmatched ingredients of each. Names are compared word by word in singular, so \"chicken\" matches\n\"2 chicken breasts\" and \"lemons\" matches \"1 lemon\". Recipes with an excluded ingredient or none of\nthe included ones are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/search/ingredients recipes.",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Ingredients to look for",
+                        "name": "include",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Ingredients the recipes must not use",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum results (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.IngredientMatch"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/search/pantry": {
+            "get": {
+                "description": "Rank recipes by the share of their ingredients covered by what is on hand, listing the missing\ningredient lines of each. The items on hand are those of the owner's pantry and the have parameter;\nwater, salt and black pepper count as on hand unless staples is false. Recipes with an excluded\ningredient are left out. Exclusions come from the pantry and the exclude parameter, written as \"nuts\",\n\"no nuts\" or \"-nuts\"; nuts, pork, meat, fish, shellfish, seafood, dairy, egg and gluten cover their\nusual ingredients. Only recipes with at least one covered ingredient are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/search/pantry recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use the pantry of this owner",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "More ingredients on hand",
+                        "name": "have",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Ingredients to exclude",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Count water, salt and black pepper as on hand",
+                        "name": "staples",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only recipes missing at most this many ingredients",
+                        "name": "max_missing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum results (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.PantryMatch"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/trash": {
+            "get": {
+                "description": "List the deleted recipes, most recently deleted first. They stay in the trash, with the date they were\ndeleted, until they are restored or purged once the retention period has passed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/trash recipes.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}": {
+            "get": {
+                "description": "Return a single recipe by its ID, with each ingredient line also parsed into quantity, unit, item and note.\nWith servings, ingredient amounts are rescaled from the servings the recipe makes, converted to fitting units and rounded to kitchen fractions.\nWith units, amounts and temperatures are rendered in metric or US customary units.\nThe ETag is strong for the recipe as stored and weak with servings or units. If-Match only accepts the\nstrong one, so updates must send the ETag of a plain GET.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/{id} recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rescale the recipe to this many servings",
+                        "name": "servings",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "us"
+                        ],
+                        "type": "string",
+                        "description": "Unit system to render the recipe in",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeDetail"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the recipe, to send back in If-Match; weak when rescaled or converted"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing recipe. Times are ISO 8601 durations; a missing totalTime is the sum of prepTime and\ncookTime, and a missing cuisine is taken from a tag naming one.\nSend the ETag of the recipe as read in If-Match to have the update rejected with 412 when someone\nelse changed it in between. The revision in the body is ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation PUT /recipes/{id} recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the recipe must still have, as returned by GET",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Update recipe",
+                        "name": "models.Recipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the change, recorded in the history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated recipe"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing recipe. It is moved to the trash, from where it can be restored until it is purged.\nMeal plans that planned it are kept, with the affected meals flagged as recipeDeleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation DELETE /recipes/{id} recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change part of a recipe with a JSON Merge Patch (RFC 7386) sent as application/merge-patch+json, or a\nJSON Patch (RFC 6902) sent as application/json-patch+json, applied to the recipe as GET returns it\nwithout parsedIngredients. The patched recipe must be valid, and its id, publishedAt and revision\ncannot change. Either the whole patch is applied or nothing is. Send If-Match, or a JSON Patch test\nof /revision, to have the patch rejected when someone else changed the recipe since it was read.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation PATCH /recipes/{id} recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the recipe must still have, as returned by GET",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch object or array of JSON Patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the change, recorded in the history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the patched recipe"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "A JSON Patch test failed",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "The patch cannot be applied or makes the recipe invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/nutrition": {
+            "get": {
+                "description": "Estimate calories, protein, fat, carbohydrates and sodium of a recipe, in total and per serving when the recipe gives its servings.\nIngredients are matched against an offline nutrient table; lines without a match, or whose amount cannot be weighed, are listed separately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/{id}/nutrition recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/nutrition.Estimate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/restore": {
+            "post": {
+                "description": "Take a recipe out of the trash. Meals planned with it are no longer flagged as recipeDeleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation POST /recipes/{id}/restore recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the recipe"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/revisions": {
+            "get": {
+                "description": "List the recorded revisions of a recipe, newest first, without their recipe. Every create, update,\npatch and restore through the API records one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/{id}/revisions recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecipeRevision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/revisions/{rev}": {
+            "get": {
+                "description": "Return one revision of a recipe, with the recipe as that write stored it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/{id}/revisions/{rev} recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/revisions/{rev}/diff": {
+            "get": {
+                "description": "Compare a revision of a recipe with an earlier one, field by field. Tags, ingredients and instructions\nare compared line by line. Without from, the revision is compared with the one recorded before it, or\nwith an empty recipe when there is none.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation GET /recipes/{id}/revisions/{rev}/diff recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to compare",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to compare with",
+                        "name": "from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevisionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/revisions/{rev}/restore": {
+            "post": {
+                "description": "Make a recipe what it was at an earlier revision. The restore is a new revision; the ones in between\nstay in the history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Operation POST /recipes/{id}/revisions/{rev}/restore recipes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to restore",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the recipe must still have, as returned by GET",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the change, recorded in the history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the restored recipe"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/shopping-list": {
+            "post": {
+                "description": "Build one shopping list from several recipes, optionally rescaled. The same ingredient is merged across\nrecipes, volumes and weights are added up after unit conversion, and items are grouped by store aisle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "shopping"
+                ],
+                "summary": "Operation POST /shopping-list shopping.",
+                "parameters": [
+                    {
+                        "description": "Recipes to shop for",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShoppingListRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/shopping.List"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{owner}/calendar": {
+            "get": {
+                "description": "Return the URL of the calendar feed holding every meal plan of an owner, for calendar apps to subscribe to.\nThe URL carries a token signed for the owner; it stays valid as long as the server's calendar secret does.\nThe API does not authenticate callers, so anyone reaching this endpoint gets the token of any owner;\nexpose it only behind a proxy that lets users ask for their own link alone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mealplans"
+                ],
+                "summary": "Operation GET /users/{owner}/calendar mealplans.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan owner",
+                        "name": "owner",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CalendarLink"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{owner}/mealplans.ics": {
+            "get": {
+                "description": "Subscribable iCalendar (RFC 5545) feed of every meal plan of an owner, with events as in\n/mealplans/{id}/calendar.ics. The token must be the one returned by /users/{owner}/calendar.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "mealplans"
+                ],
+                "summary": "Operation GET /users/{owner}/mealplans.ics mealplans.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan owner",
+                        "name": "owner",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signed calendar token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "diff.Change": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "instructions"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "Chocolate Cake"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Line"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "Dark Chocolate Cake"
+                }
+            }
+        },
+        "diff.Line": {
+            "type": "object",
+            "properties": {
+                "new": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "old": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "op": {
+                    "enum": [
+                        "equal",
+                        "insert",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/diff.Op"
+                        }
+                    ],
+                    "example": "equal"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Bake for 12 minutes."
+                }
+            }
+        },
+        "diff.Op": {
+            "type": "string",
+            "enum": [
+                "equal",
+                "insert",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "Equal",
+                "Insert",
+                "Delete"
+            ]
+        },
+        "handlers.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "example": "update"
+                },
+                "recipe": {
+                    "$ref": "#/definitions/models.Recipe"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchOperation"
+                    }
+                }
+            }
+        },
+        "handlers.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchResult"
+                    }
+                }
+            }
+        },
+        "handlers.BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/httputil.HTTPError"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "handlers.CalendarLink": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "3q2-7w"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8080/api/v1/users/maria/mealplans.ics?token=3q2-7w"
+                }
+            }
+        },
+        "handlers.IngredientMatch": {
+            "type": "object",
+            "properties": {
+                "attribution": {
+                    "type": "string",
+                    "example": "Adapted from Grandma's recipe box"
+                },
+                "author": {
+                    "type": "string",
+                    "example": "Maria Rojas"
+                },
+                "cookTime": {
+                    "type": "string",
+                    "example": "PT45M"
+                },
+                "cuisine": {
+                    "type": "string",
+                    "example": "italian"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set while the recipe is in the trash.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-03-01T09:30:00Z"
+                },
+                "difficulty": {
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Difficulty"
+                        }
+                    ],
+                    "example": "easy"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ingredient1",
+                        "ingredient2"
+                    ]
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "instruction1",
+                        "instruction2"
+                    ]
+                },
+                "matched": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "chicken"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Chocolate Cake"
+                },
+                "prepTime": {
+                    "type": "string",
+                    "example": "PT20M"
+                },
+                "publishedAt": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "revision": {
+                    "description": "Revision counts the writes to the recipe, starting at 1. The store\nmaintains it; the API exposes it as the ETag.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "sourceUrl": {
+                    "type": "string",
+                    "example": "https://example.com/chocolate-cake"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dessert",
+                        "sweet"
+                    ]
+                },
+                "totalTime": {
+                    "type": "string",
+                    "example": "PT1H5M"
+                },
+                "yield": {
+                    "type": "string",
+                    "example": "1 loaf"
+                }
+            }
+        },
+        "handlers.PantryMatch": {
+            "type": "object",
+            "properties": {
+                "attribution": {
+                    "type": "string",
+                    "example": "Adapted from Grandma's recipe box"
+                },
+                "author": {
+                    "type": "string",
+                    "example": "Maria Rojas"
+                },
+                "cookTime": {
+                    "type": "string",
+                    "example": "PT45M"
+                },
+                "covered": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "cuisine": {
+                    "type": "string",
+                    "example": "italian"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set while the recipe is in the trash.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-03-01T09:30:00Z"
+                },
+                "difficulty": {
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Difficulty"
+                        }
+                    ],
+                    "example": "easy"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ingredient1",
+                        "ingredient2"
+                    ]
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "instruction1",
+                        "instruction2"
+                    ]
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Chocolate Cake"
+                },
+                "prepTime": {
+                    "type": "string",
+                    "example": "PT20M"
+                },
+                "publishedAt": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "revision": {
+                    "description": "Revision counts the writes to the recipe, starting at 1. The store\nmaintains it; the API exposes it as the ETag.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.75
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "sourceUrl": {
+                    "type": "string",
+                    "example": "https://example.com/chocolate-cake"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dessert",
+                        "sweet"
+                    ]
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "totalTime": {
+                    "type": "string",
+                    "example": "PT1H5M"
+                },
+                "yield": {
+                    "type": "string",
+                    "example": "1 loaf"
+                }
+            }
+        },
+        "handlers.RecipeDetail": {
+            "type": "object",
+            "properties": {
+                "attribution": {
+                    "type": "string",
+                    "example": "Adapted from Grandma's recipe box"
+                },
+                "author": {
+                    "type": "string",
+                    "example": "Maria Rojas"
+                },
+                "cookTime": {
+                    "type": "string",
+                    "example": "PT45M"
+                },
+                "cuisine": {
+                    "type": "string",
+                    "example": "italian"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set while the recipe is in the trash.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-03-01T09:30:00Z"
+                },
+                "difficulty": {
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Difficulty"
+                        }
+                    ],
+                    "example": "easy"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ingredient1",
+                        "ingredient2"
+                    ]
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "instruction1",
+                        "instruction2"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Chocolate Cake"
+                },
+                "parsedIngredients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ingredient.Ingredient"
+                    }
+                },
+                "prepTime": {
+                    "type": "string",
+                    "example": "PT20M"
+                },
+                "publishedAt": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "revision": {
+                    "description": "Revision counts the writes to the recipe, starting at 1. The store\nmaintains it; the API exposes it as the ETag.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "scaledFrom": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "sourceUrl": {
+                    "type": "string",
+                    "example": "https://example.com/chocolate-cake"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dessert",
+                        "sweet"
+                    ]
+                },
+                "totalTime": {
+                    "type": "string",
+                    "example": "PT1H5M"
+                },
+                "units": {
+                    "type": "string",
+                    "enum": [
+                        "metric",
+                        "us"
+                    ]
+                },
+                "yield": {
+                    "type": "string",
+                    "example": "1 loaf"
+                }
+            }
+        },
+        "handlers.RecipeList": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJwIjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjoiYWJjIn0"
+                },
+                "recipes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Recipe"
+                    }
+                }
+            }
+        },
+        "handlers.RevisionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Change"
+                    }
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "recipeId": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.ShoppingListRecipe": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 6
+                }
+            }
+        },
+        "handlers.ShoppingListRequest": {
+            "type": "object",
+            "properties": {
+                "recipes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ShoppingListRecipe"
+                    }
+                },
+                "units": {
+                    "type": "string",
+                    "enum": [
+                        "metric",
+                        "us"
+                    ]
+                }
+            }
+        },
+        "httputil.HTTPError": {
+            "type": "object",
+            "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 400
+                    "example": 400
+                },
+                "message": {
+                    "type": "string",
+                    "example": "status bad request"
+                }
+            }
+        },
+        "ingredient.Ingredient": {
+            "type": "object",
+            "properties": {
+                "alternate": {
+                    "$ref": "#/definitions/ingredient.Measure"
+                },
+                "item": {
+                    "type": "string",
+                    "example": "warm water"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "105F°-115F°"
+                },
+                "quantity": {
+                    "$ref": "#/definitions/ingredient.Quantity"
+                },
+                "raw": {
+                    "type": "string",
+                    "example": "1 1/2 cups (355 ml) warm water (105F°-115F°)"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "cup"
+                },
+                "unparsed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "ingredient.Measure": {
+            "type": "object",
+            "properties": {
+                "each": {
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "$ref": "#/definitions/ingredient.Quantity"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "milliliter"
+                }
+            }
+        },
+        "ingredient.Quantity": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number",
+                    "example": 2
+                },
+                "value": {
+                    "type": "number",
+                    "example": 1.5
+                }
+            }
+        },
+        "mealplan.DayNutrition": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-03-04"
+                },
+                "meals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mealplan.MealNutrition"
+                    }
+                },
+                "nutrients": {
+                    "$ref": "#/definitions/nutrition.Nutrients"
+                }
+            }
+        },
+        "mealplan.MealNutrition": {
+            "type": "object",
+            "properties": {
+                "perServing": {
+                    "$ref": "#/definitions/nutrition.Nutrients"
+                },
+                "recipe": {
+                    "type": "string",
+                    "example": "Homemade Pizza"
+                },
+                "recipeDeleted": {
+                    "type": "boolean"
+                },
+                "recipeId": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "slot": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Slot"
+                        }
+                    ],
+                    "example": "dinner"
+                },
+                "unestimated": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "mealplan.Nutrition": {
+            "type": "object",
+            "properties": {
+                "dailyAverage": {
+                    "$ref": "#/definitions/nutrition.Nutrients"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mealplan.DayNutrition"
+                    }
+                }
+            }
+        },
+        "models.Difficulty": {
+            "type": "string",
+            "enum": [
+                "easy",
+                "medium",
+                "hard"
+            ],
+            "x-enum-varnames": [
+                "Easy",
+                "Medium",
+                "Hard"
+            ]
+        },
+        "models.Meal": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-03-04"
+                },
+                "recipeDeleted": {
+                    "type": "boolean"
+                },
+                "recipeId": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 4
                 },
-                "message": {
+                "slot": {
+                    "enum": [
+                        "breakfast",
+                        "lunch",
+                        "dinner",
+                        "snack"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Slot"
+                        }
+                    ],
+                    "example": "dinner"
+                }
+            }
+        },
+        "models.MealPlan": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
                     "type": "string",
-                    "example": "status bad request"
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "endDate": {
+                    "type": "string",
+                    "example": "2024-03-10"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "meals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Meal"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "First week of March"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "maria"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2024-03-04"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                }
+            }
+        },
+        "models.Pantry": {
+            "type": "object",
+            "properties": {
+                "exclude": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "no nuts"
+                    ]
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PantryItem"
+                    }
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "maria"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                }
+            }
+        },
+        "models.PantryItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "2 pounds"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "chicken breasts"
                 }
             }
         },
         "models.Recipe": {
             "type": "object",
             "properties": {
-                "id": {
+                "attribution": {
+                    "type": "string",
+                    "example": "Adapted from Grandma's recipe box"
+                },
+                "author": {
+                    "type": "string",
+                    "example": "Maria Rojas"
+                },
+                "cookTime": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "PT45M"
+                },
+                "cuisine": {
+                    "type": "string",
+                    "example": "italian"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set while the recipe is in the trash.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-03-01T09:30:00Z"
+                },
+                "difficulty": {
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Difficulty"
+                        }
+                    ],
+                    "example": "easy"
+                },
+                "id": {
+                    "type": "string"
                 },
                 "ingredients": {
                     "type": "array",
@@ -317,21 +2887,263 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string",
-                    "example": "Recipe name"
+                    "example": "Chocolate Cake"
+                },
+                "prepTime": {
+                    "type": "string",
+                    "example": "PT20M"
                 },
                 "publishedAt": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
                 },
+                "revision": {
+                    "description": "Revision counts the writes to the recipe, starting at 1. The store\nmaintains it; the API exposes it as the ETag.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "sourceUrl": {
+                    "type": "string",
+                    "example": "https://example.com/chocolate-cake"
+                },
                 "tags": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "value1",
-                        "value2"
+                        "dessert",
+                        "sweet"
+                    ]
+                },
+                "totalTime": {
+                    "type": "string",
+                    "example": "PT1H5M"
+                },
+                "yield": {
+                    "type": "string",
+                    "example": "1 loaf"
+                }
+            }
+        },
+        "models.RecipeRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "create",
+                        "update",
+                        "restore"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RevisionAction"
+                        }
+                    ],
+                    "example": "update"
+                },
+                "changedAt": {
+                    "type": "string",
+                    "example": "2024-03-01T09:30:00Z"
+                },
+                "changedBy": {
+                    "type": "string",
+                    "example": "maria"
+                },
+                "recipe": {
+                    "description": "Recipe is left out of revision lists.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
                     ]
+                },
+                "recipeId": {
+                    "type": "string",
+                    "example": "600dcc85a65917cbd1f201b0"
+                },
+                "restoredFrom": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.RevisionAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "restore"
+            ],
+            "x-enum-varnames": [
+                "RevisionCreated",
+                "RevisionUpdated",
+                "RevisionRestored"
+            ]
+        },
+        "models.Slot": {
+            "type": "string",
+            "enum": [
+                "breakfast",
+                "lunch",
+                "dinner",
+                "snack"
+            ],
+            "x-enum-varnames": [
+                "Breakfast",
+                "Lunch",
+                "Dinner",
+                "Snack"
+            ]
+        },
+        "nutrition.Estimate": {
+            "type": "object",
+            "properties": {
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nutrition.IngredientNutrition"
+                    }
+                },
+                "perServing": {
+                    "$ref": "#/definitions/nutrition.Nutrients"
+                },
+                "servings": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "total": {
+                    "$ref": "#/definitions/nutrition.Nutrients"
+                },
+                "unmatched": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unmeasured": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "nutrition.IngredientNutrition": {
+            "type": "object",
+            "properties": {
+                "food": {
+                    "type": "string",
+                    "example": "bread flour"
+                },
+                "grams": {
+                    "type": "number",
+                    "example": 490
+                },
+                "ingredient": {
+                    "type": "string",
+                    "example": "3 3/4 cups (490 g) bread flour"
+                },
+                "nutrients": {
+                    "$ref": "#/definitions/nutrition.Nutrients"
+                }
+            }
+        },
+        "nutrition.Nutrients": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "number",
+                    "example": 412.5
+                },
+                "carbohydrates": {
+                    "type": "number",
+                    "example": 35.4
+                },
+                "fat": {
+                    "type": "number",
+                    "example": 21
+                },
+                "protein": {
+                    "type": "number",
+                    "example": 18.2
+                },
+                "sodium": {
+                    "type": "number",
+                    "example": 780
+                }
+            }
+        },
+        "shopping.Aisle": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/shopping.Item"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "produce"
+                }
+            }
+        },
+        "shopping.Amount": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "number",
+                    "example": 2.25
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "cup"
+                }
+            }
+        },
+        "shopping.Item": {
+            "type": "object",
+            "properties": {
+                "amounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/shopping.Amount"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "all-purpose flour"
+                },
+                "recipes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string",
+                    "example": "2 1/4 cups all-purpose flour"
+                }
+            }
+        },
+        "shopping.List": {
+            "type": "object",
+            "properties": {
+                "aisles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/shopping.Aisle"
+                    }
                 }
             }
         }
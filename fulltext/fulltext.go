@@ -0,0 +1,230 @@
+// Package fulltext implements the query language, relevance scoring and
+// highlighting of recipe full-text search.
+//
+// A query is a list of terms, prefixes ending in "*" and double-quoted
+// phrases, e.g.
+//
+//	mozzarella "stand mixer" oreg*
+//
+// A recipe matches when it contains every phrase and, if the query has
+// terms or prefixes, at least one of them, mirroring MongoDB $text
+// semantics. Matches in the name weigh more than matches in ingredients,
+// which weigh more than matches in instructions.
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Field weights used by Score and by the MongoDB text index.
+const (
+	NameWeight         = 10
+	IngredientsWeight  = 5
+	InstructionsWeight = 1
+)
+
+// Query is a parsed full-text query. Terms and phrase words are lower
+// case and singular, as with ingredient.Singular; prefixes and RawPhrases,
+// the phrases as written, are only lower-cased.
+type Query struct {
+	Terms      []string
+	Prefixes   []string
+	Phrases    [][]string
+	RawPhrases [][]string
+}
+
+// stopWords are ignored as standalone terms since they match nearly every
+// recipe.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "in": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// Parse splits q into terms and double-quoted phrases. An unterminated
+// quote extends the phrase to the end of the query.
+func Parse(q string) Query {
+	var query Query
+	for i, part := range strings.Split(q, `"`) {
+		if i%2 == 1 {
+			if tokens := tokenize(part); len(tokens) > 0 {
+				words, raw := make([]string, len(tokens)), make([]string, len(tokens))
+				for j, t := range tokens {
+					words[j], raw[j] = t.word, t.raw
+				}
+				query.Phrases = append(query.Phrases, words)
+				query.RawPhrases = append(query.RawPhrases, raw)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			prefix, isPrefix := strings.CutSuffix(word, "*")
+			tokens := tokenize(prefix)
+			for j, t := range tokens {
+				switch {
+				case isPrefix && j == len(tokens)-1:
+					query.Prefixes = append(query.Prefixes, t.raw)
+				case !stopWords[t.word]:
+					query.Terms = append(query.Terms, t.word)
+				}
+			}
+		}
+	}
+	return query
+}
+
+// Empty reports whether the query has nothing to search for.
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Prefixes) == 0 && len(q.Phrases) == 0
+}
+
+// String renders the query in the MongoDB $search syntax. The text index
+// has no prefix operator, so prefixes are sent as plain terms. Phrases are
+// sent as written, since MongoDB looks for them in the raw text.
+func (q Query) String() string {
+	parts := append([]string(nil), q.Terms...)
+	parts = append(parts, q.Prefixes...)
+	for p, phrase := range q.Phrases {
+		if p < len(q.RawPhrases) {
+			phrase = q.RawPhrases[p]
+		}
+		parts = append(parts, `"`+strings.Join(phrase, " ")+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// field is one searchable text of a recipe.
+type field struct {
+	name   string
+	index  int
+	text   string
+	weight int
+}
+
+func fields(recipe models.Recipe) []field {
+	out := []field{{name: "name", text: recipe.Name, weight: NameWeight}}
+	for i, ingredient := range recipe.Ingredients {
+		out = append(out, field{name: "ingredients", index: i, text: ingredient, weight: IngredientsWeight})
+	}
+	for i, instruction := range recipe.Instructions {
+		out = append(out, field{name: "instructions", index: i, text: instruction, weight: InstructionsWeight})
+	}
+	return out
+}
+
+// Score returns the weighted relevance of recipe for q, or zero when the
+// recipe does not match.
+func Score(recipe models.Recipe, q Query) float64 {
+	if q.Empty() {
+		return 0
+	}
+	var score float64
+	termMatched := len(q.Terms) == 0 && len(q.Prefixes) == 0
+	phraseMatched := make([]bool, len(q.Phrases))
+	for _, f := range fields(recipe) {
+		for _, m := range q.matches(f.text) {
+			score += float64(f.weight * m.words)
+			if m.phrase >= 0 {
+				phraseMatched[m.phrase] = true
+			} else {
+				termMatched = true
+			}
+		}
+	}
+	if !termMatched {
+		return 0
+	}
+	for _, matched := range phraseMatched {
+		if !matched {
+			return 0
+		}
+	}
+	return score
+}
+
+// token is a word of a text with its byte offsets. raw is the lower-cased
+// word and word its singular.
+type token struct {
+	word, raw  string
+	start, end int
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+	return tokens
+}
+
+func newToken(text string, start, end int) token {
+	raw := strings.ToLower(text[start:end])
+	return token{word: ingredient.Singular(raw), raw: raw, start: start, end: end}
+}
+
+// match is a run of tokens matching a term or phrase. phrase is the index
+// of the matched phrase or -1 for a term.
+type match struct {
+	start, end int
+	words      int
+	phrase     int
+}
+
+// matches returns the non-overlapping matches of q in text, in order.
+func (q Query) matches(text string) []match {
+	tokens := tokenize(text)
+	var out []match
+	for i := 0; i < len(tokens); {
+		m, ok := q.matchAt(tokens, i)
+		if !ok {
+			i++
+			continue
+		}
+		out = append(out, m)
+		i += m.words
+	}
+	return out
+}
+
+func (q Query) matchAt(tokens []token, i int) (match, bool) {
+	for p, phrase := range q.Phrases {
+		if i+len(phrase) > len(tokens) {
+			continue
+		}
+		matched := true
+		for j, word := range phrase {
+			if tokens[i+j].word != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return match{start: tokens[i].start, end: tokens[i+len(phrase)-1].end, words: len(phrase), phrase: p}, true
+		}
+	}
+	for _, term := range q.Terms {
+		if tokens[i].word == term {
+			return match{start: tokens[i].start, end: tokens[i].end, words: 1, phrase: -1}, true
+		}
+	}
+	for _, prefix := range q.Prefixes {
+		if strings.HasPrefix(tokens[i].raw, prefix) {
+			return match{start: tokens[i].start, end: tokens[i].end, words: 1, phrase: -1}, true
+		}
+	}
+	return match{}, false
+}
@@ -0,0 +1,105 @@
+package fulltext
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+var pizza = models.Recipe{
+	Name: "Homemade Pizza",
+	Ingredients: models.Ingredients{
+		"1 1/2 cups (355 ml) warm water (105F°-115F°)",
+		"feta cheese, firm mozzarella cheese, grated",
+	},
+	Instructions: models.Instructions{
+		"Place the warm water in the large bowl of a heavy duty stand mixer. Sprinkle the yeast over the warm water and let it sit for 5 minutes until the yeast is dissolved.",
+	},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected Query
+	}{
+		{"Terms", "Mozzarella Tomatoes", Query{Terms: []string{"mozzarella", "tomato"}}},
+		{"Irregular plural", "Bay Leaves", Query{Terms: []string{"bay", "leaf"}}},
+		{"Phrase", `"stand mixer"`, Query{Phrases: [][]string{{"stand", "mixer"}}, RawPhrases: [][]string{{"stand", "mixer"}}}},
+		{"Terms and phrase", `yeast "Stand Mixers" bowl`, Query{
+			Terms: []string{"yeast", "bowl"}, Phrases: [][]string{{"stand", "mixer"}}, RawPhrases: [][]string{{"stand", "mixers"}},
+		}},
+		{"Stop words dropped", "the pizza", Query{Terms: []string{"pizza"}}},
+		{"Prefix", "mozz* Pizz*", Query{Prefixes: []string{"mozz", "pizz"}}},
+		{"Unterminated quote", `"warm water`, Query{Phrases: [][]string{{"warm", "water"}}, RawPhrases: [][]string{{"warm", "water"}}}},
+		{"Empty", ` "" `, Query{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.query); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Parse(%q) = %#v, expected %#v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQuery_String(t *testing.T) {
+	got := Parse(`Tomatoes "Bay Leaves" oreg*`).String()
+	if expected := `tomato oreg "bay leaves"`; got != expected {
+		t.Errorf("String() = %q, expected %q", got, expected)
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected float64
+	}{
+		{"Name match", "pizza", NameWeight},
+		{"Ingredient match", "mozzarella", IngredientsWeight},
+		{"Instruction match", "yeast", 2 * InstructionsWeight},
+		{"Term in two fields", "water", IngredientsWeight + 2*InstructionsWeight},
+		{"Phrase match", `"stand mixer"`, 2 * InstructionsWeight},
+		{"Phrase words out of order", `"mixer stand"`, 0},
+		{"Missing phrase rejects terms", `pizza "bread flour"`, 0},
+		{"Prefix match", "mozz*", IngredientsWeight},
+		{"No match", "chocolate", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(pizza, Parse(tt.query)); got != tt.expected {
+				t.Errorf("Score(%q) = %v, expected %v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	got := Highlights(pizza, Parse(`mozzarella "stand mixer"`))
+	expected := []Highlight{
+		{Field: "ingredients", Index: 1, Snippet: "feta cheese, firm <em>mozzarella</em> cheese, grated"},
+		{Field: "instructions", Index: 0, Snippet: "Place the warm water in the large bowl of a heavy duty <em>stand mixer</em>. Sprinkle the yeast over the warm water and let it sit for 5…"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Highlights() = %#v, expected %#v", got, expected)
+	}
+}
+
+func TestHighlights_EscapesHTML(t *testing.T) {
+	recipe := models.Recipe{
+		Name:        "Cheese & <b>Onion</b>",
+		Ingredients: models.Ingredients{`<script>alert("cheese")</script> grated cheese`},
+	}
+	got := Highlights(recipe, Parse("cheese"))
+	expected := []Highlight{
+		{Field: "name", Snippet: "<em>Cheese</em> &amp; &lt;b&gt;Onion&lt;/b&gt;"},
+		{Field: "ingredients", Snippet: "&lt;script&gt;alert(&#34;<em>cheese</em>&#34;)&lt;/script&gt; grated <em>cheese</em>"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Highlights() = %#v, expected %#v", got, expected)
+	}
+}
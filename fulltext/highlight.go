@@ -0,0 +1,87 @@
+package fulltext
+
+import (
+	"html"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Highlight markers wrapped around matched text in snippets.
+const (
+	HighlightStart = "<em>"
+	HighlightEnd   = "</em>"
+)
+
+// snippetContext is the number of bytes of context kept around the first
+// and last match of a long text.
+const snippetContext = 60
+
+// Highlight is a snippet of a recipe field with the matches of a query
+// wrapped in HighlightStart and HighlightEnd. The recipe text is HTML
+// escaped, so the snippet is safe to render as HTML. Index is the position
+// of the line within ingredients or instructions and zero for the name.
+type Highlight struct {
+	Field   string `json:"field" example:"ingredients"`
+	Index   int    `json:"index" example:"4"`
+	Snippet string `json:"snippet" example:"feta cheese, firm <em>mozzarella</em> cheese, grated"`
+}
+
+// Highlights returns a snippet for every field of recipe matching q, in
+// name, ingredients, instructions order.
+func Highlights(recipe models.Recipe, q Query) []Highlight {
+	highlights := make([]Highlight, 0)
+	for _, f := range fields(recipe) {
+		matches := q.matches(f.text)
+		if len(matches) == 0 {
+			continue
+		}
+		highlights = append(highlights, Highlight{
+			Field:   f.name,
+			Index:   f.index,
+			Snippet: snippet(strings.TrimSpace(f.text), q),
+		})
+	}
+	return highlights
+}
+
+// snippet marks the matches of q in text, trimming context further than
+// snippetContext bytes from the first and last match. Every piece of text
+// is escaped before the markers are added around it.
+func snippet(text string, q Query) string {
+	matches := q.matches(text)
+	from, to := 0, len(text)
+	if first := matches[0].start - snippetContext; first > 0 {
+		from = min(wordBoundary(text, first), matches[0].start)
+	}
+	if last := matches[len(matches)-1].end + snippetContext; last < len(text) {
+		to = wordBoundary(text, last)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range matches {
+		b.WriteString(html.EscapeString(text[pos:m.start]))
+		b.WriteString(HighlightStart)
+		b.WriteString(html.EscapeString(text[m.start:m.end]))
+		b.WriteString(HighlightEnd)
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// wordBoundary moves i forward to the next space so snippets do not cut
+// words in half.
+func wordBoundary(text string, i int) int {
+	if j := strings.IndexByte(text[i:], ' '); j >= 0 {
+		return i + j
+	}
+	return len(text)
+}
@@ -3,19 +3,20 @@ module github.com/mrojasb2000/GinRecipes
 go 1.25.4
 
 require (
+	github.com/blevesearch/go-porterstemmer v1.0.3
+	github.com/emersion/go-ical v0.0.0-20250329121855-f41e73efc392
 	github.com/gin-gonic/gin v1.11.0
-	github.com/rs/xid v1.6.0
+	go.mongodb.org/mongo-driver/v2 v2.4.1
 )
 
 require (
 	github.com/golang/snappy v1.0.0 // indirect
 	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/teambition/rrule-go v1.8.2 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
-	go.mongodb.org/mongo-driver v1.17.6 // indirect
-	go.mongodb.org/mongo-driver/v2 v2.4.1 // indirect
 )
 
 require (
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// maxBatchOperations bounds the size of a batch, so one request cannot hold
+// a transaction open for long.
+const maxBatchOperations = 1000
+
+// The operations of a batch.
+const (
+	batchCreate = "create"
+	batchUpdate = "update"
+	batchDelete = "delete"
+)
+
+var (
+	errBatchEmpty         = errors.New("operations must list at least one operation")
+	errBatchTooLarge      = fmt.Errorf("a batch takes at most %d operations", maxBatchOperations)
+	errBatchAtomic        = errors.New("atomic must be true or false")
+	errBatchOp            = errors.New("op must be create, update or delete")
+	errBatchID            = errors.New("id is required")
+	errBatchRecipe        = errors.New("recipe is required")
+	errAtomicNotSupported = errors.New("The recipe store cannot apply a batch atomically")
+)
+
+// BatchOperation is one write of a batch. A create takes a recipe, an
+// update the id and the new content of a recipe and a delete an id.
+// Revision, when set on an update, is the revision the recipe must still
+// have, as with If-Match.
+type BatchOperation struct {
+	Op       string         `json:"op" enums:"create,update,delete" example:"update"`
+	ID       string         `json:"id,omitempty" example:"600dcc85a65917cbd1f201b0"`
+	Revision int            `json:"revision,omitempty" example:"3"`
+	Recipe   *models.Recipe `json:"recipe,omitempty"`
+}
+
+// BatchRequest is the body of BatchRecipesHandler.
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult is the outcome of one operation of a batch: the status the
+// single request would have answered, the id and revision of the recipe
+// written, or the error.
+type BatchResult struct {
+	Op       string              `json:"op" example:"update"`
+	ID       string              `json:"id,omitempty" example:"600dcc85a65917cbd1f201b0"`
+	Status   int                 `json:"status" example:"200"`
+	Revision int                 `json:"revision,omitempty" example:"4"`
+	Error    *httputil.HTTPError `json:"error,omitempty"`
+}
+
+// BatchResponse is the response of BatchRecipesHandler, with one result
+// per operation in the order of the request.
+type BatchResponse struct {
+	Atomic  bool          `json:"atomic" example:"false"`
+	Results []BatchResult `json:"results"`
+}
+
+// Batch Recipes
+//
+//	@Summary		Operation POST /recipes/batch recipes.
+//	@Description	Create, update and delete recipes in one request. Each operation is validated and answered like the
+//	@Description	single request would be, with the status and error in its result.
+//	@Description	By default the operations are applied one after the other and the ones that fail are skipped; the
+//	@Description	response is 200 when all succeeded and 207 otherwise. With atomic=true they are applied in a
+//	@Description	transaction that stops at the first failure: nothing is written, the other operations are answered
+//	@Description	with 424 and the response is 409, or the status of the failure when the server failed.
+//	@Tags			recipes
+//	@Accept			json
+//	@Produce		json
+//	@Param			batch	body		BatchRequest	true	"Operations to apply"
+//	@Param			atomic	query		bool			false	"Apply all operations or none"	default(false)
+//	@Param			X-User	header		string			false	"User making the changes, recorded in the history"
+//	@Success		200		{object}	BatchResponse
+//	@Success		207		{object}	BatchResponse	"Some operations failed"
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		409		{object}	BatchResponse	"An operation failed and the batch was rolled back"
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Failure		501		{object}	httputil.HTTPError
+//	@Router			/recipes/batch [post]
+func (s *Server) BatchRecipesHandler(c *gin.Context) {
+	atomic := false
+	if value := c.Query("atomic"); value != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(value); err != nil {
+			httputil.NewError(c, http.StatusBadRequest, errBatchAtomic)
+			return
+		}
+	}
+	var request BatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	switch n := len(request.Operations); {
+	case n == 0:
+		httputil.NewError(c, http.StatusBadRequest, errBatchEmpty)
+		return
+	case n > maxBatchOperations:
+		httputil.NewError(c, http.StatusBadRequest, errBatchTooLarge)
+		return
+	}
+	if atomic {
+		s.applyAtomically(c, request.Operations)
+		return
+	}
+	response := BatchResponse{Results: make([]BatchResult, len(request.Operations))}
+	status := http.StatusOK
+	for i, op := range request.Operations {
+		result, after, _ := s.applyOperation(c, op)
+		if after != nil {
+			after(c)
+		}
+		if result.Error != nil {
+			status = http.StatusMultiStatus
+		}
+		response.Results[i] = result
+	}
+	c.JSON(status, response)
+}
+
+// applyAtomically applies the operations of a batch in a transaction of
+// the recipe store and writes the response. The revisions and meal plan
+// flags are only recorded once the transaction has committed.
+func (s *Server) applyAtomically(c *gin.Context, ops []BatchOperation) {
+	tx, ok := s.store.(store.Transactor)
+	if !ok {
+		httputil.NewError(c, http.StatusNotImplemented, errAtomicNotSupported)
+		return
+	}
+	var (
+		results []BatchResult
+		afters  []func(*gin.Context)
+		failed  int
+	)
+	err := tx.WithTransaction(c, func(ctx context.Context) error {
+		results, afters, failed = results[:0], afters[:0], -1
+		for i, op := range ops {
+			result, after, err := s.applyOperation(ctx, op)
+			results = append(results, result)
+			if err != nil {
+				failed = i
+				return err
+			}
+			afters = append(afters, after)
+		}
+		return nil
+	})
+	if errors.Is(err, store.ErrNoTransactions) {
+		httputil.NewError(c, http.StatusNotImplemented, errAtomicNotSupported)
+		return
+	}
+	if err != nil && failed < 0 {
+		log.Println("Error committing a batch: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	response := BatchResponse{Atomic: true, Results: make([]BatchResult, len(ops))}
+	if failed >= 0 {
+		notApplied := fmt.Errorf("Not applied: operation %d failed and the batch was rolled back", failed)
+		for i, op := range ops {
+			response.Results[i] = failedResult(op, http.StatusFailedDependency, notApplied)
+		}
+		response.Results[failed] = results[failed]
+		status := http.StatusConflict
+		if results[failed].Status >= http.StatusInternalServerError {
+			status = results[failed].Status
+		}
+		c.JSON(status, response)
+		return
+	}
+	for _, after := range afters {
+		after(c)
+	}
+	copy(response.Results, results)
+	c.JSON(http.StatusOK, response)
+}
+
+// applyOperation applies one operation of a batch to the recipe store with
+// ctx. It returns its result, the work to do once the write takes effect,
+// and the error when it failed.
+func (s *Server) applyOperation(ctx context.Context, op BatchOperation) (BatchResult, func(*gin.Context), error) {
+	switch op.Op {
+	case batchCreate:
+		if op.Recipe == nil {
+			return failedResult(op, http.StatusBadRequest, errBatchRecipe), nil, errBatchRecipe
+		}
+		recipe := *op.Recipe
+		if err := recipe.Validate(); err != nil {
+			return failedResult(op, http.StatusBadRequest, err), nil, err
+		}
+		recipe.Normalize()
+		recipe.ID = bson.NewObjectID().Hex()
+		recipe.PublishedAt = time.Now()
+		if err := s.store.Create(ctx, &recipe); err != nil {
+			return storeFailure(op, err)
+		}
+		return BatchResult{Op: op.Op, ID: recipe.ID, Status: http.StatusCreated, Revision: recipe.Revision},
+			func(c *gin.Context) { s.recordRevision(c, recipe, models.RevisionCreated, 0) }, nil
+	case batchUpdate:
+		if op.ID == "" {
+			return failedResult(op, http.StatusBadRequest, errBatchID), nil, errBatchID
+		}
+		if op.Recipe == nil {
+			return failedResult(op, http.StatusBadRequest, errBatchRecipe), nil, errBatchRecipe
+		}
+		recipe := *op.Recipe
+		if err := recipe.Validate(); err != nil {
+			return failedResult(op, http.StatusBadRequest, err), nil, err
+		}
+		recipe.Normalize()
+		recipe.Revision = op.Revision
+		updated, err := s.store.Update(ctx, op.ID, &recipe)
+		if err != nil {
+			return storeFailure(op, err)
+		}
+		return BatchResult{Op: op.Op, ID: op.ID, Status: http.StatusOK, Revision: updated.Revision},
+			func(c *gin.Context) { s.recordRevision(c, *updated, models.RevisionUpdated, 0) }, nil
+	case batchDelete:
+		if op.ID == "" {
+			return failedResult(op, http.StatusBadRequest, errBatchID), nil, errBatchID
+		}
+		if err := s.store.Delete(ctx, op.ID); err != nil {
+			return storeFailure(op, err)
+		}
+		return BatchResult{Op: op.Op, ID: op.ID, Status: http.StatusOK}, func(c *gin.Context) {
+			if _, err := s.plans.FlagDeletedRecipe(c, op.ID); err != nil {
+				log.Println("Error flagging meal plans of a deleted recipe: ", err)
+			}
+		}, nil
+	}
+	return failedResult(op, http.StatusBadRequest, errBatchOp), nil, errBatchOp
+}
+
+// storeFailure is the result of an operation the recipe store refused.
+func storeFailure(op BatchOperation, err error) (BatchResult, func(*gin.Context), error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return failedResult(op, http.StatusNotFound, errRecipeNotFound), nil, err
+	case errors.Is(err, store.ErrRevisionMismatch):
+		return failedResult(op, http.StatusPreconditionFailed, errPreconditionFailed), nil, err
+	}
+	log.Println("Error applying a batch operation: ", err)
+	return failedResult(op, http.StatusInternalServerError, err), nil, err
+}
+
+// failedResult is the result of an operation that failed with status.
+func failedResult(op BatchOperation, status int, err error) BatchResult {
+	return BatchResult{Op: op.Op, ID: op.ID, Status: status, Error: &httputil.HTTPError{Code: status, Message: err.Error()}}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/ical"
+	"github.com/mrojasb2000/GinRecipes/mealplan"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// calendarProdID identifies the API as the producer of its calendars.
+const calendarProdID = "-//GinRecipes//Recipes API//EN"
+
+// calendarRefresh is how often subscribers are asked to refetch a feed.
+const calendarRefresh = 6 * time.Hour
+
+// Route paths of the calendar endpoints, also used to find the URL prefix
+// the routes are mounted under.
+const (
+	mealPlanCalendarRoute = "/mealplans/:id/calendar.ics"
+	calendarLinkRoute     = "/users/:owner/calendar"
+	ownerCalendarRoute    = "/users/:owner/mealplans.ics"
+)
+
+var errInvalidCalendarToken = errors.New("invalid calendar token")
+
+// CalendarLink is the subscribable calendar feed of an owner.
+type CalendarLink struct {
+	URL   string `json:"url" example:"http://localhost:8080/api/v1/users/maria/mealplans.ics?token=3q2-7w"`
+	Token string `json:"token" example:"3q2-7w"`
+}
+
+// Meal Plan Calendar
+//
+//	@Summary		Operation GET /mealplans/{id}/calendar.ics mealplans.
+//	@Description	Export a meal plan as an iCalendar (RFC 5545) file. Every meal becomes an event at the usual time of its
+//	@Description	slot, titled with the recipe name, linking to the recipe and describing its ingredients, rescaled to the
+//	@Description	meal's servings, and instructions. Meals whose recipe was deleted are left out.
+//	@Tags			mealplans
+//	@Produce		text/calendar
+//	@Param			id	path		string	true	"Meal plan ID"
+//	@Success		200	{string}	string	"iCalendar file"
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/mealplans/{id}/calendar.ics [get]
+func (s *Server) MealPlanCalendarHandler(c *gin.Context) {
+	plan, ok := s.getMealPlan(c)
+	if !ok {
+		return
+	}
+	name := plan.Name
+	if name == "" {
+		name = fmt.Sprintf("Meal plan %s to %s", plan.StartDate, plan.EndDate)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="mealplan-%s.ics"`, plan.ID))
+	s.writeCalendar(c, mealPlanCalendarRoute, ical.Calendar{Name: name}, []models.MealPlan{*plan})
+}
+
+// Calendar Link
+//
+//	@Summary		Operation GET /users/{owner}/calendar mealplans.
+//	@Description	Return the URL of the calendar feed holding every meal plan of an owner, for calendar apps to subscribe to.
+//	@Description	The URL carries a token signed for the owner; it stays valid as long as the server's calendar secret does.
+//	@Description	The API does not authenticate callers, so anyone reaching this endpoint gets the token of any owner;
+//	@Description	expose it only behind a proxy that lets users ask for their own link alone.
+//	@Tags			mealplans
+//	@Produce		json
+//	@Param			owner	path		string	true	"Meal plan owner"
+//	@Success		200		{object}	CalendarLink
+//	@Router			/users/{owner}/calendar [get]
+func (s *Server) CalendarLinkHandler(c *gin.Context) {
+	owner := c.Param("owner")
+	token := s.calendarToken(owner)
+	feed := apiBaseURL(c, calendarLinkRoute) + "/users/" + url.PathEscape(owner) + "/mealplans.ics?token=" + token
+	c.JSON(http.StatusOK, CalendarLink{URL: feed, Token: token})
+}
+
+// Owner Calendar
+//
+//	@Summary		Operation GET /users/{owner}/mealplans.ics mealplans.
+//	@Description	Subscribable iCalendar (RFC 5545) feed of every meal plan of an owner, with events as in
+//	@Description	/mealplans/{id}/calendar.ics. The token must be the one returned by /users/{owner}/calendar.
+//	@Tags			mealplans
+//	@Produce		text/calendar
+//	@Param			owner	path		string	true	"Meal plan owner"
+//	@Param			token	query		string	true	"Signed calendar token"
+//	@Success		200		{string}	string	"iCalendar feed"
+//	@Failure		403		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/users/{owner}/mealplans.ics [get]
+func (s *Server) OwnerCalendarHandler(c *gin.Context) {
+	owner := c.Param("owner")
+	if !hmac.Equal([]byte(c.Query("token")), []byte(s.calendarToken(owner))) {
+		httputil.NewError(c, http.StatusForbidden, errInvalidCalendarToken)
+		return
+	}
+	plans, err := s.plans.List(c, owner)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	cal := ical.Calendar{Name: "Meal plans of " + owner, RefreshInterval: calendarRefresh}
+	s.writeCalendar(c, ownerCalendarRoute, cal, plans)
+}
+
+// writeCalendar responds with cal holding the events of plans. route is
+// the route being served, used to link to the recipes.
+func (s *Server) writeCalendar(c *gin.Context, route string, cal ical.Calendar, plans []models.MealPlan) {
+	recipes, err := s.plansRecipes(c, plans)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	base := apiBaseURL(c, route)
+	cal.ProdID = calendarProdID
+	cal.Events = mealplan.Events(plans, recipes, func(id string) string {
+		return base + "/recipes/" + url.PathEscape(id)
+	})
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Status(http.StatusOK)
+	if _, err := cal.WriteTo(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+// plansRecipes fetches the recipes planned in any of plans by id.
+func (s *Server) plansRecipes(ctx context.Context, plans []models.MealPlan) (map[string]models.Recipe, error) {
+	recipes := map[string]models.Recipe{}
+	for _, plan := range plans {
+		found, _, err := s.planRecipes(ctx, plan)
+		if err != nil {
+			return nil, err
+		}
+		for id, recipe := range found {
+			recipes[id] = recipe
+		}
+	}
+	return recipes, nil
+}
+
+// calendarToken signs owner with the calendar secret.
+func (s *Server) calendarToken(owner string) string {
+	mac := hmac.New(sha256.New, s.calendarSecret)
+	mac.Write([]byte("calendar:" + owner))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// apiBaseURL returns the absolute URL the API routes are mounted under,
+// as seen by the client, given the route being served. Proxies may
+// override the scheme and host with X-Forwarded-Proto and
+// X-Forwarded-Host.
+func apiBaseURL(c *gin.Context, route string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := c.Request.Host
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host + strings.TrimSuffix(c.FullPath(), route)
+}
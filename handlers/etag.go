@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// errPreconditionFailed is the client-facing message for a stale If-Match.
+var errPreconditionFailed = errors.New("Recipe has been modified since it was fetched")
+
+// setETag sends the revision of a recipe as its entity tag.
+func setETag(c *gin.Context, revision int) {
+	c.Header("ETag", `"`+strconv.Itoa(revision)+`"`)
+}
+
+// setWeakETag sends the revision of a recipe as a weak entity tag, for
+// responses rendering it differently from how it is stored. Such a tag
+// never satisfies If-Match.
+func setWeakETag(c *gin.Context, revision int) {
+	c.Header("ETag", `W/"`+strconv.Itoa(revision)+`"`)
+}
+
+// parseIfMatch reads the revisions listed in an If-Match header, and
+// whether it is "*". Weak and malformed entity tags are skipped, as they
+// never match under the strong comparison If-Match calls for.
+func parseIfMatch(header string) (revisions []int, any bool) {
+	for tag := range strings.SplitSeq(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		unquoted, ok := strings.CutPrefix(tag, `"`)
+		unquoted, closed := strings.CutSuffix(unquoted, `"`)
+		if !ok || !closed {
+			continue
+		}
+		if revision, err := strconv.Atoi(unquoted); err == nil && revision > 0 {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, false
+}
+
+// expectedRevision works out the revision the If-Match header of an
+// update requires the recipe with the given id to be at, or zero when any
+// will do. It fails with errPreconditionFailed when no revision can match.
+func (s *Server) expectedRevision(ctx context.Context, header, id string) (int, error) {
+	if header == "" {
+		return 0, nil
+	}
+	revisions, any := parseIfMatch(header)
+	switch {
+	case any:
+		return 0, nil
+	case len(revisions) == 0:
+		return 0, errPreconditionFailed
+	case len(revisions) == 1:
+		return revisions[0], nil
+	}
+	current, err := s.store.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(revisions, current.Revision) {
+		return 0, errPreconditionFailed
+	}
+	return current.Revision, nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/recipeio"
+)
+
+// Export Recipes
+//
+//	@Summary		Operation GET /recipes/export recipes.
+//	@Description	Stream every recipe matching the list filters as JSON, NDJSON or CSV. The CSV layout flattens
+//	@Description	tags, ingredients and instructions into "|"-separated cells and can be imported back unchanged.
+//	@Tags			recipes
+//	@Produce		json
+//	@Produce		plain
+//	@Param			format			query		string	false	"Output format"	Enums(json, ndjson, csv)	default(json)
+//	@Param			tags			query		string	false	"Comma-separated tags, -tag to exclude"
+//	@Param			tags_match		query		string	false	"Match any (default) or all of the tags"	Enums(any, all)
+//	@Param			name			query		string	false	"Name contains (case-insensitive)"
+//	@Param			ingredient		query		string	false	"An ingredient contains (case-insensitive)"
+//	@Param			published_from	query		string	false	"Published at or after (RFC 3339 or YYYY-MM-DD)"
+//	@Param			published_to	query		string	false	"Published at or before (RFC 3339 or YYYY-MM-DD)"
+//	@Param			difficulty		query		string	false	"Difficulty"	Enums(easy, medium, hard)
+//	@Param			cuisine			query		string	false	"Cuisine (case-insensitive)"
+//	@Param			author			query		string	false	"Author contains (case-insensitive)"
+//	@Param			max_total_time	query		string	false	"Total time at most, as an ISO 8601 duration like PT30M"
+//	@Success		200	{array}		models.Recipe
+//	@Failure		400	{object}	httputil.HTTPError
+//	@Router			/recipes/export [get]
+func (s *Server) ExportRecipesHandler(c *gin.Context) {
+	format, err := recipeio.ParseFormat(c.DefaultQuery("format", string(recipeio.JSON)))
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	opts, err := parseListOptions(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="recipes.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+	w, err := recipeio.NewWriter(c.Writer, format)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := recipeio.Export(c, s.store, w, opts.Filter, c.Writer.Flush); err != nil {
+		log.Println("Error exporting recipes: ", err)
+		abortStream(c)
+	}
+}
+
+// abortStream cuts the connection of a response whose status line may
+// already be sent, so that the client sees the body as truncated instead
+// of complete. HTTP/2 connections cannot be taken over and are only
+// logged.
+func abortStream(c *gin.Context) {
+	c.Abort()
+	conn, _, err := c.Writer.Hijack()
+	if err != nil {
+		log.Println("Error aborting a response: ", err)
+		return
+	}
+	conn.Close()
+}
@@ -0,0 +1,141 @@
+// Package handlers contains the HTTP handlers of the recipes API.
+//
+// Handlers are methods on Server, which receives its RecipeStore, and
+// optionally meal plan, pantry and revision stores, at construction time so the API
+// can run against MongoDB, an in-memory store, or be embedded in another
+// gin application.
+package handlers
+
+import (
+	"crypto/rand"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// Server holds the dependencies shared by the recipe handlers.
+type Server struct {
+	store     store.RecipeStore
+	plans     store.MealPlanStore
+	pantries  store.PantryStore
+	revisions store.RevisionStore
+	nutrients *nutrition.Table
+
+	// calendarSecret signs the tokens of the calendar feeds.
+	calendarSecret []byte
+
+	// searchNotFound restores the legacy 404 response of tag search when
+	// nothing matches.
+	searchNotFound bool
+}
+
+// Option configures optional Server behaviour.
+type Option func(*Server)
+
+// WithSearchNotFound makes tag search answer 404 instead of an empty list
+// when no recipe matches, as it did before search queried the store.
+func WithSearchNotFound(enabled bool) Option {
+	return func(s *Server) {
+		s.searchNotFound = enabled
+	}
+}
+
+// WithMealPlanStore keeps meal plans in plans instead of in memory.
+func WithMealPlanStore(plans store.MealPlanStore) Option {
+	return func(s *Server) {
+		s.plans = plans
+	}
+}
+
+// WithPantryStore keeps pantries in pantries instead of in memory.
+func WithPantryStore(pantries store.PantryStore) Option {
+	return func(s *Server) {
+		s.pantries = pantries
+	}
+}
+
+// WithRevisionStore keeps the revision history of recipes in revisions
+// instead of in memory.
+func WithRevisionStore(revisions store.RevisionStore) Option {
+	return func(s *Server) {
+		s.revisions = revisions
+	}
+}
+
+// WithCalendarSecret sets the key signing the calendar feed tokens. Without
+// it a random key is used, and feed URLs stop working when the server
+// restarts.
+func WithCalendarSecret(secret []byte) Option {
+	return func(s *Server) {
+		s.calendarSecret = secret
+	}
+}
+
+// WithNutritionTable replaces the bundled nutrient table used to estimate
+// recipe nutrition.
+func WithNutritionTable(table *nutrition.Table) Option {
+	return func(s *Server) {
+		s.nutrients = table
+	}
+}
+
+// NewServer returns a Server backed by the given recipe store. Meal plans,
+// pantries and recipe revisions are kept in memory unless
+// WithMealPlanStore, WithPantryStore and WithRevisionStore are given.
+func NewServer(recipeStore store.RecipeStore, opts ...Option) *Server {
+	s := &Server{
+		store:     recipeStore,
+		plans:     store.NewMemoryMealPlanStore(),
+		pantries:  store.NewMemoryPantryStore(),
+		revisions: store.NewMemoryRevisionStore(),
+		nutrients: nutrition.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(s.calendarSecret) == 0 {
+		log.Println("No calendar secret set, using a random key: calendar feed URLs stop working when the server restarts")
+		s.calendarSecret = make([]byte, 32)
+		rand.Read(s.calendarSecret)
+	}
+	return s
+}
+
+// RegisterRoutes mounts the recipe and meal plan endpoints on r. Callers usually pass
+// the "/api/v1" group.
+func (s *Server) RegisterRoutes(r gin.IRouter) {
+	r.POST("/recipes", s.NewRecipeHandler)
+	r.POST("/recipes/batch", s.BatchRecipesHandler)
+	r.GET("/recipes", s.ListRecipesHandler)
+	r.PUT("/recipes/:id", s.UpdateRecipeHandler)
+	r.PATCH("/recipes/:id", s.PatchRecipeHandler)
+	r.DELETE("/recipes/:id", s.DeleteRecipeHandler)
+	r.GET("/recipes/search", s.SearchRecipesHandler)
+	r.GET("/recipes/search/pantry", s.PantrySearchHandler)
+	r.GET("/recipes/search/ingredients", s.IngredientSearchHandler)
+	r.GET("/recipes/export", s.ExportRecipesHandler)
+	r.GET("/recipes/trash", s.TrashHandler)
+	r.GET("/recipes/:id", s.GetRecipeHandler)
+	r.GET("/recipes/:id/nutrition", s.RecipeNutritionHandler)
+	r.POST("/recipes/:id/restore", s.RestoreRecipeHandler)
+	r.GET("/recipes/:id/revisions", s.ListRevisionsHandler)
+	r.GET("/recipes/:id/revisions/:rev", s.GetRevisionHandler)
+	r.GET("/recipes/:id/revisions/:rev/diff", s.RevisionDiffHandler)
+	r.POST("/recipes/:id/revisions/:rev/restore", s.RestoreRevisionHandler)
+	r.POST("/shopping-list", s.ShoppingListHandler)
+	r.POST("/mealplans", s.NewMealPlanHandler)
+	r.GET("/mealplans", s.ListMealPlansHandler)
+	r.GET("/mealplans/:id", s.GetMealPlanHandler)
+	r.PUT("/mealplans/:id", s.UpdateMealPlanHandler)
+	r.DELETE("/mealplans/:id", s.DeleteMealPlanHandler)
+	r.GET("/mealplans/:id/shopping-list", s.MealPlanShoppingListHandler)
+	r.GET("/mealplans/:id/nutrition", s.MealPlanNutritionHandler)
+	r.GET(mealPlanCalendarRoute, s.MealPlanCalendarHandler)
+	r.GET(calendarLinkRoute, s.CalendarLinkHandler)
+	r.GET(ownerCalendarRoute, s.OwnerCalendarHandler)
+	r.GET("/pantries/:owner", s.GetPantryHandler)
+	r.PUT("/pantries/:owner", s.PutPantryHandler)
+	r.DELETE("/pantries/:owner", s.DeletePantryHandler)
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/mealplan"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/shopping"
+	"github.com/mrojasb2000/GinRecipes/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// errMealPlanNotFound is the client-facing message for
+// store.ErrMealPlanNotFound.
+var errMealPlanNotFound = errors.New("Meal plan not found")
+
+// New Meal Plan
+//
+//	@Summary		Operation POST /mealplans mealplans.
+//	@Description	Add a new meal plan covering at most 31 days. Every planned recipe must exist.
+//	@Tags			mealplans
+//	@Accept			json
+//	@Produce		json
+//	@Param			plan	body		models.MealPlan	true	"Add meal plan"
+//	@Success		201		{object}	models.MealPlan
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		422		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/mealplans [post]
+func (s *Server) NewMealPlanHandler(c *gin.Context) {
+	plan, ok := s.bindMealPlan(c)
+	if !ok {
+		return
+	}
+	plan.ID = bson.NewObjectID().Hex()
+	plan.CreatedAt = time.Now()
+	plan.UpdatedAt = plan.CreatedAt
+	if err := s.plans.Create(c, &plan); err != nil {
+		log.Println("Error inserting meal plan: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+// List Meal Plans
+//
+//	@Summary		Operation GET /mealplans mealplans.
+//	@Description	Return the meal plans of an owner, or of everyone, ordered by start date.
+//	@Tags			mealplans
+//	@Produce		json
+//	@Param			owner	query		string	false	"Only plans of this owner"
+//	@Success		200		{array}		models.MealPlan
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/mealplans [get]
+func (s *Server) ListMealPlansHandler(c *gin.Context) {
+	plans, err := s.plans.List(c, c.Query("owner"))
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, plans)
+}
+
+// Get Meal Plan
+//
+//	@Summary		Operation GET /mealplans/{id} mealplans.
+//	@Description	Return a single meal plan. Meals whose recipe has since been deleted are flagged with recipeDeleted.
+//	@Tags			mealplans
+//	@Produce		json
+//	@Param			id	path		string	true	"Meal plan ID"
+//	@Success		200	{object}	models.MealPlan
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/mealplans/{id} [get]
+func (s *Server) GetMealPlanHandler(c *gin.Context) {
+	plan, ok := s.getMealPlan(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// Update Meal Plan
+//
+//	@Summary		Operation PUT /mealplans/{id} mealplans.
+//	@Description	Replace the owner, name, dates and meals of a meal plan. Every planned recipe must exist, so meals
+//	@Description	flagged with recipeDeleted have to be replaced or removed.
+//	@Tags			mealplans
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Meal plan ID"
+//	@Param			plan	body		models.MealPlan	true	"Update meal plan"
+//	@Success		200		{object}	models.MealPlan
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		404		{object}	httputil.HTTPError
+//	@Failure		422		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/mealplans/{id} [put]
+func (s *Server) UpdateMealPlanHandler(c *gin.Context) {
+	plan, ok := s.bindMealPlan(c)
+	if !ok {
+		return
+	}
+	plan.UpdatedAt = time.Now()
+	updated, err := s.plans.Update(c, c.Param("id"), &plan)
+	if errors.Is(err, store.ErrMealPlanNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errMealPlanNotFound)
+		return
+	}
+	if err != nil {
+		log.Println("Error updating a meal plan: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete Meal Plan
+//
+//	@Summary		Operation DELETE /mealplans/{id} mealplans.
+//	@Description	Delete a meal plan. The planned recipes are kept.
+//	@Tags			mealplans
+//	@Produce		json
+//	@Param			id	path	string	true	"Meal plan ID"
+//	@Success		204
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/mealplans/{id} [delete]
+func (s *Server) DeleteMealPlanHandler(c *gin.Context) {
+	err := s.plans.Delete(c, c.Param("id"))
+	if errors.Is(err, store.ErrMealPlanNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errMealPlanNotFound)
+		return
+	}
+	if err != nil {
+		log.Println("Error deleting a meal plan: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Meal Plan Shopping List
+//
+//	@Summary		Operation GET /mealplans/{id}/shopping-list mealplans.
+//	@Description	Build the shopping list of a meal plan, with every meal rescaled to its servings. Meals whose recipe
+//	@Description	was deleted are left out.
+//	@Tags			mealplans
+//	@Produce		json
+//	@Produce		plain
+//	@Produce		text/markdown
+//	@Param			id				path		string	true	"Meal plan ID"
+//	@Param			format			query		string	false	"Output format"	Enums(json, text, markdown)	default(json)
+//	@Param			units			query		string	false	"Unit system to total amounts in"	Enums(metric, us)
+//	@Param			Accept-Language	header		string	false	"Picks the unit system when units is not given"
+//	@Success		200				{object}	shopping.List
+//	@Failure		400				{object}	httputil.HTTPError
+//	@Failure		404				{object}	httputil.HTTPError
+//	@Failure		422				{object}	httputil.HTTPError
+//	@Failure		500				{object}	httputil.HTTPError
+//	@Router			/mealplans/{id}/shopping-list [get]
+func (s *Server) MealPlanShoppingListHandler(c *gin.Context) {
+	format, err := parseShoppingFormat(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	sys, err := parseUnits(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	plan, ok := s.getMealPlan(c)
+	if !ok {
+		return
+	}
+	recipes, _, err := s.planRecipes(c, *plan)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	list, err := shopping.Build(mealplan.ShoppingEntries(*plan, recipes), s.nutrients, sys)
+	if err != nil {
+		httputil.NewError(c, shoppingListStatus(err), err)
+		return
+	}
+	c.Header("Vary", "Accept-Language")
+	writeShoppingList(c, format, list)
+}
+
+// Meal Plan Nutrition
+//
+//	@Summary		Operation GET /mealplans/{id}/nutrition mealplans.
+//	@Description	Estimate the nutrients one person eats on each day of a meal plan, counting one serving of every meal,
+//	@Description	and the average over the days with estimated meals. Meals of recipes that do not give their servings
+//	@Description	are listed without an estimate.
+//	@Tags			mealplans
+//	@Produce		json
+//	@Param			id	path		string	true	"Meal plan ID"
+//	@Success		200	{object}	mealplan.Nutrition
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/mealplans/{id}/nutrition [get]
+func (s *Server) MealPlanNutritionHandler(c *gin.Context) {
+	plan, ok := s.getMealPlan(c)
+	if !ok {
+		return
+	}
+	recipes, _, err := s.planRecipes(c, *plan)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, mealplan.Summarize(*plan, recipes, s.nutrients))
+}
+
+// bindMealPlan reads and validates the meal plan in the request body,
+// responding with an error and returning false when it is unusable.
+func (s *Server) bindMealPlan(c *gin.Context) (models.MealPlan, bool) {
+	var plan models.MealPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return plan, false
+	}
+	if err := plan.Validate(); err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return plan, false
+	}
+	for i := range plan.Meals {
+		plan.Meals[i].RecipeDeleted = false
+	}
+	_, missing, err := s.planRecipes(c, plan)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return plan, false
+	}
+	if len(missing) > 0 {
+		httputil.NewError(c, http.StatusUnprocessableEntity, fmt.Errorf("recipe %q: %w", missing[0], store.ErrNotFound))
+		return plan, false
+	}
+	plan.SortMeals()
+	return plan, true
+}
+
+// getMealPlan fetches the meal plan named by the id path parameter,
+// responding with an error and returning false when there is none.
+func (s *Server) getMealPlan(c *gin.Context) (*models.MealPlan, bool) {
+	plan, err := s.plans.Get(c, c.Param("id"))
+	if errors.Is(err, store.ErrMealPlanNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errMealPlanNotFound)
+		return nil, false
+	}
+	if err != nil {
+		log.Println("Error fetching a meal plan: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return plan, true
+}
+
+// planRecipes fetches the recipes planned in plan by id, skipping meals
+// flagged as deleted. The ids of recipes that no longer exist are returned
+// as missing.
+func (s *Server) planRecipes(ctx context.Context, plan models.MealPlan) (map[string]models.Recipe, []string, error) {
+	recipes := map[string]models.Recipe{}
+	seen := map[string]bool{}
+	var missing []string
+	for _, meal := range plan.Meals {
+		if seen[meal.RecipeID] || meal.RecipeDeleted {
+			continue
+		}
+		seen[meal.RecipeID] = true
+		recipe, err := s.store.Get(ctx, meal.RecipeID)
+		if errors.Is(err, store.ErrNotFound) {
+			missing = append(missing, meal.RecipeID)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		recipes[meal.RecipeID] = *recipe
+	}
+	return recipes, missing, nil
+}
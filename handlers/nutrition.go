@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// Recipe Nutrition
+//
+//	@Summary		Operation GET /recipes/{id}/nutrition recipes.
+//	@Description	Estimate calories, protein, fat, carbohydrates and sodium of a recipe, in total and per serving when the recipe gives its servings.
+//	@Description	Ingredients are matched against an offline nutrient table; lines without a match, or whose amount cannot be weighed, are listed separately.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			id	path		string	true	"Recipe ID"
+//	@Success		200	{object}	nutrition.Estimate
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/{id}/nutrition [get]
+func (s *Server) RecipeNutritionHandler(c *gin.Context) {
+	recipe, err := s.store.Get(c, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errRecipeNotFound)
+		return
+	}
+	if err != nil {
+		log.Println("Error fetching a recipe: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, s.nutrients.Estimate(*recipe))
+}
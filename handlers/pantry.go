@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/pantry"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// errPantryNotFound is the client-facing message for
+// store.ErrPantryNotFound.
+var errPantryNotFound = errors.New("Pantry not found")
+
+// PantryMatch is a recipe ranked by a pantry search, with how much of it
+// the ingredients on hand cover and the ingredient lines still missing.
+type PantryMatch struct {
+	models.Recipe
+	pantry.Coverage
+}
+
+// Get Pantry
+//
+//	@Summary		Operation GET /pantries/{owner} pantries.
+//	@Description	Return the ingredients an owner has on hand and the ingredients they exclude.
+//	@Tags			pantries
+//	@Produce		json
+//	@Param			owner	path		string	true	"Pantry owner"
+//	@Success		200		{object}	models.Pantry
+//	@Failure		404		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/pantries/{owner} [get]
+func (s *Server) GetPantryHandler(c *gin.Context) {
+	p, err := s.pantries.Get(c, c.Param("owner"))
+	if errors.Is(err, store.ErrPantryNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errPantryNotFound)
+		return
+	}
+	if err != nil {
+		log.Println("Error fetching a pantry: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Put Pantry
+//
+//	@Summary		Operation PUT /pantries/{owner} pantries.
+//	@Description	Create or replace the pantry of an owner. The owner in the body, if any, is ignored.
+//	@Tags			pantries
+//	@Accept			json
+//	@Produce		json
+//	@Param			owner	path		string			true	"Pantry owner"
+//	@Param			pantry	body		models.Pantry	true	"Ingredients on hand"
+//	@Success		200		{object}	models.Pantry
+//	@Success		201		{object}	models.Pantry
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/pantries/{owner} [put]
+func (s *Server) PutPantryHandler(c *gin.Context) {
+	var p models.Pantry
+	if err := c.ShouldBindJSON(&p); err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	p.Owner = c.Param("owner")
+	if err := p.Validate(); err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	if p.Items == nil {
+		p.Items = []models.PantryItem{}
+	}
+	p.UpdatedAt = time.Now()
+	created, err := s.pantries.Put(c, &p)
+	if err != nil {
+		log.Println("Error storing a pantry: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, p)
+}
+
+// Delete Pantry
+//
+//	@Summary		Operation DELETE /pantries/{owner} pantries.
+//	@Description	Delete the pantry of an owner.
+//	@Tags			pantries
+//	@Param			owner	path	string	true	"Pantry owner"
+//	@Success		204
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/pantries/{owner} [delete]
+func (s *Server) DeletePantryHandler(c *gin.Context) {
+	err := s.pantries.Delete(c, c.Param("owner"))
+	if errors.Is(err, store.ErrPantryNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errPantryNotFound)
+		return
+	}
+	if err != nil {
+		log.Println("Error deleting a pantry: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// What Can I Cook
+//
+//	@Summary		Operation GET /recipes/search/pantry recipes.
+//	@Description	Rank recipes by the share of their ingredients covered by what is on hand, listing the missing
+//	@Description	ingredient lines of each. The items on hand are those of the owner's pantry and the have parameter;
+//	@Description	water, salt and black pepper count as on hand unless staples is false. Recipes with an excluded
+//	@Description	ingredient are left out. Exclusions come from the pantry and the exclude parameter, written as "nuts",
+//	@Description	"no nuts" or "-nuts"; nuts, pork, meat, fish, shellfish, seafood, dairy, egg and gluten cover their
+//	@Description	usual ingredients. Only recipes with at least one covered ingredient are returned.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			owner		query		string		false	"Use the pantry of this owner"
+//	@Param			have		query		[]string	false	"More ingredients on hand"	collectionFormat(multi)
+//	@Param			exclude		query		[]string	false	"Ingredients to exclude"	collectionFormat(multi)
+//	@Param			staples		query		bool		false	"Count water, salt and black pepper as on hand"	default(true)
+//	@Param			max_missing	query		int			false	"Only recipes missing at most this many ingredients"
+//	@Param			limit		query		int			false	"Maximum results (default 100, max 1000)"
+//	@Success		200			{array}		PantryMatch
+//	@Failure		400			{object}	httputil.HTTPError
+//	@Failure		404			{object}	httputil.HTTPError
+//	@Failure		500			{object}	httputil.HTTPError
+//	@Router			/recipes/search/pantry [get]
+func (s *Server) PantrySearchHandler(c *gin.Context) {
+	q, err := parsePantryQuery(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	if q.owner != "" {
+		p, err := s.pantries.Get(c, q.owner)
+		if errors.Is(err, store.ErrPantryNotFound) {
+			httputil.NewError(c, http.StatusNotFound, errPantryNotFound)
+			return
+		}
+		if err != nil {
+			httputil.NewError(c, http.StatusInternalServerError, err)
+			return
+		}
+		for _, item := range p.Items {
+			q.have = append(q.have, item.Name)
+		}
+		q.exclude = append(q.exclude, p.Exclude...)
+	}
+	if q.staples {
+		q.have = append(q.have, pantry.Staples...)
+	}
+
+	matcher := pantry.NewMatcher(s.nutrients, q.have, q.exclude)
+	var results []pantry.Result
+	err = store.Walk(c, s.store, store.ListOptions{Limit: store.MaxListLimit}, func(recipe models.Recipe) error {
+		coverage, ok := matcher.Match(recipe)
+		if ok && coverage.Covered > 0 && (q.maxMissing < 0 || len(coverage.Missing) <= q.maxMissing) {
+			results = append(results, pantry.Result{Recipe: recipe, Coverage: coverage})
+		}
+		return nil
+	})
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pantry.Sort(results)
+	if limit := (store.ListOptions{Limit: q.limit}).EffectiveLimit(); len(results) > limit {
+		results = results[:limit]
+	}
+	matches := make([]PantryMatch, len(results))
+	for i, r := range results {
+		matches[i] = PantryMatch{Recipe: r.Recipe, Coverage: r.Coverage}
+	}
+	c.JSON(http.StatusOK, matches)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/jsonpatch"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// maxPatchAttempts bounds how often a patch is applied again when the
+// recipe changes between reading and writing it.
+const maxPatchAttempts = 3
+
+// acceptPatch lists the patch formats PatchRecipeHandler understands, for
+// the Accept-Patch header.
+var acceptPatch = strings.Join([]string{jsonpatch.MergePatchType, jsonpatch.JSONPatchType}, ", ")
+
+var (
+	errReadOnlyFields = errors.New("id, publishedAt and revision cannot be patched")
+	errPatchConflict  = errors.New("Recipe kept changing while the patch was applied; retry")
+)
+
+// Patch Recipe
+//
+//	@Summary		Operation PATCH /recipes/{id} recipes.
+//	@Description	Change part of a recipe with a JSON Merge Patch (RFC 7386) sent as application/merge-patch+json, or a
+//	@Description	JSON Patch (RFC 6902) sent as application/json-patch+json, applied to the recipe as GET returns it
+//	@Description	without parsedIngredients. The patched recipe must be valid, and its id, publishedAt and revision
+//	@Description	cannot change. Either the whole patch is applied or nothing is. Send If-Match, or a JSON Patch test
+//	@Description	of /revision, to have the patch rejected when someone else changed the recipe since it was read.
+//	@Tags			recipes
+//	@Accept			application/merge-patch+json
+//	@Accept			application/json-patch+json
+//	@Produce		json
+//	@Param			id			path		string	true	"Recipe ID"
+//	@Param			If-Match	header		string	false	"ETag the recipe must still have, as returned by GET"
+//	@Param			patch		body		object	true	"Merge patch object or array of JSON Patch operations"
+//	@Param			X-User		header		string	false	"User making the change, recorded in the history"
+//	@Success		200			{object}	models.Recipe
+//	@Header			200			{string}	ETag	"Revision of the patched recipe"
+//	@Failure		400			{object}	httputil.HTTPError
+//	@Failure		404			{object}	httputil.HTTPError
+//	@Failure		409			{object}	httputil.HTTPError	"A JSON Patch test failed"
+//	@Failure		412			{object}	httputil.HTTPError
+//	@Failure		415			{object}	httputil.HTTPError
+//	@Failure		422			{object}	httputil.HTTPError	"The patch cannot be applied or makes the recipe invalid"
+//	@Failure		500			{object}	httputil.HTTPError
+//	@Router			/recipes/{id} [patch]
+func (s *Server) PatchRecipeHandler(c *gin.Context) {
+	apply, ok := readPatch(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	header := c.GetHeader("If-Match")
+	revisions, anyRevision := parseIfMatch(header)
+	for range maxPatchAttempts {
+		current, err := s.store.Get(c, id)
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.NewError(c, http.StatusNotFound, errRecipeNotFound)
+			return
+		}
+		if err != nil {
+			log.Println("Error fetching a recipe: ", err)
+			httputil.NewError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if header != "" && !anyRevision && !slices.Contains(revisions, current.Revision) {
+			httputil.NewError(c, http.StatusPreconditionFailed, errPreconditionFailed)
+			return
+		}
+		recipe, err := patchRecipe(*current, apply)
+		if err != nil {
+			httputil.NewError(c, patchStatus(err), err)
+			return
+		}
+		updated, err := s.store.Update(c, id, &recipe)
+		if errors.Is(err, store.ErrRevisionMismatch) {
+			continue
+		}
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.NewError(c, http.StatusNotFound, errRecipeNotFound)
+			return
+		}
+		if err != nil {
+			log.Println("Error updating a recipe: ", err)
+			httputil.NewError(c, http.StatusInternalServerError, err)
+			return
+		}
+		s.recordRevision(c, *updated, models.RevisionUpdated, 0)
+		setETag(c, updated.Revision)
+		c.JSON(http.StatusOK, updated)
+		return
+	}
+	httputil.NewError(c, http.StatusConflict, errPatchConflict)
+}
+
+// readPatch reads the patch in the request body as a function applying it
+// to a JSON document, responding with an error and returning false when
+// the body is not a patch.
+func readPatch(c *gin.Context) (func(doc []byte) ([]byte, error), bool) {
+	contentType := c.ContentType()
+	if contentType != jsonpatch.MergePatchType && contentType != jsonpatch.JSONPatchType {
+		c.Header("Accept-Patch", acceptPatch)
+		httputil.NewError(c, http.StatusUnsupportedMediaType,
+			fmt.Errorf("Content-Type must be %s or %s, got %q", jsonpatch.MergePatchType, jsonpatch.JSONPatchType, contentType))
+		return nil, false
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if contentType == jsonpatch.MergePatchType {
+		if !json.Valid(body) {
+			httputil.NewError(c, http.StatusBadRequest, fmt.Errorf("%w: body is not JSON", jsonpatch.ErrInvalidPatch))
+			return nil, false
+		}
+		return func(doc []byte) ([]byte, error) {
+			return jsonpatch.MergePatch(doc, body)
+		}, true
+	}
+	patch, err := jsonpatch.DecodePatch(body)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return patch.Apply, true
+}
+
+// patchRecipe applies a patch to the JSON form of current and returns the
+// validated and normalized result, at the revision of current.
+func patchRecipe(current models.Recipe, apply func(doc []byte) ([]byte, error)) (models.Recipe, error) {
+	var recipe models.Recipe
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return recipe, err
+	}
+	if doc, err = apply(doc); err != nil {
+		return recipe, err
+	}
+	if err := json.Unmarshal(doc, &recipe); err != nil {
+		return recipe, fmt.Errorf("patched recipe: %w", err)
+	}
+	if recipe.ID != current.ID || !recipe.PublishedAt.Equal(current.PublishedAt) || recipe.Revision != current.Revision {
+		return recipe, errReadOnlyFields
+	}
+	if err := recipe.Validate(); err != nil {
+		return recipe, err
+	}
+	recipe.Normalize()
+	return recipe, nil
+}
+
+// patchStatus maps an error of patchRecipe to its response status.
+func patchStatus(err error) int {
+	switch {
+	case errors.Is(err, jsonpatch.ErrInvalidPatch):
+		return http.StatusBadRequest
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		return http.StatusConflict
+	}
+	return http.StatusUnprocessableEntity
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/convert"
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// parseListOptions reads the pagination, filter and sort query parameters
+// of the recipe list. Malformed values are reported as errors suitable for
+// a 400 response.
+func parseListOptions(c *gin.Context) (store.ListOptions, error) {
+	opts := store.ListOptions{Cursor: c.Query("cursor")}
+	var err error
+	if opts.Limit, err = parseLimit(c); err != nil {
+		return opts, err
+	}
+
+	opts.Filter.Tags, opts.Filter.ExcludeTags = splitTags(c.QueryArray("tags"))
+	if opts.Filter.MatchAllTags, err = parseTagsMatch(c); err != nil {
+		return opts, err
+	}
+	opts.Filter.Name = strings.TrimSpace(c.Query("name"))
+	opts.Filter.Ingredient = strings.TrimSpace(c.Query("ingredient"))
+
+	if opts.Filter.PublishedFrom, err = parseTime("published_from", c.Query("published_from"), false); err != nil {
+		return opts, err
+	}
+	if opts.Filter.PublishedTo, err = parseTime("published_to", c.Query("published_to"), true); err != nil {
+		return opts, err
+	}
+	if !opts.Filter.PublishedFrom.IsZero() && !opts.Filter.PublishedTo.IsZero() &&
+		opts.Filter.PublishedFrom.After(opts.Filter.PublishedTo) {
+		return opts, errors.New("published_from must not be after published_to")
+	}
+
+	if difficulty := strings.ToLower(c.Query("difficulty")); difficulty != "" {
+		if !slices.Contains(models.Difficulties, models.Difficulty(difficulty)) {
+			return opts, fmt.Errorf("difficulty must be easy, medium or hard, got %q", difficulty)
+		}
+		opts.Filter.Difficulty = models.Difficulty(difficulty)
+	}
+	opts.Filter.Cuisine = strings.ToLower(strings.TrimSpace(c.Query("cuisine")))
+	opts.Filter.Author = strings.TrimSpace(c.Query("author"))
+	if maxTime := c.Query("max_total_time"); maxTime != "" {
+		d, err := models.ParseDuration(maxTime)
+		if err != nil || d < time.Minute {
+			return opts, fmt.Errorf("max_total_time must be an ISO 8601 duration of at least a minute like PT30M, got %q", maxTime)
+		}
+		opts.Filter.MaxTotalTime = d
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		field, descending := strings.CutPrefix(sort, "-")
+		switch store.SortField(field) {
+		case store.SortByName, store.SortByPublishedAt:
+			opts.Sort, opts.Descending = store.SortField(field), descending
+		default:
+			return opts, fmt.Errorf("sort must be one of name, -name, publishedAt, -publishedAt, got %q", sort)
+		}
+	}
+	return opts, nil
+}
+
+// parseLimit reads the optional limit parameter, returning zero when it
+// is absent.
+func parseLimit(c *gin.Context) (int, error) {
+	limit := c.Query("limit")
+	if limit == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 1 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	return n, nil
+}
+
+// parseServings reads the optional servings parameter, returning zero
+// when it is absent.
+func parseServings(c *gin.Context) (int, error) {
+	servings := c.Query("servings")
+	if servings == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(servings)
+	if err != nil || n < 1 {
+		return 0, errors.New("servings must be a positive integer")
+	}
+	return n, nil
+}
+
+// parseUnits reads the unit system to render amounts in from the units
+// parameter or, failing that, the Accept-Language header. It returns zero
+// when neither asks for one.
+func parseUnits(c *gin.Context) (ingredient.System, error) {
+	if sys, err := parseUnitsParam(c); sys != 0 || err != nil {
+		return sys, err
+	}
+	sys, _ := convert.SystemForLanguage(c.GetHeader("Accept-Language"))
+	return sys, nil
+}
+
+// parseUnitsParam reads the unit system asked for by the units parameter
+// alone, returning zero when it is absent.
+func parseUnitsParam(c *gin.Context) (ingredient.System, error) {
+	if units := c.Query("units"); units != "" {
+		return convert.ParseSystem(units)
+	}
+	return 0, nil
+}
+
+// parseTagsMatch reads the tags_match parameter shared by the recipe list
+// and the tag search, reporting whether every tag must match. Recipes
+// having any of the tags match by default.
+func parseTagsMatch(c *gin.Context) (bool, error) {
+	switch match := c.DefaultQuery("tags_match", "any"); match {
+	case "any":
+		return false, nil
+	case "all":
+		return true, nil
+	default:
+		return false, fmt.Errorf("tags_match must be any or all, got %q", match)
+	}
+}
+
+// parseTagQuery reads the tag and tags_match parameters of the tag search.
+// Tags prefixed with "-" are exclusions; at least one tag is required.
+func parseTagQuery(c *gin.Context) (store.Filter, error) {
+	var filter store.Filter
+	filter.Tags, filter.ExcludeTags = splitTags(c.QueryArray("tag"))
+	if len(filter.Tags) == 0 && len(filter.ExcludeTags) == 0 {
+		return filter, errors.New("at least one tag is required")
+	}
+	var err error
+	filter.MatchAllTags, err = parseTagsMatch(c)
+	return filter, err
+}
+
+// pantryQuery holds the parameters of the pantry search. maxMissing is
+// negative when unlimited.
+type pantryQuery struct {
+	owner      string
+	have       []string
+	exclude    []string
+	staples    bool
+	maxMissing int
+	limit      int
+}
+
+// parsePantryQuery reads the parameters of the pantry search. Either an
+// owner or items on hand are required.
+func parsePantryQuery(c *gin.Context) (pantryQuery, error) {
+	q := pantryQuery{
+		owner:      c.Query("owner"),
+		have:       splitList(c.QueryArray("have")),
+		exclude:    splitList(c.QueryArray("exclude")),
+		staples:    true,
+		maxMissing: -1,
+	}
+	if q.owner == "" && len(q.have) == 0 {
+		return q, errors.New("owner or have is required")
+	}
+	if staples := c.Query("staples"); staples != "" {
+		b, err := strconv.ParseBool(staples)
+		if err != nil {
+			return q, errors.New("staples must be true or false")
+		}
+		q.staples = b
+	}
+	if maxMissing := c.Query("max_missing"); maxMissing != "" {
+		n, err := strconv.Atoi(maxMissing)
+		if err != nil || n < 0 {
+			return q, errors.New("max_missing must be a non-negative integer")
+		}
+		q.maxMissing = n
+	}
+	var err error
+	q.limit, err = parseLimit(c)
+	return q, err
+}
+
+// splitList flattens repeated and comma-separated query values, dropping
+// blanks.
+func splitList(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+// splitTags separates tag parameters into included tags and the
+// exclusions written as "-tag".
+func splitTags(values []string) (include, exclude []string) {
+	for _, tag := range splitList(values) {
+		if excluded, ok := strings.CutPrefix(tag, "-"); ok {
+			if excluded != "" {
+				exclude = append(exclude, excluded)
+			}
+			continue
+		}
+		include = append(include, tag)
+	}
+	return include, exclude
+}
+
+// parseTime accepts an RFC 3339 timestamp or a YYYY-MM-DD date. A bare
+// date used as an upper bound covers the whole day.
+func parseTime(name, value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC 3339 timestamp or YYYY-MM-DD date, got %q", name, value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
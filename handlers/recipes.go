@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/convert"
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/shopping"
+	"github.com/mrojasb2000/GinRecipes/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// errRecipeNotFound is the client-facing message for store.ErrNotFound.
+var errRecipeNotFound = errors.New("Recipe not found")
+
+// Add new Recipe
+//
+//	@Summary		Operation POST /recipes recipes.
+//	@Description	Add a new recipe. Times are ISO 8601 durations; a missing totalTime is the sum of prepTime and
+//	@Description	cookTime, and a missing cuisine is taken from a tag naming one.
+//	@Tags			recipes
+//	@Accept			json
+//	@Produce		json
+//	@Param			models.Recipe	body		models.Recipe	true	"Add recipe"
+//	@Param			X-User	header	string	false	"User making the change, recorded in the history"
+//	@Success		200	{object}	models.Recipe
+//	@Header			200	{string}	ETag	"Revision of the recipe"
+//	@Failure		400	{object}	httputil.HTTPError
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes [post]
+//
+// NewRecipeHandler handles POST requests to create a new recipe.
+// It binds the JSON request body to a Recipe model, validates the input,
+// fills in the metadata defaults, generates a unique ID, sets the published timestamp to the current time,
+// stores the recipe, and returns the created recipe with HTTP 201 status.
+// If the JSON binding fails, it returns an HTTP 400 error with the validation error message.
+func (s *Server) NewRecipeHandler(c *gin.Context) {
+	var recipe models.Recipe
+	if err := c.ShouldBindJSON(&recipe); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := recipe.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recipe.Normalize()
+	recipe.ID = bson.NewObjectID().Hex()
+	recipe.PublishedAt = time.Now()
+	if err := s.store.Create(c, &recipe); err != nil {
+		log.Println("Error inserting recipe: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while inserting a new recipe"})
+		return
+	}
+	s.recordRevision(c, recipe, models.RevisionCreated, 0)
+	setETag(c, recipe.Revision)
+	c.JSON(http.StatusCreated, recipe)
+}
+
+// RecipeList is the paginated response of ListRecipesHandler.
+type RecipeList struct {
+	Recipes    []models.Recipe `json:"recipes"`
+	NextCursor string          `json:"next_cursor,omitempty" example:"eyJwIjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjoiYWJjIn0"`
+}
+
+// Recipes list
+// @Summary      Operation GET /recipes returns a list of recipes.
+// @Description  Return a page of filtered recipes. Pass the returned next_cursor, with the same sort, to fetch the following page.
+// @Tags         recipes
+// @Accept       json
+// @Produce      json
+// @Param        tags            query  string  false  "Comma-separated tags, -tag to exclude"
+// @Param        tags_match      query  string  false  "Match any (default) or all of the tags"  Enums(any, all)
+// @Param        name            query  string  false  "Name contains (case-insensitive)"
+// @Param        ingredient      query  string  false  "An ingredient contains (case-insensitive)"
+// @Param        published_from  query  string  false  "Published at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param        published_to    query  string  false  "Published at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param        difficulty      query  string  false  "Difficulty"  Enums(easy, medium, hard)
+// @Param        cuisine         query  string  false  "Cuisine (case-insensitive)"
+// @Param        author          query  string  false  "Author contains (case-insensitive)"
+// @Param        max_total_time  query  string  false  "Total time at most, as an ISO 8601 duration like PT30M; recipes without a total time are left out"
+// @Param        sort            query  string  false  "Sort order"  Enums(publishedAt, -publishedAt, name, -name)
+// @Param        limit           query  int     false  "Page size (default 100, max 1000)"
+// @Param        cursor          query  string  false  "Opaque cursor from a previous page"
+// @Success      200  {object}  RecipeList
+// @Failure		400	{object}	httputil.HTTPError
+// @Failure		500	{object}	httputil.HTTPError
+// @Router       /recipes [get]
+func (s *Server) ListRecipesHandler(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	page, err := s.store.List(c, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, RecipeList{Recipes: page.Recipes, NextCursor: page.NextCursor})
+}
+
+// RecipeDetail is a single recipe together with the structured form of its
+// ingredient lines. ScaledFrom holds the servings the stored recipe makes
+// when the recipe was rescaled for the request, and Units the unit system
+// it was converted to.
+type RecipeDetail struct {
+	models.Recipe
+	ParsedIngredients []ingredient.Ingredient `json:"parsedIngredients"`
+	ScaledFrom        int                     `json:"scaledFrom,omitempty" example:"4"`
+	Units             string                  `json:"units,omitempty" enums:"metric,us"`
+}
+
+// newRecipeDetail parses the ingredients of recipe, rescaling them to
+// servings unless servings is zero.
+func newRecipeDetail(recipe models.Recipe, servings int) RecipeDetail {
+	detail := RecipeDetail{Recipe: recipe, ParsedIngredients: ingredient.ParseAll(recipe.Ingredients)}
+	if servings == 0 || servings == recipe.Servings {
+		return detail
+	}
+	factor := float64(servings) / float64(recipe.Servings)
+	detail.Ingredients = make(models.Ingredients, len(detail.ParsedIngredients))
+	for i, ing := range detail.ParsedIngredients {
+		detail.ParsedIngredients[i] = ing.Scale(factor)
+		detail.Ingredients[i] = detail.ParsedIngredients[i].String()
+	}
+	detail.Servings, detail.ScaledFrom = servings, recipe.Servings
+	return detail
+}
+
+// convertUnits renders d in sys, including temperatures in its
+// instructions. The ingredient and instruction lists are replaced rather
+// than modified, as they may be shared with the store.
+func (d *RecipeDetail) convertUnits(sys ingredient.System) {
+	ingredients := make(models.Ingredients, len(d.ParsedIngredients))
+	for i, ing := range d.ParsedIngredients {
+		d.ParsedIngredients[i] = convert.Ingredient(ing, sys)
+		ingredients[i] = convert.Temperatures(d.ParsedIngredients[i].String(), sys)
+	}
+	instructions := make(models.Instructions, len(d.Instructions))
+	for i, instruction := range d.Instructions {
+		instructions[i] = convert.Temperatures(instruction, sys)
+	}
+	d.Ingredients, d.Instructions, d.Units = ingredients, instructions, sys.String()
+}
+
+// Get Recipe
+//
+//	@Summary		Operation GET /recipes/{id} recipes.
+//	@Description	Return a single recipe by its ID, with each ingredient line also parsed into quantity, unit, item and note.
+//	@Description	With servings, ingredient amounts are rescaled from the servings the recipe makes, converted to fitting units and rounded to kitchen fractions.
+//	@Description	With units, amounts and temperatures are rendered in metric or US customary units.
+//	@Description	The ETag is strong for the recipe as stored and weak with servings or units. If-Match only accepts the
+//	@Description	strong one, so updates must send the ETag of a plain GET.
+//	@Tags			recipes
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"Recipe ID"
+//	@Param			servings	query		int		false	"Rescale the recipe to this many servings"
+//	@Param			units		query		string	false	"Unit system to render the recipe in"	Enums(metric, us)
+//	@Success		200			{object}	RecipeDetail
+//	@Header			200			{string}	ETag	"Revision of the recipe, to send back in If-Match; weak when rescaled or converted"
+//	@Failure		400			{object}	httputil.HTTPError
+//	@Failure		404			{object}	httputil.HTTPError
+//	@Failure		422			{object}	httputil.HTTPError
+//	@Failure		500			{object}	httputil.HTTPError
+//	@Router			/recipes/{id} [get]
+func (s *Server) GetRecipeHandler(c *gin.Context) {
+	servings, err := parseServings(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	sys, err := parseUnitsParam(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	recipe, err := s.store.Get(c, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errRecipeNotFound)
+		return
+	}
+	if err != nil {
+		log.Println("Error fetching a recipe: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if servings > 0 && recipe.Servings == 0 {
+		httputil.NewError(c, http.StatusUnprocessableEntity, shopping.ErrNoServings)
+		return
+	}
+	detail := newRecipeDetail(*recipe, servings)
+	if sys != 0 {
+		detail.convertUnits(sys)
+	}
+	if detail.ScaledFrom != 0 || sys != 0 {
+		setWeakETag(c, recipe.Revision)
+	} else {
+		setETag(c, recipe.Revision)
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+// Update Recipe
+//
+//	@Summary		Operation PUT /recipes/{id} recipes.
+//	@Description	Update an existing recipe. Times are ISO 8601 durations; a missing totalTime is the sum of prepTime and
+//	@Description	cookTime, and a missing cuisine is taken from a tag naming one.
+//	@Description	Send the ETag of the recipe as read in If-Match to have the update rejected with 412 when someone
+//	@Description	else changed it in between. The revision in the body is ignored.
+//	@Tags			recipes
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Recipe ID"
+//	@Param			If-Match	header	string	false	"ETag the recipe must still have, as returned by GET"
+//	@Param			models.Recipe	body		models.Recipe	true	"Update recipe"
+//	@Param			X-User	header	string	false	"User making the change, recorded in the history"
+//	@Success		200	{object}	models.Recipe
+//	@Header			200	{string}	ETag	"Revision of the updated recipe"
+//	@Failure		400	{object}	httputil.HTTPError
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		412	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/{id} [put]
+func (s *Server) UpdateRecipeHandler(c *gin.Context) {
+	id := c.Param("id")
+	var recipe models.Recipe
+	if err := c.ShouldBindJSON(&recipe); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := recipe.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recipe.Normalize()
+	expected, err := s.expectedRevision(c, c.GetHeader("If-Match"), id)
+	if errors.Is(err, errPreconditionFailed) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Println("Error fetching a recipe: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recipe.Revision = expected
+	updated, err := s.store.Update(c, id, &recipe)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	if errors.Is(err, store.ErrRevisionMismatch) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": errPreconditionFailed.Error()})
+		return
+	}
+	if err != nil {
+		log.Println("Error updating a recipe: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.recordRevision(c, *updated, models.RevisionUpdated, 0)
+	setETag(c, updated.Revision)
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete Recipe
+//
+//	@Summary		Operation DELETE /recipes/{id} recipes.
+//	@Description	Delete an existing recipe. It is moved to the trash, from where it can be restored until it is purged.
+//	@Description	Meal plans that planned it are kept, with the affected meals flagged as recipeDeleted.
+//	@Tags			recipes
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Recipe ID"
+//	@Success		200	{object}	models.Recipe
+//	@Failure		400	{object}	httputil.HTTPError
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/{id} [delete]
+func (s *Server) DeleteRecipeHandler(c *gin.Context) {
+	id := c.Param("id")
+	err := s.store.Delete(c, id)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	if err != nil {
+		log.Println("Error deleting a recipe: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error delete a recipe"})
+		return
+	}
+	if _, err := s.plans.FlagDeletedRecipe(c, id); err != nil {
+		log.Println("Error flagging meal plans of a deleted recipe: ", err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recipe deleted"})
+}
+
+// SearchResult is a full-text search hit: the recipe, its relevance and
+// the highlighted snippets that matched.
+type SearchResult struct {
+	models.Recipe
+	Score      float64              `json:"score" example:"15"`
+	Highlights []fulltext.Highlight `json:"highlights"`
+}
+
+// IngredientMatch is a recipe found by an ingredient search. Score is the
+// share of the included ingredients the recipe uses and Matched lists
+// them, normalized to lower-case singular words.
+type IngredientMatch struct {
+	models.Recipe
+	Score   float64  `json:"score" example:"0.5"`
+	Matched []string `json:"matched" example:"chicken"`
+}
+
+// Search Recipes
+//
+//	@Summary		Operation Search Recipe GET /recipes/search?={tag} recipes.
+//	@Description	Search recipes by tags. Repeat tag or separate values with commas; prefix a tag with "-" to exclude it.
+//	@Description	When q is given, run a full-text search over name, ingredients and instructions instead and return
+//	@Description	handlers.SearchResult items ordered by relevance. Quote words in q to search for a phrase.
+//	@Tags			recipes
+//	@Accept			json
+//	@Produce		json
+//	@Param			tag		query		[]string	false	"Tags to match, -tag to exclude"	collectionFormat(multi)
+//	@Param			tags_match	query	string		false	"Match any (default) or all of the tags"	Enums(any, all)
+//	@Param			q		query		string		false	"Full-text query; quote words to match a phrase"
+//	@Param			limit	query		int			false	"Maximum full-text results (default 100, max 1000)"
+//	@Success		200	{array}		models.Recipe
+//	@Failure		400	{object}	httputil.HTTPError
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/search [get]
+func (s *Server) SearchRecipesHandler(c *gin.Context) {
+	if _, ok := c.GetQuery("q"); ok {
+		s.textSearch(c)
+		return
+	}
+	filter, err := parseTagQuery(c)
+	if err != nil && !s.searchNotFound {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	listOfRecipes := make([]models.Recipe, 0)
+	if err == nil {
+		listOfRecipes, err = s.store.Search(c, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if len(listOfRecipes) == 0 && s.searchNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	c.JSON(http.StatusOK, listOfRecipes)
+}
+
+// textSearch answers the full-text mode of SearchRecipesHandler.
+func (s *Server) textSearch(c *gin.Context) {
+	query := fulltext.Parse(c.Query("q"))
+	if query.Empty() {
+		httputil.NewError(c, http.StatusBadRequest, errors.New("q must contain at least one search term"))
+		return
+	}
+	limit, err := parseLimit(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	hits, err := s.store.TextSearch(c, query, limit)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	results := make([]SearchResult, len(hits))
+	for i, hit := range hits {
+		highlight := query
+		if len(hit.Terms) > 0 {
+			matched := fulltext.Parse(strings.Join(hit.Terms, " "))
+			highlight.Terms = append(slices.Clone(query.Terms), matched.Terms...)
+		}
+		results[i] = SearchResult{
+			Recipe:     hit.Recipe,
+			Score:      hit.Score,
+			Highlights: fulltext.Highlights(hit.Recipe, highlight),
+		}
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// Search Recipes By Ingredients
+//
+//	@Summary		Operation GET /recipes/search/ingredients recipes.
+//	@Description	Score recipes by the share of the included ingredients they use, highest first, returning the
+//	@Description	matched ingredients of each. Names are compared word by word in singular, so "chicken" matches
+//	@Description	"2 chicken breasts" and "lemons" matches "1 lemon". Recipes with an excluded ingredient or none of
+//	@Description	the included ones are left out.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			include	query		[]string	true	"Ingredients to look for"	collectionFormat(multi)
+//	@Param			exclude	query		[]string	false	"Ingredients the recipes must not use"	collectionFormat(multi)
+//	@Param			limit	query		int			false	"Maximum results (default 100, max 1000)"
+//	@Success		200		{array}		IngredientMatch
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/recipes/search/ingredients [get]
+func (s *Server) IngredientSearchHandler(c *gin.Context) {
+	query := store.NewIngredientQuery(splitList(c.QueryArray("include")), splitList(c.QueryArray("exclude")))
+	if query.Empty() {
+		httputil.NewError(c, http.StatusBadRequest, errors.New("include must name at least one ingredient"))
+		return
+	}
+	limit, err := parseLimit(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	hits, err := s.store.IngredientSearch(c, query, limit)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	matches := make([]IngredientMatch, len(hits))
+	for i, hit := range hits {
+		matches[i] = IngredientMatch{Recipe: hit.Recipe, Score: hit.Score, Matched: hit.Terms}
+	}
+	c.JSON(http.StatusOK, matches)
+}
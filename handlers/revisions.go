@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/diff"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// userHeader names the user making a change, recorded in the revision
+// history. The API does not authenticate anyone; a proxy in front of it
+// is expected to set the header.
+const userHeader = "X-User"
+
+// errRevisionNotFound is the client-facing message for
+// store.ErrRevisionNotFound.
+var errRevisionNotFound = errors.New("Recipe revision not found")
+
+// RevisionDiff is the field-level difference between two revisions of a
+// recipe. From is zero when To is compared with an empty recipe.
+type RevisionDiff struct {
+	RecipeID string        `json:"recipeId" example:"600dcc85a65917cbd1f201b0"`
+	From     int           `json:"from" example:"2"`
+	To       int           `json:"to" example:"3"`
+	Changes  []diff.Change `json:"changes"`
+}
+
+// List Recipe Revisions
+//
+//	@Summary		Operation GET /recipes/{id}/revisions recipes.
+//	@Description	List the recorded revisions of a recipe, newest first, without their recipe. Every create, update,
+//	@Description	patch and restore through the API records one.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			id	path		string	true	"Recipe ID"
+//	@Success		200	{array}		models.RecipeRevision
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/{id}/revisions [get]
+func (s *Server) ListRevisionsHandler(c *gin.Context) {
+	id := c.Param("id")
+	revisions, err := s.revisions.List(c, id)
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(revisions) == 0 {
+		_, err := s.store.Get(c, id)
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.NewError(c, http.StatusNotFound, errRecipeNotFound)
+			return
+		}
+		if err != nil {
+			httputil.NewError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// Get Recipe Revision
+//
+//	@Summary		Operation GET /recipes/{id}/revisions/{rev} recipes.
+//	@Description	Return one revision of a recipe, with the recipe as that write stored it.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			id	path		string	true	"Recipe ID"
+//	@Param			rev	path		int		true	"Revision"
+//	@Success		200	{object}	models.RecipeRevision
+//	@Failure		400	{object}	httputil.HTTPError
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/{id}/revisions/{rev} [get]
+func (s *Server) GetRevisionHandler(c *gin.Context) {
+	revision, ok := s.getRevision(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// Recipe Revision Diff
+//
+//	@Summary		Operation GET /recipes/{id}/revisions/{rev}/diff recipes.
+//	@Description	Compare a revision of a recipe with an earlier one, field by field. Tags, ingredients and instructions
+//	@Description	are compared line by line. Without from, the revision is compared with the one recorded before it, or
+//	@Description	with an empty recipe when there is none.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			id		path		string	true	"Recipe ID"
+//	@Param			rev		path		int		true	"Revision to compare"
+//	@Param			from	query		int		false	"Revision to compare with"
+//	@Success		200		{object}	RevisionDiff
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		404		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/recipes/{id}/revisions/{rev}/diff [get]
+func (s *Server) RevisionDiffHandler(c *gin.Context) {
+	from := 0
+	if param := c.Query("from"); param != "" {
+		var err error
+		if from, err = parseRevision("from", param); err != nil {
+			httputil.NewError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	to, ok := s.getRevision(c)
+	if !ok {
+		return
+	}
+	if from == 0 {
+		previous, err := s.previousRevision(c, to)
+		if err != nil {
+			httputil.NewError(c, http.StatusInternalServerError, err)
+			return
+		}
+		from = previous
+	}
+	base := models.Recipe{}
+	if from != 0 {
+		revision, err := s.revisions.Get(c, to.RecipeID, from)
+		if errors.Is(err, store.ErrRevisionNotFound) {
+			httputil.NewError(c, http.StatusNotFound, errRevisionNotFound)
+			return
+		}
+		if err != nil {
+			httputil.NewError(c, http.StatusInternalServerError, err)
+			return
+		}
+		base = *revision.Recipe
+	}
+	c.JSON(http.StatusOK, RevisionDiff{
+		RecipeID: to.RecipeID,
+		From:     from,
+		To:       to.Revision,
+		Changes:  diff.Recipes(base, *to.Recipe),
+	})
+}
+
+// Restore Recipe Revision
+//
+//	@Summary		Operation POST /recipes/{id}/revisions/{rev}/restore recipes.
+//	@Description	Make a recipe what it was at an earlier revision. The restore is a new revision; the ones in between
+//	@Description	stay in the history.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			id			path		string	true	"Recipe ID"
+//	@Param			rev			path		int		true	"Revision to restore"
+//	@Param			If-Match	header		string	false	"ETag the recipe must still have, as returned by GET"
+//	@Param			X-User		header		string	false	"User making the change, recorded in the history"
+//	@Success		200			{object}	models.Recipe
+//	@Header			200			{string}	ETag	"Revision of the restored recipe"
+//	@Failure		400			{object}	httputil.HTTPError
+//	@Failure		404			{object}	httputil.HTTPError
+//	@Failure		412			{object}	httputil.HTTPError
+//	@Failure		500			{object}	httputil.HTTPError
+//	@Router			/recipes/{id}/revisions/{rev}/restore [post]
+func (s *Server) RestoreRevisionHandler(c *gin.Context) {
+	revision, ok := s.getRevision(c)
+	if !ok {
+		return
+	}
+	expected, err := s.expectedRevision(c, c.GetHeader("If-Match"), revision.RecipeID)
+	if errors.Is(err, errPreconditionFailed) {
+		httputil.NewError(c, http.StatusPreconditionFailed, err)
+		return
+	}
+	if err != nil {
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	recipe := *revision.Recipe
+	recipe.Revision = expected
+	updated, err := s.store.Update(c, revision.RecipeID, &recipe)
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errRecipeNotFound)
+		return
+	}
+	if errors.Is(err, store.ErrRevisionMismatch) {
+		httputil.NewError(c, http.StatusPreconditionFailed, errPreconditionFailed)
+		return
+	}
+	if err != nil {
+		log.Println("Error restoring a recipe: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	s.recordRevision(c, *updated, models.RevisionRestored, revision.Revision)
+	setETag(c, updated.Revision)
+	c.JSON(http.StatusOK, updated)
+}
+
+// recordRevision adds recipe, as just stored, to its history. A failure is
+// logged rather than reported, as the write itself succeeded.
+func (s *Server) recordRevision(c *gin.Context, recipe models.Recipe, action models.RevisionAction, restoredFrom int) {
+	revision := models.RecipeRevision{
+		RecipeID:     recipe.ID,
+		Revision:     recipe.Revision,
+		Action:       action,
+		ChangedAt:    time.Now(),
+		ChangedBy:    strings.TrimSpace(c.GetHeader(userHeader)),
+		RestoredFrom: restoredFrom,
+		Recipe:       &recipe,
+	}
+	if err := s.revisions.Add(c, &revision); err != nil {
+		log.Println("Error recording a recipe revision: ", err)
+	}
+}
+
+// getRevision fetches the revision named by the id and rev path
+// parameters, responding with an error and returning false when there is
+// none.
+func (s *Server) getRevision(c *gin.Context) (*models.RecipeRevision, bool) {
+	rev, err := parseRevision("rev", c.Param("rev"))
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	revision, err := s.revisions.Get(c, c.Param("id"), rev)
+	if errors.Is(err, store.ErrRevisionNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errRevisionNotFound)
+		return nil, false
+	}
+	if err != nil {
+		log.Println("Error fetching a recipe revision: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return revision, true
+}
+
+// previousRevision returns the number of the revision recorded before
+// revision, or zero when there is none.
+func (s *Server) previousRevision(ctx context.Context, revision *models.RecipeRevision) (int, error) {
+	history, err := s.revisions.List(ctx, revision.RecipeID)
+	if err != nil {
+		return 0, err
+	}
+	for _, earlier := range history {
+		if earlier.Revision < revision.Revision {
+			return earlier.Revision, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseRevision reads a revision number given as the named parameter.
+func parseRevision(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, errors.New(name + " must be a positive revision number")
+	}
+	return n, nil
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/convert"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/shopping"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// ShoppingListRequest names the recipes to shop for. Servings rescales a
+// recipe; zero keeps its own amounts.
+type ShoppingListRequest struct {
+	Recipes []ShoppingListRecipe `json:"recipes"`
+	Units   string               `json:"units,omitempty" enums:"metric,us"`
+}
+
+// ShoppingListRecipe is one recipe of a ShoppingListRequest.
+type ShoppingListRecipe struct {
+	ID       string `json:"id" example:"600dcc85a65917cbd1f201b0"`
+	Servings int    `json:"servings,omitempty" example:"6"`
+}
+
+// Shopping List
+//
+//	@Summary		Operation POST /shopping-list shopping.
+//	@Description	Build one shopping list from several recipes, optionally rescaled. The same ingredient is merged across
+//	@Description	recipes, volumes and weights are added up after unit conversion, and items are grouped by store aisle.
+//	@Tags			shopping
+//	@Accept			json
+//	@Produce		json
+//	@Produce		plain
+//	@Produce		text/markdown
+//	@Param			request	body		ShoppingListRequest	true	"Recipes to shop for"
+//	@Param			format	query		string				false	"Output format"	Enums(json, text, markdown)	default(json)
+//	@Success		200		{object}	shopping.List
+//	@Failure		400		{object}	httputil.HTTPError
+//	@Failure		404		{object}	httputil.HTTPError
+//	@Failure		422		{object}	httputil.HTTPError
+//	@Failure		500		{object}	httputil.HTTPError
+//	@Router			/shopping-list [post]
+func (s *Server) ShoppingListHandler(c *gin.Context) {
+	format, err := parseShoppingFormat(c)
+	if err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	var req ShoppingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.NewError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Recipes) == 0 {
+		httputil.NewError(c, http.StatusBadRequest, errors.New("at least one recipe is required"))
+		return
+	}
+	var sys ingredient.System
+	if req.Units != "" {
+		if sys, err = convert.ParseSystem(req.Units); err != nil {
+			httputil.NewError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	list, err := s.shoppingList(c, req.Recipes, sys)
+	if err != nil {
+		httputil.NewError(c, shoppingListStatus(err), err)
+		return
+	}
+	writeShoppingList(c, format, list)
+}
+
+// parseShoppingFormat reads the format query parameter of the shopping
+// list endpoints.
+func parseShoppingFormat(c *gin.Context) (string, error) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" && format != "markdown" {
+		return "", fmt.Errorf("unknown format %q; expected json, text or markdown", format)
+	}
+	return format, nil
+}
+
+// writeShoppingList responds with list in the given format.
+func writeShoppingList(c *gin.Context, format string, list shopping.List) {
+	switch format {
+	case "text":
+		c.String(http.StatusOK, list.Text())
+	case "markdown":
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(list.Markdown()))
+	default:
+		c.JSON(http.StatusOK, list)
+	}
+}
+
+// shoppingList fetches the requested recipes and merges their ingredients.
+func (s *Server) shoppingList(ctx context.Context, recipes []ShoppingListRecipe, sys ingredient.System) (shopping.List, error) {
+	entries := make([]shopping.Entry, len(recipes))
+	for i, r := range recipes {
+		if r.Servings < 0 {
+			return shopping.List{}, errInvalidServings
+		}
+		recipe, err := s.store.Get(ctx, r.ID)
+		if errors.Is(err, store.ErrNotFound) {
+			return shopping.List{}, fmt.Errorf("recipe %q: %w", r.ID, err)
+		}
+		if err != nil {
+			return shopping.List{}, err
+		}
+		if r.Servings > 0 && recipe.Servings == 0 {
+			return shopping.List{}, fmt.Errorf("recipe %q: %w", r.ID, shopping.ErrNoServings)
+		}
+		entries[i] = shopping.Entry{Recipe: *recipe, Servings: r.Servings}
+	}
+	return shopping.Build(entries, s.nutrients, sys)
+}
+
+var errInvalidServings = errors.New("servings must not be negative")
+
+// shoppingListStatus maps an error of shoppingList to a response status.
+func shoppingListStatus(err error) int {
+	switch {
+	case errors.Is(err, errInvalidServings):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, shopping.ErrNoServings):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
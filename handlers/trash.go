@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// errNotInTrash is the client-facing message for store.ErrNotFound when
+// restoring a recipe.
+var errNotInTrash = errors.New("Recipe not found in the trash")
+
+// List Deleted Recipes
+//
+//	@Summary		Operation GET /recipes/trash recipes.
+//	@Description	List the deleted recipes, most recently deleted first. They stay in the trash, with the date they were
+//	@Description	deleted, until they are restored or purged once the retention period has passed.
+//	@Tags			recipes
+//	@Produce		json
+//	@Success		200	{array}		models.Recipe
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/trash [get]
+func (s *Server) TrashHandler(c *gin.Context) {
+	recipes, err := s.store.Trash(c)
+	if err != nil {
+		log.Println("Error listing deleted recipes: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, recipes)
+}
+
+// Restore Deleted Recipe
+//
+//	@Summary		Operation POST /recipes/{id}/restore recipes.
+//	@Description	Take a recipe out of the trash. Meals planned with it are no longer flagged as recipeDeleted.
+//	@Tags			recipes
+//	@Produce		json
+//	@Param			id	path		string	true	"Recipe ID"
+//	@Success		200	{object}	models.Recipe
+//	@Header			200	{string}	ETag	"Revision of the recipe"
+//	@Failure		404	{object}	httputil.HTTPError
+//	@Failure		500	{object}	httputil.HTTPError
+//	@Router			/recipes/{id}/restore [post]
+func (s *Server) RestoreRecipeHandler(c *gin.Context) {
+	id := c.Param("id")
+	recipe, err := s.store.Restore(c, id)
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.NewError(c, http.StatusNotFound, errNotInTrash)
+		return
+	}
+	if err != nil {
+		log.Println("Error restoring a deleted recipe: ", err)
+		httputil.NewError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := s.plans.ClearDeletedRecipe(c, id); err != nil {
+		log.Println("Error clearing meal plans of a restored recipe: ", err)
+	}
+	setETag(c, recipe.Revision)
+	c.JSON(http.StatusOK, recipe)
+}
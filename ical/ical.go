@@ -0,0 +1,172 @@
+// Package ical writes iCalendar (RFC 5545) calendars of timed events, as
+// used for meal plan feeds.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendar is a VCALENDAR object. Name is shown by calendar apps that
+// honor the X-WR-CALNAME extension, and RefreshInterval, when set, tells
+// subscribers how often to fetch the calendar again.
+type Calendar struct {
+	ProdID          string
+	Name            string
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is a VEVENT. Start and End are written as floating times: their
+// wall clock is kept and the time zone dropped, so a breakfast at 08:00
+// shows at 08:00 wherever the calendar is viewed. Stamp, the time the
+// event was last changed, is written in UTC.
+type Event struct {
+	UID         string
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+}
+
+const (
+	floatingLayout = "20060102T150405"
+	utcLayout      = "20060102T150405Z"
+
+	// maxLineOctets is the longest content line allowed before folding,
+	// excluding the line break.
+	maxLineOctets = 75
+)
+
+// WriteTo writes c to w in the iCalendar format, with CRLF line endings
+// and long lines folded. It implements io.WriterTo.
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", escapeText(c.ProdID))
+	cw.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		cw.line("REFRESH-INTERVAL;VALUE=DURATION", duration(c.RefreshInterval))
+		cw.line("X-PUBLISHED-TTL", duration(c.RefreshInterval))
+	}
+	for _, e := range c.Events {
+		e.write(cw)
+	}
+	cw.line("END", "VCALENDAR")
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// String returns c in the iCalendar format.
+func (c Calendar) String() string {
+	var b strings.Builder
+	c.WriteTo(&b)
+	return b.String()
+}
+
+func (e Event) write(cw *contentWriter) {
+	cw.line("BEGIN", "VEVENT")
+	cw.line("UID", escapeText(e.UID))
+	cw.line("DTSTAMP", e.Stamp.UTC().Format(utcLayout))
+	cw.line("DTSTART", e.Start.Format(floatingLayout))
+	if !e.End.IsZero() {
+		cw.line("DTEND", e.End.Format(floatingLayout))
+	}
+	cw.line("SUMMARY", escapeText(e.Summary))
+	if e.Description != "" {
+		cw.line("DESCRIPTION", escapeText(e.Description))
+	}
+	if e.URL != "" {
+		cw.line("URL", e.URL)
+	}
+	if len(e.Categories) > 0 {
+		escaped := make([]string, len(e.Categories))
+		for i, category := range e.Categories {
+			escaped[i] = escapeText(category)
+		}
+		cw.line("CATEGORIES", strings.Join(escaped, ","))
+	}
+	cw.line("END", "VEVENT")
+}
+
+// contentWriter writes folded content lines, remembering the first error.
+type contentWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+// line writes "name:value", folding it into lines of at most
+// maxLineOctets octets without splitting a UTF-8 sequence. Continuation
+// lines start with a space, which counts towards their length.
+func (cw *contentWriter) line(name, value string) {
+	s := name + ":" + value
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		cw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	cw.write(s + "\r\n")
+}
+
+func (cw *contentWriter) write(s string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText escapes a TEXT property value.
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// duration formats d as an RFC 5545 duration of whole seconds, such as
+// PT12H or PT1H30M.
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	var b strings.Builder
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		b.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		b.WriteString("T")
+		for _, unit := range []struct {
+			size   time.Duration
+			suffix string
+		}{{time.Hour, "H"}, {time.Minute, "M"}, {time.Second, "S"}} {
+			if n := d / unit.size; n > 0 {
+				b.WriteString(strconv.FormatInt(int64(n), 10) + unit.suffix)
+				d -= n * unit.size
+			}
+		}
+	}
+	if b.Len() == 1 {
+		return "PT0S"
+	}
+	return b.String()
+}
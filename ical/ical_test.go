@@ -0,0 +1,129 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	goical "github.com/emersion/go-ical"
+)
+
+func testCalendar() Calendar {
+	return Calendar{
+		ProdID:          "-//GinRecipes//Meal plans//EN",
+		Name:            "Maria's meals",
+		RefreshInterval: 12 * time.Hour,
+		Events: []Event{{
+			UID:         "plan1-20240304-dinner@recipes",
+			Stamp:       time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600)),
+			Start:       time.Date(2024, 3, 4, 19, 0, 0, 0, time.UTC),
+			End:         time.Date(2024, 3, 4, 20, 0, 0, 0, time.UTC),
+			Summary:     "Dinner: Pizza; extra cheese, please",
+			Description: "Ingredients:\n- 3 3/4 cups (490 g) bread flour\n- 1 1/2 cups (355 ml) warm water (105°F-115°F)\n\n" + strings.Repeat("Knead the dough. ", 12),
+			URL:         "http://localhost:8080/api/v1/recipes/600dcc85a65917cbd1f201b0",
+			Categories:  []string{"dinner", "meal plan"},
+		}},
+	}
+}
+
+func TestCalendar_WriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := testCalendar().WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, expected %d bytes written", n, buf.Len())
+	}
+
+	text := buf.String()
+	if !strings.HasSuffix(text, "END:VCALENDAR\r\n") {
+		t.Errorf("WriteTo() does not end with a CRLF terminated END:VCALENDAR")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("WriteTo() line of %d octets: %q", len(line), line)
+		}
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("WriteTo() bare line break in %q", line)
+		}
+	}
+	for _, expected := range []string{
+		"DTSTAMP:20240301T083000Z\r\n",
+		"DTSTART:20240304T190000\r\n",
+		`SUMMARY:Dinner: Pizza\; extra cheese\, please` + "\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT12H\r\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("WriteTo() missing %q", expected)
+		}
+	}
+}
+
+// TestCalendar_RoundTrip decodes the output with an independent iCalendar
+// implementation, which also enforces the properties RFC 5545 requires
+// when encoding it again.
+func TestCalendar_RoundTrip(t *testing.T) {
+	expected := testCalendar()
+	decoded, err := goical.NewDecoder(strings.NewReader(expected.String())).Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	var reencoded bytes.Buffer
+	if err := goical.NewEncoder(&reencoded).Encode(decoded); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err = goical.NewDecoder(&reencoded).Decode()
+	if err != nil {
+		t.Fatalf("Decode() re-encoded error = %v", err)
+	}
+
+	events := decoded.Events()
+	if len(events) != 1 {
+		t.Fatalf("Events() = %d events, expected 1", len(events))
+	}
+	event, want := events[0], expected.Events[0]
+	for name, value := range map[string]string{
+		goical.PropUID:         want.UID,
+		goical.PropSummary:     want.Summary,
+		goical.PropDescription: want.Description,
+	} {
+		got, err := event.Props.Text(name)
+		if err != nil || got != value {
+			t.Errorf("%s = %q (%v), expected %q", name, got, err, value)
+		}
+	}
+	if got := event.Props.Get(goical.PropURL).Value; got != want.URL {
+		t.Errorf("URL = %q, expected %q", got, want.URL)
+	}
+	categories, err := event.Props.Get(goical.PropCategories).TextList()
+	if err != nil || strings.Join(categories, "|") != "dinner|meal plan" {
+		t.Errorf("CATEGORIES = %q (%v), expected dinner and meal plan", categories, err)
+	}
+	start, err := event.DateTimeStart(time.UTC)
+	if err != nil || !start.Equal(want.Start) {
+		t.Errorf("DTSTART = %v (%v), expected %v", start, err, want.Start)
+	}
+	end, err := event.DateTimeEnd(time.UTC)
+	if err != nil || !end.Equal(want.End) {
+		t.Errorf("DTEND = %v (%v), expected %v", end, err, want.End)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "PT0S"},
+		{12 * time.Hour, "PT12H"},
+		{90 * time.Minute, "PT1H30M"},
+		{36 * time.Hour, "P1DT12H"},
+	}
+	for _, tt := range tests {
+		if got := duration(tt.d); got != tt.expected {
+			t.Errorf("duration(%v) = %s, expected %s", tt.d, got, tt.expected)
+		}
+	}
+}
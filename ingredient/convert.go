@@ -0,0 +1,35 @@
+package ingredient
+
+// Convert re-expresses the amounts of ing in sys. When ing already gives
+// the amount in sys as its alternate, as "1 1/2 cups (355 ml)" does, that
+// figure replaces the primary one as written; otherwise amounts are
+// converted and rounded like scaled ones, and an alternate that merely
+// restates the converted amount is dropped. Volumes stay volumes and
+// weights stay weights, and units such as cloves or cans are left alone.
+func (ing Ingredient) Convert(sys System) Ingredient {
+	if ing.Quantity == nil {
+		return ing
+	}
+	info, ok := convertible[ing.Unit]
+	if ok && info.system != sys {
+		if a := ing.Alternate; a != nil && !a.Each && convertible[a.Unit].system == sys {
+			q := a.Quantity
+			ing.Quantity, ing.Unit, ing.Alternate = &q, a.Unit, nil
+			return ing
+		}
+		converted := Measure{Quantity: *ing.Quantity, Unit: ing.Unit}.Tidy(sys)
+		ing.Quantity, ing.Unit = &converted.Quantity, converted.Unit
+	}
+	if a := ing.Alternate; a != nil {
+		alt, altOK := convertible[a.Unit]
+		switch {
+		case !altOK || alt.system == sys:
+		case ok && !a.Each && alt.dimension == info.dimension:
+			ing.Alternate = nil
+		default:
+			converted := a.Tidy(sys)
+			ing.Alternate = &converted
+		}
+	}
+	return ing
+}
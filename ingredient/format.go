@@ -0,0 +1,129 @@
+package ingredient
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// kitchenFractions are the fractions measuring cups and spoons come in.
+var kitchenFractions = []struct {
+	value float64
+	text  string
+}{
+	{0, ""}, {1.0 / 8, "1/8"}, {1.0 / 4, "1/4"}, {1.0 / 3, "1/3"}, {3.0 / 8, "3/8"},
+	{1.0 / 2, "1/2"}, {5.0 / 8, "5/8"}, {2.0 / 3, "2/3"}, {3.0 / 4, "3/4"}, {7.0 / 8, "7/8"}, {1, ""},
+}
+
+// String formats q with kitchen fractions, e.g. "1 1/2" or "6 to 7".
+func (q Quantity) String() string {
+	if q.IsRange() {
+		return formatNumber(q.Value) + " to " + formatNumber(q.Max)
+	}
+	return formatNumber(q.Value)
+}
+
+func formatNumber(v float64) string {
+	whole, frac := math.Modf(v)
+	for _, f := range kitchenFractions {
+		if math.Abs(frac-f.value) > 0.01 {
+			continue
+		}
+		if f.value == 1 {
+			whole++
+		}
+		switch {
+		case f.text == "":
+			return strconv.FormatFloat(whole, 'f', -1, 64)
+		case whole == 0:
+			return f.text
+		default:
+			return strconv.FormatFloat(whole, 'f', -1, 64) + " " + f.text
+		}
+	}
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// roundKitchen rounds v to what can be measured in unit: whole or
+// rounded grams and milliliters for metric units and the nearest kitchen
+// fraction otherwise. Positive amounts never round down to zero.
+func roundKitchen(v float64, unit string) float64 {
+	if v <= 0 {
+		return v
+	}
+	var rounded float64
+	switch unit {
+	case "gram", "milliliter":
+		step := 1.0
+		switch {
+		case v < 10:
+			step = 0.5
+		case v >= 100:
+			step = 5
+		}
+		rounded = math.Round(v/step) * step
+		if rounded == 0 {
+			rounded = step
+		}
+	case "kilogram", "liter":
+		rounded = math.Round(v*20) / 20
+		if rounded == 0 {
+			rounded = 0.05
+		}
+	default:
+		if v >= 10 {
+			return math.Round(v)
+		}
+		whole, frac := math.Modf(v)
+		best := kitchenFractions[0].value
+		for _, f := range kitchenFractions {
+			if math.Abs(frac-f.value) < math.Abs(frac-best) {
+				best = f.value
+			}
+		}
+		rounded = whole + best
+		if rounded == 0 {
+			rounded = kitchenFractions[1].value
+		}
+	}
+	return rounded
+}
+
+// unitLabel spells unit in the singular or plural to go with q.
+func unitLabel(unit string, q Quantity) string {
+	if q.Value > 1 || q.Max > 1 {
+		return pluralUnit(unit)
+	}
+	return unit
+}
+
+// String formats m as "1 1/2 cups".
+func (m Measure) String() string {
+	if m.Unit == "" {
+		return m.Quantity.String()
+	}
+	return m.Quantity.String() + " " + unitLabel(m.Unit, m.Quantity)
+}
+
+// String renders ing as an ingredient line such as "1 1/2 cups (355
+// milliliters) warm water, 105F°-115F°". Lines without a quantity are
+// returned as written.
+func (ing Ingredient) String() string {
+	if ing.Unparsed || ing.Quantity == nil {
+		return strings.TrimSpace(ing.Raw)
+	}
+	var b strings.Builder
+	b.WriteString(Measure{Quantity: *ing.Quantity, Unit: ing.Unit}.String())
+	if a := ing.Alternate; a != nil {
+		b.WriteString(" (" + a.String())
+		if a.Each {
+			b.WriteString(" each")
+		}
+		b.WriteString(")")
+	}
+	b.WriteString(" " + ing.Item)
+	if ing.Note != "" {
+		b.WriteString(", " + ing.Note)
+	}
+	return b.String()
+}
@@ -0,0 +1,244 @@
+// Package ingredient parses free-text ingredient lines such as
+// "1 1/2 cups (355 ml) warm water (105F°-115F°)" into a quantity, unit,
+// alternate measure, item and preparation note.
+package ingredient
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Measure is a quantity with its unit, e.g. the "(355 ml)" alternate of
+// "1 1/2 cups". Each is set when the measure is the size of one item, as
+// in "4 (6-ounce) salmon fillets", rather than the whole amount.
+type Measure struct {
+	Quantity Quantity `json:"quantity"`
+	Unit     string   `json:"unit" example:"milliliter"`
+	Each     bool     `json:"each,omitempty"`
+}
+
+// Ingredient is the structured form of one ingredient line. Raw always
+// holds the original text. Lines that cannot be understood have Unparsed
+// set and Raw, trimmed, as their Item.
+type Ingredient struct {
+	Raw       string    `json:"raw" example:"1 1/2 cups (355 ml) warm water (105F°-115F°)"`
+	Quantity  *Quantity `json:"quantity,omitempty"`
+	Unit      string    `json:"unit,omitempty" example:"cup"`
+	Alternate *Measure  `json:"alternate,omitempty"`
+	Item      string    `json:"item" example:"warm water"`
+	Note      string    `json:"note,omitempty" example:"105F°-115F°"`
+	Unparsed  bool      `json:"unparsed,omitempty"`
+}
+
+// ParseAll parses every line of an ingredient list.
+func ParseAll(lines []string) []Ingredient {
+	out := make([]Ingredient, len(lines))
+	for i, line := range lines {
+		out[i] = Parse(line)
+	}
+	return out
+}
+
+// Parse parses one ingredient line. It never fails: a line without any
+// recognizable item is returned verbatim with Unparsed set.
+func Parse(line string) Ingredient {
+	ing := Ingredient{Raw: line}
+	rest := strings.TrimSpace(strings.ReplaceAll(line, "⁄", "/"))
+	if !strings.ContainsFunc(htmlTagRe.ReplaceAllString(rest, ""), unicode.IsLetter) {
+		return unparsed(ing)
+	}
+
+	if q, after, ok := parseQuantity(rest); ok {
+		ing.Quantity = q
+		rest = ing.takeAlternate(after)
+		if unit, after := takeUnit(rest); unit != "" {
+			ing.Unit, rest = unit, after
+			if ing.Alternate == nil {
+				rest = ing.takeAlternate(rest)
+			}
+		}
+	} else if unit, after := takeUnit(rest); unit != "" && strings.HasPrefix(after, "of ") {
+		// "Pinch of salt" is one pinch.
+		ing.Quantity, ing.Unit, rest = &Quantity{Value: 1}, unit, after
+	}
+	rest = strings.TrimPrefix(rest, "of ")
+
+	item, note := splitNote(rest)
+	item, asides := extractParens(item)
+	var notes []string
+	for _, aside := range asides {
+		if m, ok := parseMeasure(aside); ok && ing.Alternate == nil {
+			m.Each = m.Each || ing.Unit == ""
+			ing.Alternate = m
+			continue
+		}
+		notes = append(notes, aside)
+	}
+	if note != "" {
+		notes = append(notes, note)
+	}
+	ing.Item = cleanText(markdownLinkRe.ReplaceAllString(item, "$1"))
+	ing.Note = strings.Join(notes, ", ")
+	if ing.Item == "" {
+		return unparsed(ing)
+	}
+	return ing
+}
+
+func unparsed(ing Ingredient) Ingredient {
+	return Ingredient{Raw: ing.Raw, Item: strings.TrimSpace(ing.Raw), Unparsed: true}
+}
+
+// htmlTagRe matches markup such as the "<hr>" separators found between
+// ingredient groups in the seed data.
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// markdownLinkRe matches the "[Sushi Rice](http://...)" links some seed
+// recipes use to reference other recipes.
+var markdownLinkRe = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+
+// sizeRe matches a size written as a compound word, e.g. "6-ounce" or
+// "14oz".
+var sizeRe = regexp.MustCompile(`^` + numberPattern + `-?[A-Za-z.]+`)
+
+// takeAlternate consumes an alternate measure written as "(355 ml)" or
+// "6-ounce" at the start of s. Sizes written as compound words, or given
+// for a count without a unit, are per item.
+func (ing *Ingredient) takeAlternate(s string) string {
+	if strings.HasPrefix(s, "(") {
+		if end := closingParen(s); end > 0 {
+			if m, ok := parseMeasure(s[1:end]); ok {
+				m.Each = m.Each || ing.Unit == ""
+				ing.Alternate = m
+				return strings.TrimSpace(s[end+1:])
+			}
+		}
+		return s
+	}
+	if size := sizeRe.FindString(s); size != "" {
+		if m, ok := parseMeasure(size); ok {
+			m.Each = true
+			ing.Alternate = m
+			return strings.TrimSpace(s[len(size):])
+		}
+	}
+	return s
+}
+
+// parseMeasure parses text such as "about 4 ounces", "2 1/4 teaspoons"
+// or "6 to 7-ounce". Nothing but "each" may follow the unit.
+func parseMeasure(s string) (*Measure, bool) {
+	s = strings.TrimSpace(s)
+	for _, prefix := range []string{"about ", "approx. ", "approximately ", "roughly "} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	q, rest, ok := parseQuantity(s)
+	if !ok {
+		return nil, false
+	}
+	unit, rest := takeUnit(rest)
+	if unit == "" || (rest != "" && rest != "each") {
+		return nil, false
+	}
+	return &Measure{Quantity: *q, Unit: unit, Each: rest == "each"}, true
+}
+
+// takeUnit consumes a unit at the start of s, returning "" when s does
+// not start with one.
+func takeUnit(s string) (string, string) {
+	s = strings.TrimPrefix(s, "-")
+	words := strings.Fields(s)
+	if len(words) >= 2 {
+		if unit := lookupUnit(words[0] + " " + strings.TrimRight(words[1], ",")); unit != "" {
+			return unit, afterWords(s, 2)
+		}
+	}
+	word := leadingWord(s)
+	if word == "" {
+		return "", s
+	}
+	if next := s[len(word):]; next != "" && !strings.ContainsAny(next[:1], " ,()") {
+		return "", s
+	}
+	unit := lookupUnit(word)
+	if unit == "" {
+		return "", s
+	}
+	return unit, strings.TrimSpace(s[len(word):])
+}
+
+// afterWords returns s without its first n whitespace-separated words.
+func afterWords(s string, n int) string {
+	for range n {
+		s = strings.TrimSpace(s)
+		if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+			s = s[i:]
+		} else {
+			s = ""
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// splitNote splits s at its first comma outside parentheses into the item
+// and the preparation note.
+func splitNote(s string) (string, string) {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				return s[:i], strings.TrimSpace(s[i+1:])
+			}
+		}
+	}
+	return s, ""
+}
+
+// extractParens removes the parenthesized asides of s, returning the
+// remaining text and the asides without their parentheses. Markdown links
+// are left alone.
+func extractParens(s string) (string, []string) {
+	var asides []string
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '(' && (i == 0 || s[i-1] != ']') {
+			if end := closingParen(s[i:]); end > 0 {
+				asides = append(asides, strings.TrimSpace(s[i+1:i+end]))
+				i += end
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String(), asides
+}
+
+// closingParen returns the index of the parenthesis closing the one at
+// s[0], or -1.
+func closingParen(s string) int {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// cleanText collapses whitespace and trims separators left over after
+// removing quantities and asides.
+func cleanText(s string) string {
+	return strings.Trim(strings.Join(strings.Fields(s), " "), " ,;:-")
+}
@@ -0,0 +1,195 @@
+package ingredient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Ingredient
+	}{
+		{
+			line: "1 1/2 cups (355 ml) warm water (105F°-115F°)",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 1.5},
+				Unit:      "cup",
+				Alternate: &Measure{Quantity: Quantity{Value: 355}, Unit: "milliliter"},
+				Item:      "warm water",
+				Note:      "105F°-115F°",
+			},
+		},
+		{
+			line: "3 3/4 cups (490 g) bread flour",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 3.75},
+				Unit:      "cup",
+				Alternate: &Measure{Quantity: Quantity{Value: 490}, Unit: "gram"},
+				Item:      "bread flour",
+			},
+		},
+		{
+			line: "1 package (2 1/4 teaspoons) of active dry yeast",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 1},
+				Unit:      "package",
+				Alternate: &Measure{Quantity: Quantity{Value: 2.25}, Unit: "teaspoon"},
+				Item:      "active dry yeast",
+			},
+		},
+		{
+			line: "4 (6 to 7-ounce) boneless skinless chicken breasts\r",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 4},
+				Alternate: &Measure{Quantity: Quantity{Value: 6, Max: 7}, Unit: "ounce", Each: true},
+				Item:      "boneless skinless chicken breasts",
+			},
+		},
+		{
+			line: "4 6-ounce center-cut skinless halibut fillets (1 3/4 inches thick)\r",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 4},
+				Alternate: &Measure{Quantity: Quantity{Value: 6}, Unit: "ounce", Each: true},
+				Item:      "center-cut skinless halibut fillets",
+				Note:      "1 3/4 inches thick",
+			},
+		},
+		{
+			line: "1 small parsnip (about 4 ounces), peeled and coarsely chopped\r",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 1},
+				Alternate: &Measure{Quantity: Quantity{Value: 4}, Unit: "ounce", Each: true},
+				Item:      "small parsnip",
+				Note:      "peeled and coarsely chopped",
+			},
+		},
+		{
+			line: "2 bunches kale (1 1/2 lbs), tough stems and ribs removed, leaves coarsely chopped\r",
+			expected: Ingredient{
+				Quantity:  &Quantity{Value: 2},
+				Unit:      "bunch",
+				Alternate: &Measure{Quantity: Quantity{Value: 1.5}, Unit: "pound"},
+				Item:      "kale",
+				Note:      "tough stems and ribs removed, leaves coarsely chopped",
+			},
+		},
+		{
+			line: "1/2 pound bacon, cut into large lardons",
+			expected: Ingredient{
+				Quantity: &Quantity{Value: 0.5},
+				Unit:     "pound",
+				Item:     "bacon",
+				Note:     "cut into large lardons",
+			},
+		},
+		{
+			line: "1-2 tbsp olive oil",
+			expected: Ingredient{
+				Quantity: &Quantity{Value: 1, Max: 2},
+				Unit:     "tablespoon",
+				Item:     "olive oil",
+			},
+		},
+		{
+			line: "½ cup buttermilk",
+			expected: Ingredient{
+				Quantity: &Quantity{Value: 0.5},
+				Unit:     "cup",
+				Item:     "buttermilk",
+			},
+		},
+		{
+			line: "8 fl oz milk",
+			expected: Ingredient{
+				Quantity: &Quantity{Value: 8},
+				Unit:     "fluid ounce",
+				Item:     "milk",
+			},
+		},
+		{
+			line: "1 serving [Sherry Vinaigrette](http://example.com/sherry-vinaigrette)",
+			expected: Ingredient{
+				Quantity: &Quantity{Value: 1},
+				Unit:     "serving",
+				Item:     "Sherry Vinaigrette",
+			},
+		},
+		{
+			line: "Sour cream or Greek yogurt, for serving (optional)",
+			expected: Ingredient{
+				Item: "Sour cream or Greek yogurt",
+				Note: "for serving (optional)",
+			},
+		},
+		{
+			line: "Pinch of freshly ground white pepper",
+			expected: Ingredient{
+				Quantity: &Quantity{Value: 1},
+				Unit:     "pinch",
+				Item:     "freshly ground white pepper",
+			},
+		},
+		{
+			line:     "salt and pepper",
+			expected: Ingredient{Item: "salt and pepper"},
+		},
+		{
+			line:     "<hr>\r",
+			expected: Ingredient{Item: "<hr>", Unparsed: true},
+		},
+		{
+			line:     "2 1/2",
+			expected: Ingredient{Item: "2 1/2", Unparsed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.expected.Raw = tt.line
+		if got := Parse(tt.line); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Parse(%q) = %+v, expected %+v", tt.line, got, tt.expected)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Quantity
+		rest     string
+	}{
+		{"1 1/2 cups", Quantity{Value: 1.5}, "cups"},
+		{"1½ cups", Quantity{Value: 1.5}, "cups"},
+		{"¾ teaspoon", Quantity{Value: 0.75}, "teaspoon"},
+		{"0.5 kg", Quantity{Value: 0.5}, "kg"},
+		{"6 to 7-ounce", Quantity{Value: 6, Max: 7}, "-ounce"},
+		{"2 or 3 limes", Quantity{Value: 2, Max: 3}, "limes"},
+	}
+
+	for _, tt := range tests {
+		got, rest, ok := parseQuantity(tt.input)
+		if !ok || *got != tt.expected || rest != tt.rest {
+			t.Errorf("parseQuantity(%q) = %+v, %q, %v, expected %+v, %q", tt.input, got, rest, ok, tt.expected, tt.rest)
+		}
+	}
+}
+
+func TestForms(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected []string
+	}{
+		{"chicken", []string{"chicken", "chickens"}},
+		{"tomato", []string{"tomato", "tomatoes", "tomatos"}},
+		{"peach", []string{"peach", "peachs", "peaches"}},
+		{"berry", []string{"berry", "berrys", "berries"}},
+		{"leaf", []string{"leaf", "leaves", "leafs"}},
+		{"molasses", []string{"molasses"}},
+	}
+
+	for _, tt := range tests {
+		if got := Forms(tt.word); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Forms(%q) = %q, expected %q", tt.word, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ingredient
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Words splits an ingredient name into lower-case singular words, so that
+// "Chicken Breasts" and "chicken breast" compare equal.
+func Words(name string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	words := fields[:0]
+	for _, w := range fields {
+		if len(w) > 1 {
+			words = append(words, Singular(w))
+		}
+	}
+	return words
+}
+
+// irregularPlurals are the plurals of ingredient words that the suffix
+// rules in Singular get wrong.
+var irregularPlurals = map[string]string{
+	"leaves": "leaf", "loaves": "loaf", "halves": "half", "knives": "knife",
+	"potatoes": "potato", "tomatoes": "tomato", "mangoes": "mango", "molasses": "molasses",
+}
+
+// Singular returns the singular of a lower-case English noun, handling the
+// regular plurals found in ingredient lists.
+func Singular(word string) string {
+	if s, ok := irregularPlurals[word]; ok {
+		return s
+	}
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 4 && (strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes") ||
+		strings.HasSuffix(word, "xes") || strings.HasSuffix(word, "sses")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// Forms returns the spellings Singular maps to the singular word, starting
+// with the word itself, so that matchers working on raw text, such as
+// database queries, can find its plurals too.
+func Forms(word string) []string {
+	forms := []string{word}
+	for plural, singular := range irregularPlurals {
+		if singular == word && plural != word {
+			forms = append(forms, plural)
+		}
+	}
+	candidates := []string{word + "s", word + "es"}
+	if strings.HasSuffix(word, "y") {
+		candidates = append(candidates, word[:len(word)-1]+"ies")
+	}
+	for _, plural := range candidates {
+		if Singular(plural) == word && !slices.Contains(forms, plural) {
+			forms = append(forms, plural)
+		}
+	}
+	return forms
+}
@@ -0,0 +1,113 @@
+package ingredient
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Quantity is an amount, or a range of amounts when Max is set.
+type Quantity struct {
+	Value float64 `json:"value" example:"1.5"`
+	Max   float64 `json:"max,omitempty" example:"2"`
+}
+
+// IsRange reports whether q spans a range of amounts.
+func (q Quantity) IsRange() bool {
+	return q.Max > q.Value
+}
+
+var vulgarFractions = map[rune]float64{
+	'½': 1.0 / 2, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 1.0 / 4, '¾': 3.0 / 4,
+	'⅛': 1.0 / 8, '⅜': 3.0 / 8, '⅝': 5.0 / 8, '⅞': 7.0 / 8,
+}
+
+const numberPattern = `(?:\d+\s+\d+/\d+|\d+/\d+|\d+\s*[½⅓⅔¼¾⅛⅜⅝⅞]|[½⅓⅔¼¾⅛⅜⅝⅞]|\d*\.\d+|\d+)`
+
+// quantityRe matches a leading number or range such as "1 1/2", "½",
+// "6 to 7" or "1-2".
+var quantityRe = regexp.MustCompile(`^(` + numberPattern + `)(?:\s*(?:-|–|to|or)\s*(` + numberPattern + `))?`)
+
+// parseQuantity consumes a quantity at the start of s and returns it with
+// the remaining text.
+func parseQuantity(s string) (*Quantity, string, bool) {
+	m := quantityRe.FindStringSubmatchIndex(s)
+	if m == nil {
+		return nil, s, false
+	}
+	rest := s[m[1]:]
+	// "2 1/2cups" is fine but "12th" or "3D" is not a quantity.
+	if rest != "" && !strings.ContainsAny(rest[:1], " -(") && startsWithLetterOrDigit(rest) && lookupUnit(leadingWord(rest)) == "" {
+		return nil, s, false
+	}
+	value, ok := parseNumber(s[m[2]:m[3]])
+	if !ok {
+		return nil, s, false
+	}
+	q := &Quantity{Value: value}
+	if m[4] >= 0 {
+		max, ok := parseNumber(s[m[4]:m[5]])
+		if !ok || max <= value {
+			// "1-2" reads as a range but "2 or 1" does not; keep the first
+			// number and leave the rest to the item.
+			return q, strings.TrimSpace(s[m[3]:]), true
+		}
+		q.Max = max
+	}
+	return q, strings.TrimSpace(rest), true
+}
+
+// parseNumber parses whole numbers, decimals, fractions, mixed numbers and
+// vulgar fraction characters.
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	var total float64
+	for _, r := range s {
+		if f, ok := vulgarFractions[r]; ok {
+			total += f
+			s = strings.TrimSpace(strings.ReplaceAll(s, string(r), ""))
+		}
+	}
+	for _, part := range strings.Fields(s) {
+		if num, den, ok := strings.Cut(part, "/"); ok {
+			n, err1 := strconv.ParseFloat(num, 64)
+			d, err2 := strconv.ParseFloat(den, 64)
+			if err1 != nil || err2 != nil || d == 0 {
+				return 0, false
+			}
+			total += n / d
+			continue
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, false
+		}
+		total += v
+	}
+	return total, true
+}
+
+func startsWithLetterOrDigit(s string) bool {
+	c := s[0]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// leadingWord returns the letters and dots at the start of s.
+func leadingWord(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '.')
+	})
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
+
+// Mean returns the amount q stands for: its value, or the middle of its
+// range.
+func (q Quantity) Mean() float64 {
+	if q.IsRange() {
+		return (q.Value + q.Max) / 2
+	}
+	return q.Value
+}
@@ -0,0 +1,44 @@
+package ingredient
+
+// scale multiplies both ends of q by factor.
+func (q Quantity) scale(factor float64) Quantity {
+	return Quantity{Value: q.Value * factor, Max: q.Max * factor}
+}
+
+// Tidy re-expresses m in the kitchen unit of sys that suits its size and
+// rounds it to a measurable amount; a zero sys keeps the unit's own
+// system. Units that cannot be converted keep their unit and are only
+// rounded.
+func (m Measure) Tidy(sys System) Measure {
+	if info, ok := convertible[m.Unit]; ok {
+		if sys == 0 {
+			sys = info.system
+		}
+		unit := kitchenUnit(info.dimension, sys, m.Quantity.Value*info.base)
+		m.Quantity = m.Quantity.scale(info.base / convertible[unit].base)
+		m.Unit = unit
+	}
+	m.Quantity.Value = roundKitchen(m.Quantity.Value, m.Unit)
+	m.Quantity.Max = roundKitchen(m.Quantity.Max, m.Unit)
+	if !m.Quantity.IsRange() {
+		m.Quantity.Max = 0
+	}
+	return m
+}
+
+// Scale multiplies the amounts of ing by factor, switching to a unit
+// that reads naturally at the new size (48 teaspoons become 1 cup) and
+// rounding to kitchen fractions. Per-item sizes such as "(6-ounce)" are
+// not scaled, and ingredients without a quantity are returned unchanged.
+func (ing Ingredient) Scale(factor float64) Ingredient {
+	if ing.Quantity == nil {
+		return ing
+	}
+	scaled := Measure{Quantity: ing.Quantity.scale(factor), Unit: ing.Unit}.Tidy(convertible[ing.Unit].system)
+	ing.Quantity, ing.Unit = &scaled.Quantity, scaled.Unit
+	if a := ing.Alternate; a != nil && !a.Each {
+		alt := Measure{Quantity: a.Quantity.scale(factor), Unit: a.Unit}.Tidy(convertible[a.Unit].system)
+		ing.Alternate = &alt
+	}
+	return ing
+}
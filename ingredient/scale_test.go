@@ -0,0 +1,50 @@
+package ingredient
+
+import "testing"
+
+func TestIngredient_Scale(t *testing.T) {
+	tests := []struct {
+		line     string
+		factor   float64
+		expected string
+	}{
+		{"16 teaspoons sugar", 3, "1 cup sugar"},
+		{"1 tablespoon olive oil", 0.25, "3/4 teaspoon olive oil"},
+		{"1/2 cup grated Parmesan cheese (about 1 oz)\r", 2, "1 cup (2 ounces) grated Parmesan cheese"},
+		{"1 1/2 cups (355 ml) warm water (105F°-115F°)", 2, "3 cups (710 milliliters) warm water, 105F°-115F°"},
+		{"3 3/4 cups (490 g) bread flour", 0.5, "1 7/8 cups (245 grams) bread flour"},
+		{"4 (6 to 7-ounce) boneless skinless chicken breasts\r", 1.5, "6 (6 to 7 ounces each) boneless skinless chicken breasts"},
+		{"12 ounces spaghetti", 2, "1 1/2 pounds spaghetti"},
+		{"1-2 tbsp olive oil", 3, "3 to 6 tablespoons olive oil"},
+		{"2 cloves garlic, minced", 1.0 / 3, "2/3 clove garlic, minced"},
+		{"1 egg", 0.1, "1/8 egg"},
+		{"salt and pepper", 4, "salt and pepper"},
+		{"<hr>", 2, "<hr>"},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.line).Scale(tt.factor).String(); got != tt.expected {
+			t.Errorf("Parse(%q).Scale(%v) = %q, expected %q", tt.line, tt.factor, got, tt.expected)
+		}
+	}
+}
+
+func TestQuantity_String(t *testing.T) {
+	tests := []struct {
+		quantity Quantity
+		expected string
+	}{
+		{Quantity{Value: 2}, "2"},
+		{Quantity{Value: 1.5}, "1 1/2"},
+		{Quantity{Value: 1.0 / 3}, "1/3"},
+		{Quantity{Value: 0.999}, "1"},
+		{Quantity{Value: 0.4}, "0.4"},
+		{Quantity{Value: 6, Max: 7}, "6 to 7"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.quantity.String(); got != tt.expected {
+			t.Errorf("%+v.String() = %q, expected %q", tt.quantity, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package ingredient
+
+import "strings"
+
+// unitAliases maps the spellings found in recipes to canonical unit
+// names. Keys are lower case except single letters, where "T" is a
+// tablespoon and "t" a teaspoon.
+var unitAliases = map[string]string{
+	"cup": "cup", "cups": "cup", "c": "cup",
+	"tablespoon": "tablespoon", "tablespoons": "tablespoon", "tbsp": "tablespoon", "tbsps": "tablespoon", "tbs": "tablespoon", "tbl": "tablespoon", "T": "tablespoon",
+	"teaspoon": "teaspoon", "teaspoons": "teaspoon", "tsp": "teaspoon", "tsps": "teaspoon", "t": "teaspoon",
+	"fluid ounce": "fluid ounce", "fluid ounces": "fluid ounce", "fl oz": "fluid ounce", "fl. oz": "fluid ounce",
+	"ounce": "ounce", "ounces": "ounce", "oz": "ounce",
+	"pound": "pound", "pounds": "pound", "lb": "pound", "lbs": "pound",
+	"gram": "gram", "grams": "gram", "g": "gram", "gr": "gram",
+	"kilogram": "kilogram", "kilograms": "kilogram", "kg": "kilogram",
+	"milliliter": "milliliter", "milliliters": "milliliter", "millilitre": "milliliter", "millilitres": "milliliter", "ml": "milliliter",
+	"liter": "liter", "liters": "liter", "litre": "liter", "litres": "liter", "l": "liter",
+	"pint": "pint", "pints": "pint", "pt": "pint",
+	"quart": "quart", "quarts": "quart", "qt": "quart",
+	"gallon": "gallon", "gallons": "gallon", "gal": "gallon",
+	"inch": "inch", "inches": "inch",
+	"pinch": "pinch", "pinches": "pinch",
+	"dash": "dash", "dashes": "dash",
+	"clove": "clove", "cloves": "clove",
+	"can": "can", "cans": "can",
+	"jar": "jar", "jars": "jar",
+	"bottle": "bottle", "bottles": "bottle",
+	"package": "package", "packages": "package", "pkg": "package",
+	"packet": "packet", "packets": "packet",
+	"bag": "bag", "bags": "bag",
+	"box": "box", "boxes": "box",
+	"bar": "bar", "bars": "bar",
+	"stick": "stick", "sticks": "stick",
+	"slice": "slice", "slices": "slice",
+	"piece": "piece", "pieces": "piece",
+	"bunch": "bunch", "bunches": "bunch",
+	"head": "head", "heads": "head",
+	"sprig": "sprig", "sprigs": "sprig",
+	"stalk": "stalk", "stalks": "stalk",
+	"handful": "handful", "handfuls": "handful",
+	"serving": "serving", "servings": "serving",
+}
+
+// lookupUnit returns the canonical unit for word, or "" when word is not
+// a unit.
+func lookupUnit(word string) string {
+	word = strings.TrimSuffix(word, ".")
+	if len(word) == 1 {
+		return unitAliases[word]
+	}
+	return unitAliases[strings.ToLower(word)]
+}
+
+// Dimension is the physical quantity a unit measures.
+type Dimension int
+
+const (
+	Volume Dimension = iota + 1
+	Mass
+)
+
+// System is the measurement system a unit belongs to.
+type System int
+
+const (
+	USCustomary System = iota + 1
+	Metric
+)
+
+// unitInfo describes a convertible unit; base is its size in milliliters
+// or grams.
+type unitInfo struct {
+	dimension Dimension
+	system    System
+	base      float64
+}
+
+var convertible = map[string]unitInfo{
+	"teaspoon":    {Volume, USCustomary, 4.92892},
+	"tablespoon":  {Volume, USCustomary, 14.7868},
+	"fluid ounce": {Volume, USCustomary, 29.5735},
+	"cup":         {Volume, USCustomary, 236.588},
+	"pint":        {Volume, USCustomary, 473.176},
+	"quart":       {Volume, USCustomary, 946.353},
+	"gallon":      {Volume, USCustomary, 3785.41},
+	"milliliter":  {Volume, Metric, 1},
+	"liter":       {Volume, Metric, 1000},
+	"ounce":       {Mass, USCustomary, 28.3495},
+	"pound":       {Mass, USCustomary, 453.592},
+	"gram":        {Mass, Metric, 1},
+	"kilogram":    {Mass, Metric, 1000},
+}
+
+// UnitDimension returns the dimension and system of a canonical unit, or
+// false when the unit is not convertible (a clove, a can, a pinch).
+func UnitDimension(unit string) (Dimension, System, bool) {
+	info, ok := convertible[unit]
+	return info.dimension, info.system, ok
+}
+
+// kitchenStep is a unit cooks measure with, used once an amount reaches
+// min of it.
+type kitchenStep struct {
+	unit string
+	min  float64
+}
+
+// kitchenUnits lists, smallest first, the units amounts are expressed in
+// after scaling or conversion. Fluid ounces, pints and quarts are read
+// but never produced.
+var kitchenUnits = map[Dimension]map[System][]kitchenStep{
+	Volume: {
+		USCustomary: {{"teaspoon", 0}, {"tablespoon", 1}, {"cup", 0.25}, {"gallon", 1}},
+		Metric:      {{"milliliter", 0}, {"liter", 1}},
+	},
+	Mass: {
+		USCustomary: {{"ounce", 0}, {"pound", 1}},
+		Metric:      {{"gram", 0}, {"kilogram", 1}},
+	},
+}
+
+// kitchenUnit picks the largest kitchen unit of sys that expresses amount,
+// given in milliliters or grams, as at least that unit's minimum.
+func kitchenUnit(dim Dimension, sys System, amount float64) string {
+	steps := kitchenUnits[dim][sys]
+	unit := steps[0].unit
+	for _, step := range steps[1:] {
+		if amount/convertible[step.unit].base >= step.min-1e-9 {
+			unit = step.unit
+		}
+	}
+	return unit
+}
+
+// pluralUnit returns the plural spelling of a canonical unit.
+func pluralUnit(unit string) string {
+	switch {
+	case strings.HasSuffix(unit, "ch"), strings.HasSuffix(unit, "sh"), strings.HasSuffix(unit, "x"), strings.HasSuffix(unit, "s"):
+		return unit + "es"
+	default:
+		return unit + "s"
+	}
+}
+
+// String returns "us" or "metric".
+func (s System) String() string {
+	switch s {
+	case USCustomary:
+		return "us"
+	case Metric:
+		return "metric"
+	default:
+		return ""
+	}
+}
+
+// Base returns the mean amount of m in milliliters or grams, with its
+// dimension. It returns false when the unit is not convertible.
+func (m Measure) Base() (float64, Dimension, bool) {
+	info, ok := convertible[m.Unit]
+	if !ok {
+		return 0, 0, false
+	}
+	return m.Quantity.Mean() * info.base, info.dimension, true
+}
+
+// FromBase expresses amount, in milliliters or grams depending on dim, in
+// the kitchen unit of sys that suits it, rounded like a scaled amount.
+func FromBase(amount float64, dim Dimension, sys System) Measure {
+	unit := "milliliter"
+	if dim == Mass {
+		unit = "gram"
+	}
+	return Measure{Quantity: Quantity{Value: amount}, Unit: unit}.Tidy(sys)
+}
@@ -0,0 +1,431 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7386) and JSON Patch
+// (RFC 6902) documents to JSON values.
+//
+// Both work on a decoded copy of the target and only return the patched
+// document when every change applied, so a failed patch leaves nothing
+// half done.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Media types of the two patch formats.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// ErrInvalidPatch is returned for patch documents that are not well
+// formed, such as an unknown operation or a path that is not a JSON
+// Pointer.
+var ErrInvalidPatch = errors.New("invalid patch document")
+
+// ErrTestFailed is returned when a JSON Patch test operation does not
+// match the document.
+var ErrTestFailed = errors.New("test operation failed")
+
+// MergePatch applies the JSON Merge Patch patch to doc: objects in patch
+// are merged into doc recursively, null removes a member and any other
+// value replaces the one in doc.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	members, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(object, name)
+		} else {
+			object[name] = mergePatch(object[name], value)
+		}
+	}
+	return object
+}
+
+// Operation is one step of a JSON Patch. Value is nil when the operation
+// has no value member, and holds "null" when it is null. From cannot be
+// the empty pointer, as the whole document cannot be moved or copied into
+// itself.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a JSON Patch document: operations applied in order.
+type Patch []Operation
+
+// DecodePatch parses and checks a JSON Patch document.
+func DecodePatch(data []byte) (Patch, error) {
+	var patch Patch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	for i, op := range patch {
+		if err := op.check(); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalidPatch, i, err)
+		}
+	}
+	return patch, nil
+}
+
+func (op Operation) check() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return fmt.Errorf("%s needs a value", op.Op)
+		}
+	case "move", "copy":
+		if op.From == "" {
+			return fmt.Errorf("%s needs a from path", op.Op)
+		}
+		if _, err := parsePointer(op.From); err != nil {
+			return fmt.Errorf("from: %v", err)
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	if _, err := parsePointer(op.Path); err != nil {
+		return fmt.Errorf("path: %v", err)
+	}
+	return nil
+}
+
+// Apply applies p to doc. The error names the first operation that could
+// not be applied.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range p {
+		if target, err = op.apply(target); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (op Operation) apply(doc any) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		}
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		if op.Op == "copy" {
+			return add(doc, path, clone(value))
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("cannot move a value into itself")
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+}
+
+// add sets the member or inserts the array element path points to; "-"
+// appends to an array.
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = value
+			return c, nil
+		case []any:
+			i := len(c)
+			if token != "-" {
+				var err error
+				if i, err = index(token, len(c)+1); err != nil {
+					return nil, err
+				}
+			}
+			return append(c[:i], append([]any{value}, c[i:]...)...), nil
+		}
+		return nil, fmt.Errorf("cannot add %q to a %s", token, kind(container))
+	})
+}
+
+// replace sets the existing member or array element path points to.
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container any, token string) (any, error) {
+		if _, err := child(container, token); err != nil {
+			return nil, err
+		}
+		return setChild(container, token, value), nil
+	})
+}
+
+// remove deletes the member or array element path points to.
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	return update(doc, path, func(container any, token string) (any, error) {
+		if _, err := child(container, token); err != nil {
+			return nil, err
+		}
+		switch c := container.(type) {
+		case map[string]any:
+			delete(c, token)
+			return c, nil
+		case []any:
+			i, _ := index(token, len(c))
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return container, nil
+	})
+}
+
+// get returns the value path points to.
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		var err error
+		if doc, err = child(doc, token); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// update calls fn on the container holding the last token of path and
+// returns doc with the container fn returns in its place. Containers are
+// returned rather than changed in place as appending to an array may move
+// it.
+func update(doc any, path []string, fn func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	next, err := child(doc, path[0])
+	if err != nil {
+		return nil, err
+	}
+	if next, err = update(next, path[1:], fn); err != nil {
+		return nil, err
+	}
+	return setChild(doc, path[0], next), nil
+}
+
+// child returns the member or element token names in container.
+func child(container any, token string) (any, error) {
+	switch c := container.(type) {
+	case map[string]any:
+		value, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("no member %q", token)
+		}
+		return value, nil
+	case []any:
+		i, err := index(token, len(c))
+		if err != nil {
+			return nil, err
+		}
+		return c[i], nil
+	}
+	return nil, fmt.Errorf("cannot look up %q in a %s", token, kind(container))
+}
+
+// setChild sets the existing member or element token names in container,
+// which child has already found.
+func setChild(container any, token string, value any) any {
+	switch c := container.(type) {
+	case map[string]any:
+		c[token] = value
+	case []any:
+		i, _ := index(token, len(c))
+		c[i] = value
+	}
+	return container
+}
+
+// index parses an array index, which must be below n and, as RFC 6901
+// requires, have no leading zeros.
+func index(token string, n int) (int, error) {
+	if token == "" || strings.Trim(token, "0123456789") != "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= n {
+		return 0, fmt.Errorf("array index %s out of range", token)
+	}
+	return i, nil
+}
+
+var (
+	// escapes drops valid escapes, so that any ~ left is invalid.
+	escapes   = strings.NewReplacer("~0", "", "~1", "")
+	unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped
+// reference tokens. The empty pointer, the whole document, has none.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("JSON pointer %q does not start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if strings.Contains(escapes.Replace(token), "~") {
+			return nil, fmt.Errorf("JSON pointer %q has an invalid ~ escape", pointer)
+		}
+		tokens[i] = unescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+// decode parses a single JSON value, keeping numbers as written.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// equal compares JSON values, numbers by their value, as the test
+// operation does.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, value := range a {
+			other, ok := b[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	}
+	return a == b
+}
+
+// clone deep-copies a decoded JSON value.
+func clone(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for name, value := range v {
+			c[name] = clone(value)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, value := range v {
+			c[i] = clone(value)
+		}
+		return c
+	}
+	return v
+}
+
+func kind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
@@ -0,0 +1,148 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// jsonEqual compares JSON documents regardless of member order.
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+	var x, y any
+	if err := json.Unmarshal([]byte(a), &x); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", a, err)
+	}
+	if err := json.Unmarshal([]byte(b), &y); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", b, err)
+	}
+	return equal(x, y)
+}
+
+// The cases are the examples of RFC 7386, appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("MergePatch(%s, %s) error = %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if !jsonEqual(t, string(got), tt.expected) {
+			t.Errorf("MergePatch(%s, %s) = %s, expected %s", tt.doc, tt.patch, got, tt.expected)
+		}
+	}
+	if _, err := MergePatch([]byte(`{}`), []byte(`{"a":`)); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("MergePatch() malformed error = %v, expected ErrInvalidPatch", err)
+	}
+}
+
+// Most cases are the examples of RFC 6902, appendix A.
+func TestPatch_Apply(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, expected string
+		wantErr                    error
+	}{
+		{"Add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`, nil},
+		{"Add element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, nil},
+		{"Remove member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`, nil},
+		{"Remove element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`, nil},
+		{"Replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`, nil},
+		{"Move member", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`, nil},
+		{"Move element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`, nil},
+		{"Test", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`, nil},
+		{"Test fails", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, "", ErrTestFailed},
+		{"Add nested", `{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`, nil},
+		{"Add to missing parent", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, "", nil},
+		{"Escaped path", `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`, nil},
+		{"Add array", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`, nil},
+		{"Copy", `{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`, `{"foo":{"bar":1},"baz":{"bar":2}}`, nil},
+		{"Replace root", `{"foo":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`, nil},
+		{"Null value", `{"foo":1}`, `[{"op":"replace","path":"/foo","value":null}]`, `{"foo":null}`, nil},
+		{"Replace missing", `{"foo":1}`, `[{"op":"replace","path":"/bar","value":2}]`, "", nil},
+		{"Index out of range", `{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":2}]`, "", nil},
+		{"Leading zero", `{"foo":[1,2]}`, `[{"op":"remove","path":"/foo/01"}]`, "", nil},
+		{"Move into child", `{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`, "", nil},
+		{"Not atomic otherwise", `{"foo":1}`, `[{"op":"remove","path":"/foo"},{"op":"remove","path":"/foo"}]`, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := DecodePatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("DecodePatch() error = %v", err)
+			}
+			got, err := patch.Apply([]byte(tt.doc))
+			if tt.expected == "" {
+				if err == nil {
+					t.Errorf("Apply() = %s, expected an error", got)
+				} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("Apply() error = %v, expected %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !jsonEqual(t, string(got), tt.expected) {
+				t.Errorf("Apply() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodePatch_Invalid(t *testing.T) {
+	for _, patch := range []string{
+		`{"op":"add","path":"/a","value":1}`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"replace","path":"a","value":1}]`,
+		`[{"op":"move","path":"/a"}]`,
+		`[{"op":"remove","path":"/a~2"}]`,
+		`[{"op":"merge","path":"/a","value":1}]`,
+	} {
+		if _, err := DecodePatch([]byte(patch)); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("DecodePatch(%s) error = %v, expected ErrInvalidPatch", patch, err)
+		}
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		pointer  string
+		expected []string
+	}{
+		{"", nil},
+		{"/", []string{""}},
+		{"/a~1b/m~0n/0", []string{"a/b", "m~n", "0"}},
+		{"/~01", []string{"~1"}},
+	}
+	for _, tt := range tests {
+		got, err := parsePointer(tt.pointer)
+		if err != nil || len(got) != len(tt.expected) {
+			t.Errorf("parsePointer(%q) = %q, %v, expected %q", tt.pointer, got, err, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("parsePointer(%q) = %q, expected %q", tt.pointer, got, tt.expected)
+			}
+		}
+	}
+}
@@ -4,222 +4,118 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"time"
-
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	docs "github.com/mrojasb2000/GinRecipes/docs"
-	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/handlers"
+	"github.com/mrojasb2000/GinRecipes/migrations"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+	"github.com/mrojasb2000/GinRecipes/searchindex"
+	"github.com/mrojasb2000/GinRecipes/store"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
-var recipes []models.Recipe
-var ctx context.Context
-var err error
-var client *mongo.Client
-var collection *mongo.Collection
-
-func init() {
-	ctx = context.Background()
-	client, err = mongo.Connect(options.Client().ApplyURI(os.Getenv("MONGO_URI")))
-	if err != nil {
-		panic(err)
-	}
-
-	// defer func() {
-	// 	if err := client.Disconnect(ctx); err != nil {
-	// 		panic(err)
-	// 	}
-	// }()
-	if err = client.Ping(context.TODO(), readpref.Primary()); err != nil {
-		panic(err)
-	}
-	collection = client.Database(os.Getenv("MONGO_DATABASE")).Collection("recipes")
-	log.Println("Connected to MongoDB!")
+// indexFlushInterval is how often the server saves the changes made to
+// the search index since the last save.
+const indexFlushInterval = 5 * time.Second
+
+// shutdownTimeout bounds how long the server waits for the requests in
+// flight when shutting down.
+const shutdownTimeout = 10 * time.Second
+
+// stores are the storage backends of the API. migrator is nil for the
+// in-memory backends, which need no migrations, and index is nil unless
+// SEARCH_INDEX_PATH is set.
+type stores struct {
+	recipes   store.RecipeStore
+	plans     store.MealPlanStore
+	pantries  store.PantryStore
+	revisions store.RevisionStore
+	migrator  *migrations.Migrator
+	index     *searchindex.Index
 }
 
-// Add new Recipe
-//
-//	@Summary		Operation POST /recipes recipes.
-//	@Description	Add a new recipe.
-//	@Tags			recipes
-//	@Accept			json
-//	@Produce		json
-//	@Param			models.Recipe	body		models.Recipe	true	"Add recipe"
-//	@Success		200	{object}	models.Recipe
-//	@Failure		400	{object}	httputil.HTTPError
-//	@Failure		404	{object}	httputil.HTTPError
-//	@Failure		500	{object}	httputil.HTTPError
-//	@Router			/recipes [post]
-//
-// NewRecipeHandler handles POST requests to create a new recipe.
-// It binds the JSON request body to a Recipe model, validates the input,
-// generates a unique ID using xid, sets the published timestamp to the current time,
-// appends the recipe to the recipes slice, and returns the created recipe with HTTP 201 status.
-// If the JSON binding fails, it returns an HTTP 400 error with the validation error message.
-func NewRecipeHandler(c *gin.Context) {
-	var recipe models.Recipe
-	if err := c.ShouldBindJSON(&recipe); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	recipe.Id = primitive.NewObjectID().Hex()
-	recipe.PublishedAt = time.Now()
-	_, err := collection.InsertOne(c, recipe)
-	if err != nil {
-		log.Println("Error inserting recipe: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while inserting a new recipe"})
-		return
+// close saves what the stores still hold in memory before the process
+// exits.
+func (b stores) close() error {
+	if b.index == nil {
+		return nil
 	}
-	c.JSON(http.StatusCreated, recipe)
+	return b.index.Flush()
 }
 
-// Recipes list
-// @Summary      Operation GET /recipes returns a list of recipes.
-// @Description  Return a recipes list
-// @Tags         recipes
-// @Accept       json
-// @Produce      json
-// @Success      200  {object}  models.Recipe
-// @Failure		400	{object}	httputil.HTTPError
-// @Failure		404	{object}	httputil.HTTPError
-// @Failure		500	{object}	httputil.HTTPError
-// @Router       /recipes [get]
-func ListRecipesHandler(c *gin.Context) {
-	cur, err := collection.Find(c, bson.D{})
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	defer cur.Close(c)
-	recipes = make([]models.Recipe, 0)
-	for cur.Next(c) {
-		if err := cur.All(c, &recipes); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+// newStore returns the stores selected by the environment. When MONGO_URI
+// is set the recipes, mealplans, pantries and revisions collections of
+// MONGO_DATABASE are used, otherwise everything is kept in memory for the
+// lifetime of the process.
+func newStore(ctx context.Context) (stores, error) {
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		log.Println("MONGO_URI not set, using in-memory store")
+		return stores{
+			recipes:   store.NewMemoryStore(),
+			plans:     store.NewMemoryMealPlanStore(),
+			pantries:  store.NewMemoryPantryStore(),
+			revisions: store.NewMemoryRevisionStore(),
+		}, nil
 	}
-	c.JSON(http.StatusOK, recipes)
-}
-
-// Update Recipe
-//
-//	@Summary		Operation PUT /recipes/{id} recipes.
-//	@Description	Update an existing recipe.
-//	@Tags			recipes
-//	@Accept			json
-//	@Produce		json
-//	@Param			id	path		string	true	"Recipe ID"
-//	@Param			models.Recipe	body		models.Recipe	true	"Update recipe"
-//	@Success		200	{object}	models.Recipe
-//	@Failure		400	{object}	httputil.HTTPError
-//	@Failure		404	{object}	httputil.HTTPError
-//	@Failure		500	{object}	httputil.HTTPError
-//	@Router			/recipes/{id} [put]
-func UpdateRecipeHandler(c *gin.Context) {
-	id := c.Param("id")
-	var recipe models.Recipe
-	if err := c.ShouldBindJSON(&recipe); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	_, err := collection.UpdateOne(c, bson.M{
-		"id": id,
-	}, bson.D{{
-		Key: "$set", Value: bson.D{
-			{Key: "name", Value: recipe.Name},
-			{Key: "instructions", Value: recipe.Instructions},
-			{Key: "ingredients", Value: recipe.Ingredients},
-			{Key: "tags", Value: recipe.Tags},
-		}}})
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
 	if err != nil {
-		log.Println("Error updating a recipe: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		return stores{}, err
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Recipe has been updated"})
-}
-
-// Delete Recipe
-//
-//	@Summary		Operation DELETE /recipes/{id} recipes.
-//	@Description	Delete an existing recipe.
-//	@Tags			recipes
-//	@Accept			json
-//	@Produce		json
-//	@Param			id	path		string	true	"Recipe ID"
-//	@Success		200	{object}	models.Recipe
-//	@Failure		400	{object}	httputil.HTTPError
-//	@Failure		404	{object}	httputil.HTTPError
-//	@Failure		500	{object}	httputil.HTTPError
-//	@Router			/recipes/{id} [delete]
-func DeleteRecipeHandler(c *gin.Context) {
-	id := c.Param("id")
-
-	if !checkRecipeExistInDb(id) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
-		return
-	}
-
-	filter := bson.M{
-		"id": id,
-	}
-	_, err := collection.DeleteOne(c, filter)
-	if err != nil {
-		log.Println("Error deleting a recipe: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error delete a recipe"})
-		return
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		return stores{}, err
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Recipe has been deleted"})
-}
-
-// checkRecipeExistInDb checks if a recipe with the given ID exists in the database.
-func checkRecipeExistInDb(id string) bool {
-	filter := bson.M{
-		"id": id,
+	log.Println("Connected to MongoDB!")
+	database := client.Database(os.Getenv("MONGO_DATABASE"))
+	recipes := database.Collection("recipes")
+	mongoStore := store.NewMongoStore(recipes)
+	if err := mongoStore.EnsureIndexes(ctx); err != nil {
+		return stores{}, err
 	}
-	var recipe models.Recipe
-	err := collection.FindOne(ctx, filter).Decode(&recipe)
+	migrator, err := migrations.New(recipes, migrations.All)
 	if err != nil {
-		log.Println("Error deleting a recipe: ", err)
-		return false
+		return stores{}, err
 	}
-	return true
-}
-
-// Search Recipes
-//
-//	@Summary		Operation Search Recipe GET /recipes/search?={tag} recipes.
-//	@Description	Search an existing recipe.
-//	@Tags			recipes
-//	@Accept			json
-//	@Produce		json
-//	@Param			tag	query		string	true	"Tag Recipe"
-//	@Success		200	{object}	models.Recipe
-//	@Failure		400	{object}	httputil.HTTPError
-//	@Failure		404	{object}	httputil.HTTPError
-//	@Failure		500	{object}	httputil.HTTPError
-//	@Router			/recipes/search [get]
-func SearchRecipesHandler(c *gin.Context) {
-	tag := c.Query("tag")
-	listOfRecipes := make([]models.Recipe, 0)
-	for _, recipe := range recipes {
-		if recipe.Tags.Contains(tag) {
-			listOfRecipes = append(listOfRecipes, recipe)
-		}
+	planStore := store.NewMongoMealPlanStore(database.Collection("mealplans"))
+	if err := planStore.EnsureIndexes(ctx); err != nil {
+		return stores{}, err
+	}
+	pantryStore := store.NewMongoPantryStore(database.Collection("pantries"))
+	if err := pantryStore.EnsureIndexes(ctx); err != nil {
+		return stores{}, err
 	}
-	if len(listOfRecipes) > 0 {
-		c.JSON(http.StatusOK, listOfRecipes)
-		return
+	revisionStore := store.NewMongoRevisionStore(database.Collection("revisions"))
+	if err := revisionStore.EnsureIndexes(ctx); err != nil {
+		return stores{}, err
 	}
-	c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+	return stores{
+		recipes:   mongoStore,
+		plans:     planStore,
+		pantries:  pantryStore,
+		revisions: revisionStore,
+		migrator:  migrator,
+	}, nil
+}
+
+// setupRouter builds the gin engine serving the API on /api/v1.
+func setupRouter(server *handlers.Server) *gin.Engine {
+	router := gin.Default()
+	docs.SwaggerInfo.BasePath = "/api/v1"
+	server.RegisterRoutes(router.Group("/api/v1"))
+
+	// use ginSwagger middleware to serve the API docs
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	return router
 }
 
 // @title           Recipes Example API.
@@ -240,16 +136,82 @@ func SearchRecipesHandler(c *gin.Context) {
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://bramworks.com/resources/open-api/
 func main() {
-	router := gin.Default()
-	docs.SwaggerInfo.BasePath = "/api/v1"
-	router.POST("api/v1/recipes", NewRecipeHandler)
-	router.GET("api/v1/recipes", ListRecipesHandler)
-	router.PUT("api/v1/recipes/:id", UpdateRecipeHandler)
-	router.DELETE("api/v1/recipes/:id", DeleteRecipeHandler)
-	router.GET("api/v1/recipes/search", SearchRecipesHandler)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := runCommand(ctx, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	// use ginSwagger middleware to serve the API docs
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+// runServe serves the API. When SEARCH_INDEX_PATH is set, full-text
+// search is answered by the embedded search index stored there,
+// NUTRITION_TABLE_PATH names foods that extend or replace the bundled
+// nutrient table, CALENDAR_SECRET signs the calendar feed URLs and
+// TRASH_RETENTION sets how long deleted recipes are kept. The server
+// shuts down gracefully once ctx is done, saving the search index last.
+func runServe(ctx context.Context, _ []string) error {
+	retention, err := trashRetention()
+	if err != nil {
+		return err
+	}
+	backends, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	if retention > 0 {
+		go runPurger(ctx, backends, retention)
+	}
+	if backends.index != nil {
+		go backends.index.FlushEvery(ctx, indexFlushInterval)
+	}
+	searchNotFound, _ := strconv.ParseBool(os.Getenv("SEARCH_NOT_FOUND_404"))
+	opts := []handlers.Option{
+		handlers.WithSearchNotFound(searchNotFound),
+		handlers.WithMealPlanStore(backends.plans),
+		handlers.WithPantryStore(backends.pantries),
+		handlers.WithRevisionStore(backends.revisions),
+	}
+	if secret := os.Getenv("CALENDAR_SECRET"); secret != "" {
+		opts = append(opts, handlers.WithCalendarSecret([]byte(secret)))
+	}
+	if path := os.Getenv("NUTRITION_TABLE_PATH"); path != "" {
+		table, err := nutrition.Load(path)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, handlers.WithNutritionTable(table))
+	}
+	router := setupRouter(handlers.NewServer(backends.recipes, opts...))
+	err = serve(ctx, &http.Server{Addr: listenAddr(), Handler: router})
+	if closeErr := backends.close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// serve runs server until it fails or ctx is done, then waits up to
+// shutdownTimeout for the requests in flight to finish.
+func serve(ctx context.Context, server *http.Server) error {
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("Listening and serving HTTP on %s", server.Addr)
+		errc <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
 
-	router.Run()
+// listenAddr returns the address to serve on, port PORT or 8080 of every
+// interface as with gin's Run.
+func listenAddr() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
 }
@@ -2,30 +2,46 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	goical "github.com/emersion/go-ical"
 	"github.com/gin-gonic/gin"
+	"github.com/mrojasb2000/GinRecipes/diff"
+	"github.com/mrojasb2000/GinRecipes/handlers"
+	"github.com/mrojasb2000/GinRecipes/httputil"
+	"github.com/mrojasb2000/GinRecipes/mealplan"
+	"github.com/mrojasb2000/GinRecipes/migrations"
 	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+	"github.com/mrojasb2000/GinRecipes/shopping"
+	"github.com/mrojasb2000/GinRecipes/store"
 	"github.com/stretchr/testify/assert"
 )
 
+var testStore *store.MemoryStore
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	router := gin.Default()
-	router.POST("/api/v1/recipes", NewRecipeHandler)
-	router.GET("/api/v1/recipes", ListRecipesHandler)
-	router.PUT("/api/v1/recipes/:id", UpdateRecipeHandler)
-	router.DELETE("/api/v1/recipes/:id", DeleteRecipeHandler)
-	router.GET("/api/v1/recipes/search", SearchRecipesHandler)
-	return router
+	if testStore == nil {
+		setupTestData()
+	}
+	return setupRouter(handlers.NewServer(testStore))
 }
 
 func setupTestData() {
-	recipes = []models.Recipe{
+	testStore = store.NewMemoryStore([]models.Recipe{
 		{
 			ID:           "test1",
 			Name:         "Test Pizza",
@@ -42,7 +58,13 @@ func setupTestData() {
 			Instructions: []string{"boil pasta", "add sauce"},
 			PublishedAt:  time.Now(),
 		},
-	}
+	}...)
+}
+
+func countRecipes(t *testing.T) int {
+	page, err := testStore.List(context.Background(), store.ListOptions{})
+	assert.NoError(t, err)
+	return len(page.Recipes)
 }
 
 func TestListRecipesHandler(t *testing.T) {
@@ -55,10 +77,549 @@ func TestListRecipesHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response []models.Recipe
+	var response handlers.RecipeList
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.GreaterOrEqual(t, len(response), 2)
+	assert.GreaterOrEqual(t, len(response.Recipes), 2)
+	assert.Empty(t, response.NextCursor)
+}
+
+func TestListRecipesHandler_Pagination(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	seen := make([]string, 0)
+	cursor := ""
+	for page := 0; page < 3; page++ {
+		req, _ := http.NewRequest("GET", "/api/v1/recipes?limit=1&cursor="+cursor, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.RecipeList
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		for _, recipe := range response.Recipes {
+			seen = append(seen, recipe.ID)
+		}
+		cursor = response.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	assert.ElementsMatch(t, []string{"test1", "test2"}, seen)
+	assert.Empty(t, cursor)
+}
+
+func TestListRecipesHandler_Filters(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"tags=pizza,pasta", []string{"test1", "test2"}},
+		{"tags=italian,pizza&tags_match=all", []string{"test1"}},
+		{"tags=italian,-pizza", []string{"test2"}},
+		{"name=PASTA", []string{"test2"}},
+		{"ingredient=chees", []string{"test1"}},
+		{"published_to=2000-01-01", []string{}},
+		{"sort=-name", []string{"test1", "test2"}},
+		{"sort=name", []string{"test2", "test1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/recipes?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.RecipeList
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			ids := make([]string, 0)
+			for _, recipe := range response.Recipes {
+				ids = append(ids, recipe.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestListRecipesHandler_InvalidParams(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	for _, query := range []string{
+		"limit=0",
+		"limit=abc",
+		"cursor=not-a-cursor",
+		"tags_match=some",
+		"published_from=yesterday",
+		"published_from=2024-02-01&published_to=2024-01-01",
+		"sort=tags",
+	} {
+		req, _ := http.NewRequest("GET", "/api/v1/recipes?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		var response httputil.HTTPError
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
+func TestGetRecipeHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/test2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Recipe
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "test2", response.ID)
+	assert.Equal(t, "Test Pasta", response.Name)
+
+	var detail handlers.RecipeDetail
+	err = json.Unmarshal(w.Body.Bytes(), &detail)
+	assert.NoError(t, err)
+	assert.Len(t, detail.ParsedIngredients, 2)
+	assert.Equal(t, "pasta", detail.ParsedIngredients[0].Item)
+	assert.Equal(t, "pasta", detail.ParsedIngredients[0].Raw)
+}
+
+func TestGetRecipeHandler_NotFound(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/nonexistent", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response httputil.HTTPError
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, "Recipe not found", response.Message)
+}
+
+func TestGetRecipeHandler_Servings(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	err := testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test3",
+		Name:         "Test Lemonade",
+		Ingredients:  []string{"16 teaspoons sugar", "1 cup lemon juice", "ice"},
+		Instructions: []string{"stir"},
+		Servings:     4,
+	})
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/test3?servings=12", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `W/"1"`, w.Header().Get("ETag"))
+
+	var response handlers.RecipeDetail
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, response.Servings)
+	assert.Equal(t, 4, response.ScaledFrom)
+	assert.Equal(t, models.Ingredients{"1 cup sugar", "3 cups lemon juice", "ice"}, response.Ingredients)
+	assert.Equal(t, "cup", response.ParsedIngredients[0].Unit)
+}
+
+func TestGetRecipeHandler_Units(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	err := testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test3",
+		Name:         "Test Bread",
+		Ingredients:  []string{"1 1/2 cups (355 ml) warm water (105F°-115F°)", "1/2 pound flour"},
+		Instructions: []string{"Preheat the oven to 475°F"},
+	})
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/test3?units=metric", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `W/"1"`, w.Header().Get("ETag"))
+	var response handlers.RecipeDetail
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "metric", response.Units)
+	assert.Equal(t, models.Ingredients{"355 milliliters warm water, 41°C-46°C", "225 grams flour"}, response.Ingredients)
+	assert.Equal(t, models.Instructions{"Preheat the oven to 250°C"}, response.Instructions)
+
+	// Browsers send Accept-Language with every request; it must not turn
+	// the ETag weak and break If-Match on the next update.
+	req, _ = http.NewRequest("GET", "/api/v1/recipes/test3", nil)
+	req.Header.Set("Accept-Language", "en-GB,en;q=0.8")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+	response = handlers.RecipeDetail{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Units)
+	assert.Equal(t, models.Ingredients{"1 1/2 cups (355 ml) warm water (105F°-115F°)", "1/2 pound flour"}, response.Ingredients)
+
+	req, _ = http.NewRequest("GET", "/api/v1/recipes/test3?units=kelvin", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	stored, err := testStore.Get(context.Background(), "test3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Preheat the oven to 475°F", stored.Instructions[0])
+
+	req, _ = http.NewRequest("PUT", "/api/v1/recipes/test3", strings.NewReader(`{"name": "Test Bread", "ingredients": ["flour"], "instructions": ["bake"]}`))
+	req.Header.Set("If-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetRecipeHandler_InvalidServings(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	for path, code := range map[string]int{
+		"/api/v1/recipes/test1?servings=0":   http.StatusBadRequest,
+		"/api/v1/recipes/test1?servings=two": http.StatusBadRequest,
+		"/api/v1/recipes/test1?servings=2":   http.StatusUnprocessableEntity,
+	} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestRecipeNutritionHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	err := testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test3",
+		Name:         "Test Bread",
+		Ingredients:  []string{"1 1/2 cups (355 ml) warm water", "500 g bread flour", "1 pinch of magic"},
+		Instructions: []string{"knead"},
+		Servings:     2,
+	})
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/test3/nutrition", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response nutrition.Estimate
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1805.0, response.Total.Calories)
+	if assert.NotNil(t, response.PerServing) {
+		assert.Equal(t, 902.5, response.PerServing.Calories)
+	}
+	assert.Len(t, response.Ingredients, 2)
+	assert.Equal(t, []string{"1 pinch of magic"}, response.Unmatched)
+
+	req, _ = http.NewRequest("GET", "/api/v1/recipes/nonexistent/nutrition", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestShoppingListHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	err := testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test3",
+		Name:         "Test Salad",
+		Ingredients:  []string{"2 tomatoes", "1 tablespoon olive oil", "salt"},
+		Instructions: []string{"toss"},
+		Servings:     2,
+	})
+	assert.NoError(t, err)
+	err = testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test4",
+		Name:         "Test Sauce",
+		Ingredients:  []string{"4 large tomatoes, chopped", "1/4 cup olive oil"},
+		Instructions: []string{"simmer"},
+	})
+	assert.NoError(t, err)
+	body := `{"recipes": [{"id": "test3", "servings": 4}, {"id": "test4"}]}`
+
+	req, _ := http.NewRequest("POST", "/api/v1/shopping-list", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response shopping.List
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response.Aisles, 3) {
+		assert.Equal(t, "produce", response.Aisles[0].Name)
+		assert.Equal(t, "8 tomatoes", response.Aisles[0].Items[0].Text)
+		assert.Equal(t, "3/8 cup olive oil", response.Aisles[2].Items[0].Text)
+	}
+
+	req, _ = http.NewRequest("POST", "/api/v1/shopping-list?format=markdown", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "- [ ] 8 tomatoes\n")
+
+	for body, code := range map[string]int{
+		`{"recipes": []}`: http.StatusBadRequest,
+		`{"recipes": [{"id": "test3", "servings": -1}]}`: http.StatusBadRequest,
+		`{"recipes": [{"id": "nonexistent"}]}`:           http.StatusNotFound,
+		`{"recipes": [{"id": "test4", "servings": 2}]}`:  http.StatusUnprocessableEntity,
+	} {
+		req, _ = http.NewRequest("POST", "/api/v1/shopping-list", strings.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, code, w.Code, body)
+	}
+}
+
+func TestMealPlanHandlers(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	err := testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test3",
+		Name:         "Test Omelette",
+		Ingredients:  []string{"3 large eggs", "1 tablespoon butter"},
+		Instructions: []string{"whisk", "cook"},
+		Servings:     1,
+	})
+	assert.NoError(t, err)
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("POST", "/api/v1/mealplans", `{
+		"owner": "maria",
+		"startDate": "2024-03-04",
+		"endDate": "2024-03-05",
+		"meals": [
+			{"date": "2024-03-05", "slot": "dinner", "recipeId": "test1"},
+			{"date": "2024-03-04", "slot": "breakfast", "recipeId": "test3", "servings": 2}
+		]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var plan models.MealPlan
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+	assert.NotEmpty(t, plan.ID)
+	if assert.Len(t, plan.Meals, 2) {
+		assert.Equal(t, "test3", plan.Meals[0].RecipeID)
+	}
+
+	w = serve("GET", "/api/v1/mealplans?owner=maria", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var plans []models.MealPlan
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plans))
+	assert.Len(t, plans, 1)
+
+	w = serve("GET", "/api/v1/mealplans/"+plan.ID+"/shopping-list?format=text", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "6 large eggs")
+
+	w = serve("GET", "/api/v1/mealplans/"+plan.ID+"/nutrition", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var summary mealplan.Nutrition
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	if assert.Len(t, summary.Days, 2) {
+		assert.Greater(t, summary.Days[0].Nutrients.Calories, 200.0)
+		assert.Nil(t, summary.Days[1].Meals[0].PerServing)
+	}
+
+	// Deleting a planned recipe flags the meal instead of dropping it.
+	w = serve("DELETE", "/api/v1/recipes/test3", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve("GET", "/api/v1/mealplans/"+plan.ID, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+	assert.True(t, plan.Meals[0].RecipeDeleted)
+	assert.False(t, plan.Meals[1].RecipeDeleted)
+
+	// Updating a plan requires its flagged meals to be replaced.
+	update := `{"owner": "maria", "startDate": "2024-03-04", "endDate": "2024-03-05", "meals": [%s]}`
+	w = serve("PUT", "/api/v1/mealplans/"+plan.ID, fmt.Sprintf(update, `{"date": "2024-03-04", "slot": "lunch", "recipeId": "test3"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w = serve("PUT", "/api/v1/mealplans/"+plan.ID, fmt.Sprintf(update, `{"date": "2024-03-04", "slot": "lunch", "recipeId": "test2"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve("PUT", "/api/v1/mealplans/nonexistent", fmt.Sprintf(update, ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve("POST", "/api/v1/mealplans", fmt.Sprintf(update, `{"date": "2024-03-09", "slot": "lunch", "recipeId": "test2"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve("POST", "/api/v1/mealplans", `{"owner": "maria", "startDate": "0001-01-01", "endDate": "9999-12-31", "meals": []}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at most 31 days")
+
+	w = serve("DELETE", "/api/v1/mealplans/"+plan.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve("GET", "/api/v1/mealplans/"+plan.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMealPlanCalendarHandlers(t *testing.T) {
+	setupTestData()
+	router := setupRouter(handlers.NewServer(testStore, handlers.WithCalendarSecret([]byte("secret"))))
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("POST", "/api/v1/mealplans", `{
+		"owner": "maria",
+		"startDate": "2024-03-04",
+		"endDate": "2024-03-05",
+		"meals": [
+			{"date": "2024-03-04", "slot": "dinner", "recipeId": "test1"},
+			{"date": "2024-03-05", "slot": "lunch", "recipeId": "test2"}
+		]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var plan models.MealPlan
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+
+	w = serve("GET", "/api/v1/mealplans/"+plan.ID+"/calendar.ics", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	cal, err := goical.NewDecoder(w.Body).Decode()
+	assert.NoError(t, err)
+	events := cal.Events()
+	if assert.Len(t, events, 2) {
+		summary, _ := events[0].Props.Text(goical.PropSummary)
+		assert.Equal(t, "Dinner: Test Pizza", summary)
+		description, _ := events[0].Props.Text(goical.PropDescription)
+		assert.Contains(t, description, "1. prepare dough\n")
+		assert.Equal(t, "http://example.com/api/v1/recipes/test1", events[0].Props.Get(goical.PropURL).Value)
+		start, err := events[1].DateTimeStart(time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 5, 12, 30, 0, 0, time.UTC), start)
+	}
+
+	w = serve("GET", "/api/v1/users/maria/calendar", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var link handlers.CalendarLink
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.True(t, strings.HasPrefix(link.URL, "http://example.com/api/v1/users/maria/mealplans.ics?token="), link.URL)
+
+	w = serve("GET", strings.TrimPrefix(link.URL, "http://example.com"), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	cal, err = goical.NewDecoder(w.Body).Decode()
+	assert.NoError(t, err)
+	assert.Len(t, cal.Events(), 2)
+
+	w = serve("GET", "/api/v1/users/tom/mealplans.ics?token="+link.Token, "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve("GET", "/api/v1/mealplans/nonexistent/calendar.ics", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPantrySearchHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	err := testStore.Create(context.Background(), &models.Recipe{
+		ID:           "test3",
+		Name:         "Test Pesto Pasta",
+		Ingredients:  []string{"1 pound pasta", "1/2 cup pine nuts", "2 cups basil", "1 cup cheese"},
+		Instructions: []string{"blend", "toss"},
+	})
+	assert.NoError(t, err)
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("PUT", "/api/v1/pantries/maria", `{"items": [{"name": "pasta"}, {"name": "cheese", "amount": "1 pound"}]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = serve("PUT", "/api/v1/pantries/maria", `{"items": [{"name": "pasta"}, {"name": "cheese"}, {"name": "tomatoes"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve("GET", "/api/v1/pantries/maria", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var p models.Pantry
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, "maria", p.Owner)
+	assert.Len(t, p.Items, 3)
+
+	w = serve("GET", "/api/v1/recipes/search/pantry?owner=maria", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var matches []handlers.PantryMatch
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &matches))
+	if assert.Len(t, matches, 3) {
+		assert.Equal(t, "test1", matches[0].ID)
+		assert.Equal(t, []string{"dough"}, matches[0].Missing)
+		assert.Equal(t, "test2", matches[1].ID)
+		assert.Equal(t, "test3", matches[2].ID)
+		assert.Equal(t, 0.5, matches[2].Score)
+		assert.Equal(t, []string{"1/2 cup pine nuts", "2 cups basil"}, matches[2].Missing)
+	}
+
+	w = serve("GET", "/api/v1/recipes/search/pantry?owner=maria&exclude=no+nuts&max_missing=1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &matches))
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "test1", matches[0].ID)
+		assert.Equal(t, "test2", matches[1].ID)
+	}
+
+	w = serve("GET", "/api/v1/recipes/search/pantry?have=dough,sauce", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &matches))
+	assert.Len(t, matches, 2)
+
+	for url, code := range map[string]int{
+		"/api/v1/recipes/search/pantry":                         http.StatusBadRequest,
+		"/api/v1/recipes/search/pantry?have=eggs&max_missing=x": http.StatusBadRequest,
+		"/api/v1/recipes/search/pantry?owner=tom":               http.StatusNotFound,
+	} {
+		w = serve("GET", url, "")
+		assert.Equal(t, code, w.Code, url)
+	}
+
+	w = serve("DELETE", "/api/v1/pantries/maria", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve("GET", "/api/v1/pantries/maria", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
 func TestNewRecipeHandler(t *testing.T) {
@@ -89,6 +650,83 @@ func TestNewRecipeHandler(t *testing.T) {
 	assert.NotZero(t, response.PublishedAt)
 }
 
+func TestNewRecipeHandler_Metadata(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	body := `{"name": "Tacos", "tags": ["main", "Mexican"], "ingredients": ["tortillas"], "instructions": ["fill"],
+		"prepTime": "PT15M", "cookTime": "PT10M", "difficulty": "Easy", "author": "Maria Rojas",
+		"sourceUrl": "https://example.com/tacos", "yield": "8 tacos"}`
+	req, _ := http.NewRequest("POST", "/api/v1/recipes", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created models.Recipe
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, models.Duration("PT25M"), created.TotalTime)
+	assert.Equal(t, models.Easy, created.Difficulty)
+	assert.Equal(t, "mexican", created.Cuisine)
+	assert.NotContains(t, w.Body.String(), "totalMinutes")
+
+	for query, expected := range map[string]int{
+		"cuisine=Mexican":                  1,
+		"difficulty=easy&author=rojas":     1,
+		"max_total_time=PT30M":             1,
+		"max_total_time=PT20M":             0,
+		"cuisine=mexican&difficulty=hard":  0,
+		"tags=italian&max_total_time=PT1H": 0,
+	} {
+		req, _ = http.NewRequest("GET", "/api/v1/recipes?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, query)
+		var list handlers.RecipeList
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Len(t, list.Recipes, expected, query)
+	}
+
+	for _, query := range []string{"difficulty=trivial", "max_total_time=30", "max_total_time=PT10S"} {
+		req, _ = http.NewRequest("GET", "/api/v1/recipes?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	req, _ = http.NewRequest("PUT", "/api/v1/recipes/"+created.ID, strings.NewReader(`{"name": "Tacos", "ingredients": ["tortillas"], "instructions": ["fill"], "cookTime": "10 minutes"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cookTime must be an ISO 8601 duration")
+}
+
+func TestRecipeHandlers_Invalid(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	bodies := map[string]string{
+		`{"ingredients": ["water"], "instructions": ["boil"]}`:                                "name is required",
+		`{"name": "Soup", "instructions": ["boil"]}`:                                          "at least one ingredient is required",
+		`{"name": "Soup", "ingredients": ["water"]}`:                                          "at least one instruction is required",
+		`{"name": "Soup", "ingredients": ["water"], "instructions": ["boil"], "tags": [" "]}`: "tags must not be blank",
+	}
+	for body, message := range bodies {
+		for _, target := range []string{"POST /api/v1/recipes", "PUT /api/v1/recipes/test1"} {
+			method, url, _ := strings.Cut(target, " ")
+			req, _ := http.NewRequest(method, url, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, target+" "+body)
+			assert.Contains(t, w.Body.String(), message, target+" "+body)
+		}
+	}
+	recipe, err := testStore.Get(context.Background(), "test1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Pizza", recipe.Name)
+}
+
 func TestNewRecipeHandler_InvalidJSON(t *testing.T) {
 	router := setupTestRouter()
 
@@ -138,8 +776,10 @@ func TestUpdateRecipeHandler_NotFound(t *testing.T) {
 	router := setupTestRouter()
 
 	updatedRecipe := models.Recipe{
-		Name: "Non-existent Recipe",
-		Tags: models.Tags{"test"},
+		Name:         "Non-existent Recipe",
+		Tags:         models.Tags{"test"},
+		Ingredients:  models.Ingredients{"water"},
+		Instructions: models.Instructions{"boil"},
 	}
 
 	jsonData, _ := json.Marshal(updatedRecipe)
@@ -156,6 +796,220 @@ func TestUpdateRecipeHandler_NotFound(t *testing.T) {
 	assert.Equal(t, "Recipe not found", response["error"])
 }
 
+func TestUpdateRecipeHandler_IfMatch(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/test1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+
+	put := func(ifMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/api/v1/recipes/test1", strings.NewReader(`{"name": "Pizza", "ingredients": ["dough"], "instructions": ["bake"], "revision": 7}`))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w = put(`"1"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	var updated models.Recipe
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, 2, updated.Revision)
+
+	for _, stale := range []string{`"1"`, `W/"2"`, `"1", "3"`, "2"} {
+		w = put(stale)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code, stale)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotEmpty(t, response["error"])
+	}
+
+	w = put(`"1", "2"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	w = put("*")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = put("")
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+}
+
+func TestPatchRecipeHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	patch := func(contentType, body, ifMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PATCH", "/api/v1/recipes/test1", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := patch("application/merge-patch+json", `{"name": "Margherita", "cookTime": "PT15M", "servings": null}`, `"1"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	var patched models.Recipe
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &patched))
+	assert.Equal(t, "Margherita", patched.Name)
+	assert.Equal(t, models.Duration("PT15M"), patched.TotalTime)
+	assert.Equal(t, models.Ingredients{"dough", "tomato", "cheese"}, patched.Ingredients)
+
+	w = patch("application/json-patch+json", `[
+		{"op": "test", "path": "/revision", "value": 2},
+		{"op": "add", "path": "/tags/-", "value": "vegetarian"},
+		{"op": "replace", "path": "/instructions/2", "value": "bake for 12 minutes"}
+	]`, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &patched))
+	assert.Equal(t, models.Tags{"italian", "pizza", "vegetarian"}, patched.Tags)
+	assert.Equal(t, "bake for 12 minutes", patched.Instructions[2])
+	assert.Equal(t, 3, patched.Revision)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		ifMatch     string
+		expected    int
+	}{
+		{"Plain JSON", "application/json", `{"name": "x"}`, "", http.StatusUnsupportedMediaType},
+		{"Malformed merge patch", "application/merge-patch+json", `{"name":`, "", http.StatusBadRequest},
+		{"Unknown op", "application/json-patch+json", `[{"op": "merge", "path": "/name", "value": "x"}]`, "", http.StatusBadRequest},
+		{"Stale If-Match", "application/merge-patch+json", `{"name": "x"}`, `"1"`, http.StatusPreconditionFailed},
+		{"Failed test", "application/json-patch+json", `[{"op": "test", "path": "/revision", "value": 1}]`, "", http.StatusConflict},
+		{"Missing path", "application/json-patch+json", `[{"op": "remove", "path": "/tags/9"}]`, "", http.StatusUnprocessableEntity},
+		{"Invalid result", "application/merge-patch+json", `{"ingredients": []}`, "", http.StatusUnprocessableEntity},
+		{"Wrong type", "application/merge-patch+json", `{"servings": "four"}`, "", http.StatusUnprocessableEntity},
+		{"Read-only id", "application/json-patch+json", `[{"op": "replace", "path": "/id", "value": "test2"}]`, "", http.StatusUnprocessableEntity},
+		{"Atomic", "application/json-patch+json", `[{"op": "replace", "path": "/name", "value": "x"}, {"op": "remove", "path": "/nope"}]`, "", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := patch(tt.contentType, tt.body, tt.ifMatch)
+			assert.Equal(t, tt.expected, w.Code)
+			var response httputil.HTTPError
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expected, response.Code)
+		})
+	}
+	assert.Contains(t, patch("application/json", `{}`, "").Header().Get("Accept-Patch"), "application/merge-patch+json")
+
+	recipe, err := testStore.Get(context.Background(), "test1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Margherita", recipe.Name)
+	assert.Equal(t, 3, recipe.Revision)
+
+	req, _ := http.NewRequest("PATCH", "/api/v1/recipes/missing", strings.NewReader(`{"name": "x"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevisionHandlers(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "maria")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/v1/recipes", `{"name": "Soup", "ingredients": ["water", "salt"], "instructions": ["boil water", "add salt"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created models.Recipe
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	base := "/api/v1/recipes/" + created.ID
+
+	w = do("PUT", base, `{"name": "Onion Soup", "ingredients": ["water", "onions", "salt"], "instructions": ["boil water", "add onions", "add salt"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do("GET", base+"/revisions", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var revisions []models.RecipeRevision
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, 2, revisions[0].Revision)
+		assert.Equal(t, models.RevisionUpdated, revisions[0].Action)
+		assert.Equal(t, "maria", revisions[0].ChangedBy)
+		assert.Nil(t, revisions[0].Recipe)
+		assert.Equal(t, models.RevisionCreated, revisions[1].Action)
+	}
+
+	w = do("GET", base+"/revisions/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var first models.RecipeRevision
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Equal(t, "Soup", first.Recipe.Name)
+
+	w = do("GET", base+"/revisions/2/diff", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var changes handlers.RevisionDiff
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &changes))
+	assert.Equal(t, 1, changes.From)
+	assert.Equal(t, 2, changes.To)
+	if assert.Len(t, changes.Changes, 3) {
+		assert.Equal(t, "name", changes.Changes[0].Field)
+		assert.Equal(t, "instructions", changes.Changes[2].Field)
+		assert.Equal(t, []diff.Line{
+			{Op: diff.Equal, Text: "boil water", Old: 1, New: 1},
+			{Op: diff.Insert, Text: "add onions", New: 2},
+			{Op: diff.Equal, Text: "add salt", Old: 2, New: 3},
+		}, changes.Changes[2].Lines)
+	}
+	w = do("GET", base+"/revisions/1/diff", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &changes))
+	assert.Equal(t, 0, changes.From)
+
+	req, _ := http.NewRequest("POST", base+"/revisions/1/restore", nil)
+	req.Header.Set("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = do("POST", base+"/revisions/1/restore", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	var restored models.Recipe
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "Soup", restored.Name)
+	assert.Equal(t, created.PublishedAt.Unix(), restored.PublishedAt.Unix())
+
+	w = do("GET", base+"/revisions/3", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restore models.RecipeRevision
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &restore))
+	assert.Equal(t, models.RevisionRestored, restore.Action)
+	assert.Equal(t, 1, restore.RestoredFrom)
+
+	for path, expected := range map[string]int{
+		base + "/revisions/9":               http.StatusNotFound,
+		base + "/revisions/0":               http.StatusBadRequest,
+		base + "/revisions/3/diff?from=x":   http.StatusBadRequest,
+		base + "/revisions/3/diff?from=9":   http.StatusNotFound,
+		"/api/v1/recipes/missing/revisions": http.StatusNotFound,
+		"/api/v1/recipes/test1/revisions":   http.StatusOK,
+	} {
+		w = do("GET", path, "")
+		assert.Equal(t, expected, w.Code, path)
+	}
+}
+
 func TestUpdateRecipeHandler_InvalidJSON(t *testing.T) {
 	setupTestData()
 	router := setupTestRouter()
@@ -173,7 +1027,7 @@ func TestDeleteRecipeHandler(t *testing.T) {
 	setupTestData()
 	router := setupTestRouter()
 
-	initialCount := len(recipes)
+	initialCount := countRecipes(t)
 
 	req, _ := http.NewRequest("DELETE", "/api/v1/recipes/test1", nil)
 	w := httptest.NewRecorder()
@@ -185,7 +1039,7 @@ func TestDeleteRecipeHandler(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "Recipe deleted", response["message"])
-	assert.Equal(t, initialCount-1, len(recipes))
+	assert.Equal(t, initialCount-1, countRecipes(t))
 }
 
 func TestDeleteRecipeHandler_NotFound(t *testing.T) {
@@ -204,6 +1058,134 @@ func TestDeleteRecipeHandler_NotFound(t *testing.T) {
 	assert.Equal(t, "Recipe not found", response["error"])
 }
 
+func TestBatchRecipesHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+	batch := func(query, body string) (int, handlers.BatchResponse) {
+		req, _ := http.NewRequest("POST", "/api/v1/recipes/batch"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var response handlers.BatchResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+	history := func(id string) []models.RecipeRevision {
+		req, _ := http.NewRequest("GET", "/api/v1/recipes/"+id+"/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var revisions []models.RecipeRevision
+		json.Unmarshal(w.Body.Bytes(), &revisions)
+		return revisions
+	}
+
+	code, response := batch("", `{"operations": [
+		{"op": "create", "recipe": {"name": "Soup", "ingredients": ["water"], "instructions": ["boil"]}},
+		{"op": "update", "id": "test2", "revision": 1, "recipe": {"name": "Better Pasta", "ingredients": ["pasta"], "instructions": ["boil"]}},
+		{"op": "delete", "id": "missing"},
+		{"op": "rename", "id": "test1"},
+		{"op": "update", "id": "test1", "recipe": {"name": "Bare Pizza"}}
+	]}`)
+	assert.Equal(t, http.StatusMultiStatus, code)
+	if assert.Len(t, response.Results, 5) {
+		assert.Equal(t, http.StatusCreated, response.Results[0].Status)
+		assert.NotEmpty(t, response.Results[0].ID)
+		assert.Equal(t, 1, response.Results[0].Revision)
+		assert.Equal(t, http.StatusOK, response.Results[1].Status)
+		assert.Equal(t, 2, response.Results[1].Revision)
+		assert.Equal(t, &httputil.HTTPError{Code: http.StatusNotFound, Message: "Recipe not found"}, response.Results[2].Error)
+		assert.Equal(t, http.StatusBadRequest, response.Results[3].Status)
+		assert.Equal(t, &httputil.HTTPError{Code: http.StatusBadRequest, Message: "at least one ingredient is required"}, response.Results[4].Error)
+		assert.Nil(t, response.Results[0].Error)
+	}
+	assert.Equal(t, 3, countRecipes(t))
+	assert.Len(t, history("test2"), 1)
+
+	// An atomic batch stops at the first failure and writes nothing.
+	code, response = batch("?atomic=true", `{"operations": [
+		{"op": "delete", "id": "test1"},
+		{"op": "update", "id": "test2", "revision": 1, "recipe": {"name": "Stale", "ingredients": ["pasta"], "instructions": ["boil"]}},
+		{"op": "delete", "id": "test2"}
+	]}`)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.True(t, response.Atomic)
+	if assert.Len(t, response.Results, 3) {
+		assert.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+		assert.Equal(t, http.StatusPreconditionFailed, response.Results[1].Status)
+		assert.Equal(t, http.StatusFailedDependency, response.Results[2].Status)
+		assert.Contains(t, response.Results[2].Error.Message, "operation 1 failed")
+	}
+	assert.Equal(t, 3, countRecipes(t))
+
+	code, response = batch("?atomic=true", `{"operations": [
+		{"op": "delete", "id": "test1"},
+		{"op": "update", "id": "test2", "revision": 2, "recipe": {"name": "Best Pasta", "ingredients": ["pasta"], "instructions": ["boil"]}}
+	]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, response.Results[1].Revision)
+	assert.Equal(t, 2, countRecipes(t))
+	recipe, _ := testStore.Get(context.Background(), "test2")
+	assert.Equal(t, "Best Pasta", recipe.Name)
+	assert.Len(t, history("test2"), 2)
+
+	code, _ = batch("", `{"operations": []}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = batch("?atomic=maybe", `{"operations": [{"op": "delete", "id": "test2"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestTrashHandlers(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	w := serve("POST", "/api/v1/mealplans", `{
+		"owner": "maria", "startDate": "2024-03-04", "endDate": "2024-03-04",
+		"meals": [{"date": "2024-03-04", "slot": "dinner", "recipeId": "test1"}]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var plan models.MealPlan
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+
+	w = serve("DELETE", "/api/v1/recipes/test1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve("GET", "/api/v1/recipes/test1", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve("GET", "/api/v1/recipes/search?tag=pizza", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "test1")
+
+	w = serve("GET", "/api/v1/recipes/trash", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var trash []models.Recipe
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	if assert.Len(t, trash, 1) {
+		assert.Equal(t, "test1", trash[0].ID)
+		assert.False(t, trash[0].DeletedAt.IsZero())
+	}
+
+	w = serve("POST", "/api/v1/recipes/test1/restore", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	assert.NotContains(t, w.Body.String(), "deletedAt")
+	w = serve("GET", "/api/v1/recipes/test1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve("GET", "/api/v1/mealplans/"+plan.ID, "")
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+	assert.False(t, plan.Meals[0].RecipeDeleted)
+
+	w = serve("POST", "/api/v1/recipes/test1/restore", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var httpErr httputil.HTTPError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &httpErr))
+	assert.Equal(t, "Recipe not found in the trash", httpErr.Message)
+}
+
 func TestSearchRecipesHandler(t *testing.T) {
 	setupTestData()
 	router := setupTestRouter()
@@ -241,7 +1223,41 @@ func TestSearchRecipesHandler_MultipleResults(t *testing.T) {
 	assert.Equal(t, 2, len(response))
 }
 
-func TestSearchRecipesHandler_NotFound(t *testing.T) {
+func TestSearchRecipesHandler_MultipleTags(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"tag=italian&tag=pizza&tags_match=all", []string{"test1"}},
+		{"tag=italian,pasta&tags_match=all", []string{"test2"}},
+		{"tag=pizza&tag=pasta", []string{"test1", "test2"}},
+		{"tag=pizza&tag=pasta&tags_match=any", []string{"test1", "test2"}},
+		{"tag=italian&tag=-pizza", []string{"test2"}},
+		{"tag=-pasta", []string{"test1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/recipes/search?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response []models.Recipe
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			ids := make([]string, 0)
+			for _, recipe := range response {
+				ids = append(ids, recipe.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestSearchRecipesHandler_NoMatch(t *testing.T) {
 	setupTestData()
 	router := setupTestRouter()
 
@@ -249,6 +1265,127 @@ func TestSearchRecipesHandler_NotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestSearchRecipesHandler_InvalidQuery(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	for _, query := range []string{"tag=", "tag=-", "tag=pizza&tags_match=some"} {
+		req, _ := http.NewRequest("GET", "/api/v1/recipes/search?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		var response httputil.HTTPError
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
+func TestIngredientSearchHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	search := func(query string) (*httptest.ResponseRecorder, []handlers.IngredientMatch) {
+		req, _ := http.NewRequest("GET", "/api/v1/recipes/search/ingredients?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var matches []handlers.IngredientMatch
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &matches))
+		}
+		return w, matches
+	}
+
+	w, matches := search("include=Tomatoes,pasta,basil")
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "test1", matches[0].ID)
+		assert.InDelta(t, 1.0/3, matches[0].Score, 1e-9)
+		assert.Equal(t, []string{"tomato"}, matches[0].Matched)
+		assert.Equal(t, "test2", matches[1].ID)
+		assert.Equal(t, []string{"pasta"}, matches[1].Matched)
+	}
+
+	w, matches = search("include=tomato&include=pasta&exclude=sauce")
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "test1", matches[0].ID)
+	}
+
+	for _, query := range []string{"", "exclude=pork", "include=,", "include=pasta&limit=0"} {
+		w, _ = search(query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestSearchRecipesHandler_FullText(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/search?q=cheese+pasta", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []handlers.SearchResult
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 2) {
+		// A name match outranks an ingredient match.
+		assert.Equal(t, "test2", response[0].ID)
+		assert.Equal(t, "test1", response[1].ID)
+		assert.Greater(t, response[0].Score, response[1].Score)
+		assert.Equal(t, "<em>cheese</em>", response[1].Highlights[0].Snippet)
+	}
+}
+
+func TestSearchRecipesHandler_FullTextPhrase(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", `/api/v1/recipes/search?q="add+sauce"`, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []handlers.SearchResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response, 1) {
+		assert.Equal(t, "test2", response[0].ID)
+		assert.Equal(t, "instructions", response[0].Highlights[0].Field)
+	}
+}
+
+func TestSearchRecipesHandler_FullTextEmptyQuery(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/search?q=+the+", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func setupLegacySearchRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	setupTestData()
+	return setupRouter(handlers.NewServer(testStore, handlers.WithSearchNotFound(true)))
+}
+
+func TestSearchRecipesHandler_NotFound(t *testing.T) {
+	router := setupLegacySearchRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/search?tag=nonexistenttag", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
 	var response map[string]string
@@ -258,8 +1395,7 @@ func TestSearchRecipesHandler_NotFound(t *testing.T) {
 }
 
 func TestSearchRecipesHandler_EmptyTag(t *testing.T) {
-	setupTestData()
-	router := setupTestRouter()
+	router := setupLegacySearchRouter()
 
 	req, _ := http.NewRequest("GET", "/api/v1/recipes/search?tag=", nil)
 	w := httptest.NewRecorder()
@@ -267,3 +1403,174 @@ func TestSearchRecipesHandler_EmptyTag(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestExportRecipesHandler(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/export?format=csv&tags=pizza", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "recipes.csv")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.True(t, strings.HasPrefix(lines[0], "id,name,tags,ingredients,instructions,servings,publishedAt,"))
+		assert.True(t, strings.HasPrefix(lines[1], "test1,Test Pizza,italian|pizza,dough|tomato|cheese,"))
+	}
+}
+
+func TestExportRecipesHandler_NDJSON(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/export?format=ndjson", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		var recipe models.Recipe
+		assert.NoError(t, json.Unmarshal([]byte(line), &recipe))
+	}
+}
+
+// failingListStore fails to list recipes, as a store losing its
+// connection would.
+type failingListStore struct {
+	store.RecipeStore
+}
+
+func (failingListStore) List(context.Context, store.ListOptions) (*store.Page, error) {
+	return nil, errors.New("connection lost")
+}
+
+func TestExportRecipesHandler_Failure(t *testing.T) {
+	setupTestData()
+	server := httptest.NewServer(setupRouter(handlers.NewServer(failingListStore{testStore})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/recipes/export")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	assert.Error(t, err, "a failed export must not look complete")
+}
+
+func TestExportRecipesHandler_InvalidFormat(t *testing.T) {
+	setupTestData()
+	router := setupTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/recipes/export?format=xml", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRunCommand_Unknown(t *testing.T) {
+	err := runCommand(context.Background(), []string{"frobnicate"})
+	assert.ErrorContains(t, err, `unknown command "frobnicate"`)
+	assert.ErrorContains(t, err, "reindex")
+}
+
+func TestRunCommand_Migrate(t *testing.T) {
+	t.Setenv("MONGO_URI", "")
+	assert.ErrorContains(t, runCommand(context.Background(), []string{"migrate"}), "expected up, down or status")
+	assert.ErrorContains(t, runCommand(context.Background(), []string{"migrate", "status"}), "MONGO_URI is not set")
+	assert.ErrorContains(t, runCommand(context.Background(), []string{"migrate", "down", "-steps", "0"}), "-steps")
+}
+
+func TestRunCommand_Import(t *testing.T) {
+	t.Setenv("MONGO_URI", "")
+	path := filepath.Join(t.TempDir(), "recipes.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"id": "r1", "name": "Soup"}]`), 0o644))
+	assert.ErrorContains(t, runCommand(context.Background(), []string{"import", path}), "MONGO_URI is not set")
+	assert.ErrorContains(t, runCommand(context.Background(), []string{"import", "-dry-run", path}), "MONGO_URI is not set")
+}
+
+func TestRunCommand_Export(t *testing.T) {
+	t.Setenv("MONGO_URI", "")
+	path := filepath.Join(t.TempDir(), "recipes.csv")
+	assert.ErrorContains(t, runCommand(context.Background(), []string{"export", "-o", path}), "MONGO_URI is not set")
+	_, err := os.Stat(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestPrintMigrationResults(t *testing.T) {
+	var buf bytes.Buffer
+	ids := make([]string, 12)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("r%d", i)
+	}
+	printMigrationResults(&buf, "up", []migrations.Result{{Version: 1, Name: "trim-ingredients", Changed: 12, IDs: ids}}, true)
+	assert.Contains(t, buf.String(), "dry run: up 1 trim-ingredients: 12 recipes\n")
+	assert.Contains(t, buf.String(), "r9\n   and 2 more\n")
+	assert.NotContains(t, buf.String(), "r10")
+
+	buf.Reset()
+	printMigrationResults(&buf, "down", nil, false)
+	assert.Equal(t, "down: nothing to do\n", buf.String())
+}
+
+func TestTrashRetention(t *testing.T) {
+	t.Setenv("TRASH_RETENTION", "")
+	retention, err := trashRetention()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTrashRetention, retention)
+
+	t.Setenv("TRASH_RETENTION", "0")
+	retention, err = trashRetention()
+	assert.NoError(t, err)
+	assert.Zero(t, retention)
+
+	t.Setenv("TRASH_RETENTION", "30d")
+	_, err = trashRetention()
+	assert.ErrorContains(t, err, "TRASH_RETENTION")
+}
+
+// failingRevisionStore fails to delete revisions.
+type failingRevisionStore struct {
+	store.RevisionStore
+}
+
+func (failingRevisionStore) Delete(context.Context, string) (int, error) {
+	return 0, errors.New("connection lost")
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	setupTestData()
+	revisions := store.NewMemoryRevisionStore()
+	for _, id := range []string{"test1", "test2"} {
+		recipe, _ := testStore.Get(ctx, id)
+		assert.NoError(t, revisions.Add(ctx, &models.RecipeRevision{RecipeID: id, Revision: 1, Action: models.RevisionCreated, Recipe: recipe}))
+	}
+	backends := stores{recipes: testStore, revisions: revisions}
+	assert.NoError(t, testStore.Delete(ctx, "test1"))
+
+	ids, err := purgeTrash(ctx, backends, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	failing := stores{recipes: testStore, revisions: failingRevisionStore{revisions}}
+	_, err = purgeTrash(ctx, failing, time.Now().Add(time.Second))
+	assert.ErrorContains(t, err, "revisions of test1")
+	trash, _ := testStore.Trash(ctx)
+	assert.Len(t, trash, 1, "a recipe whose history remains stays in the trash")
+
+	ids, err = purgeTrash(ctx, backends, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test1"}, ids)
+	trash, _ = testStore.Trash(ctx)
+	assert.Empty(t, trash)
+	history, _ := revisions.List(ctx, "test1")
+	assert.Empty(t, history)
+	history, _ = revisions.List(ctx, "test2")
+	assert.Len(t, history, 1)
+}
@@ -0,0 +1,95 @@
+package mealplan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/ical"
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// slotStart is the time of day the event of each slot starts at.
+var slotStart = map[models.Slot]time.Duration{
+	models.Breakfast: 8 * time.Hour,
+	models.Lunch:     12*time.Hour + 30*time.Minute,
+	models.Snack:     16 * time.Hour,
+	models.Dinner:    19 * time.Hour,
+}
+
+// mealDuration is how long the event of a meal lasts.
+const mealDuration = time.Hour
+
+// Events turns the meals of plans into calendar events, one per meal,
+// describing the recipe with its ingredients rescaled to the meal's
+// servings and its instructions. recipes holds the planned recipes by id
+// and recipeURL returns the link to a recipe. Meals whose recipe was
+// deleted are left out.
+func Events(plans []models.MealPlan, recipes map[string]models.Recipe, recipeURL func(id string) string) []ical.Event {
+	var events []ical.Event
+	for _, plan := range plans {
+		for _, meal := range plan.Meals {
+			recipe, ok := recipes[meal.RecipeID]
+			if !ok || meal.RecipeDeleted {
+				continue
+			}
+			day, err := time.Parse(models.DateLayout, meal.Date)
+			if err != nil {
+				continue
+			}
+			start := day.Add(slotStart[meal.Slot])
+			url := recipeURL(recipe.ID)
+			events = append(events, ical.Event{
+				UID:         fmt.Sprintf("%s-%s-%s-%s@ginrecipes", plan.ID, day.Format("20060102"), meal.Slot, recipe.ID),
+				Stamp:       plan.UpdatedAt,
+				Start:       start,
+				End:         start.Add(mealDuration),
+				Summary:     fmt.Sprintf("%s: %s", slotTitle(meal.Slot), recipe.Name),
+				Description: description(recipe, meal.Servings, url),
+				URL:         url,
+				Categories:  []string{string(meal.Slot)},
+			})
+		}
+	}
+	return events
+}
+
+// description lists the ingredients, rescaled to servings unless zero,
+// and the instructions of recipe, followed by its link.
+func description(recipe models.Recipe, servings int, url string) string {
+	var b strings.Builder
+	if servings == 0 {
+		servings = recipe.Servings
+	}
+	if servings > 0 {
+		fmt.Fprintf(&b, "Serves %d.\n\n", servings)
+	}
+	b.WriteString("Ingredients:\n")
+	for _, ing := range ingredient.ParseAll(recipe.Ingredients) {
+		line := strings.TrimSpace(ing.Raw)
+		if recipe.Servings > 0 && servings != recipe.Servings {
+			line = ing.Scale(float64(servings) / float64(recipe.Servings)).String()
+		}
+		fmt.Fprintf(&b, "- %s\n", line)
+	}
+	b.WriteString("\nInstructions:\n")
+	step := 0
+	for _, instruction := range recipe.Instructions {
+		if instruction = strings.TrimSpace(instruction); instruction != "" {
+			step++
+			fmt.Fprintf(&b, "%d. %s\n", step, instruction)
+		}
+	}
+	fmt.Fprintf(&b, "\n%s", url)
+	return b.String()
+}
+
+// slotTitle capitalizes slot for event titles.
+func slotTitle(slot models.Slot) string {
+	s := string(slot)
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
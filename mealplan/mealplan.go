@@ -0,0 +1,119 @@
+// Package mealplan derives shopping lists and nutrition summaries from
+// meal plans.
+package mealplan
+
+import (
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+	"github.com/mrojasb2000/GinRecipes/shopping"
+)
+
+// ShoppingEntries returns one shopping list entry per meal of plan.
+// recipes holds the planned recipes by id; meals whose recipe was deleted
+// or is missing from recipes are left out.
+func ShoppingEntries(plan models.MealPlan, recipes map[string]models.Recipe) []shopping.Entry {
+	var entries []shopping.Entry
+	for _, meal := range plan.Meals {
+		recipe, ok := recipes[meal.RecipeID]
+		if !ok || meal.RecipeDeleted {
+			continue
+		}
+		entries = append(entries, shopping.Entry{Recipe: recipe, Servings: meal.Servings})
+	}
+	return entries
+}
+
+// Nutrition summarizes what one person eats following a meal plan.
+// DailyAverage is taken over the days with at least one estimated meal.
+type Nutrition struct {
+	Days         []DayNutrition      `json:"days"`
+	DailyAverage nutrition.Nutrients `json:"dailyAverage"`
+}
+
+// DayNutrition adds up one serving of every meal of a day.
+type DayNutrition struct {
+	Date      string              `json:"date" example:"2024-03-04"`
+	Nutrients nutrition.Nutrients `json:"nutrients"`
+	Meals     []MealNutrition     `json:"meals"`
+}
+
+// MealNutrition is the estimate for one serving of a planned meal.
+// PerServing is nil when the recipe was deleted or does not say how many
+// servings it makes; such meals are not counted in the day's total.
+// Unestimated lists the ingredient lines left out of the estimate.
+type MealNutrition struct {
+	Slot          models.Slot          `json:"slot" example:"dinner"`
+	RecipeID      string               `json:"recipeId" example:"600dcc85a65917cbd1f201b0"`
+	Recipe        string               `json:"recipe,omitempty" example:"Homemade Pizza"`
+	PerServing    *nutrition.Nutrients `json:"perServing,omitempty"`
+	Unestimated   []string             `json:"unestimated,omitempty"`
+	RecipeDeleted bool                 `json:"recipeDeleted,omitempty"`
+}
+
+// Summarize estimates the nutrition of every day of plan, from its start
+// to its end date. recipes holds the planned recipes by id.
+func Summarize(plan models.MealPlan, recipes map[string]models.Recipe, table *nutrition.Table) Nutrition {
+	estimates := map[string]nutrition.Estimate{}
+	byDate := map[string][]models.Meal{}
+	for _, meal := range plan.Meals {
+		byDate[meal.Date] = append(byDate[meal.Date], meal)
+	}
+
+	summary := Nutrition{Days: []DayNutrition{}}
+	var total nutrition.Nutrients
+	eatingDays := 0
+	for _, date := range dates(plan.StartDate, plan.EndDate) {
+		day := DayNutrition{Date: date, Meals: []MealNutrition{}}
+		for _, meal := range byDate[date] {
+			m := MealNutrition{Slot: meal.Slot, RecipeID: meal.RecipeID, RecipeDeleted: meal.RecipeDeleted}
+			recipe, ok := recipes[meal.RecipeID]
+			if !ok || meal.RecipeDeleted {
+				m.RecipeDeleted = true
+				day.Meals = append(day.Meals, m)
+				continue
+			}
+			est, ok := estimates[recipe.ID]
+			if !ok {
+				est = table.Estimate(recipe)
+				estimates[recipe.ID] = est
+			}
+			m.Recipe, m.PerServing = recipe.Name, est.PerServing
+			m.Unestimated = append(append(m.Unestimated, est.Unmatched...), est.Unmeasured...)
+			if m.PerServing != nil {
+				day.Nutrients = day.Nutrients.Add(*m.PerServing)
+			}
+			day.Meals = append(day.Meals, m)
+		}
+		if day.Nutrients != (nutrition.Nutrients{}) {
+			total = total.Add(day.Nutrients)
+			eatingDays++
+		}
+		day.Nutrients = day.Nutrients.Rounded()
+		summary.Days = append(summary.Days, day)
+	}
+	if eatingDays > 0 {
+		summary.DailyAverage = total.Scale(1 / float64(eatingDays)).Rounded()
+	}
+	return summary
+}
+
+// dates lists the calendar dates from start to end inclusive, at most
+// models.MaxMealPlanDays of them in case a plan was stored before the
+// range was limited. Malformed dates yield no days.
+func dates(start, end string) []string {
+	from, err := time.Parse(models.DateLayout, start)
+	if err != nil {
+		return nil
+	}
+	to, err := time.Parse(models.DateLayout, end)
+	if err != nil {
+		return nil
+	}
+	var days []string
+	for d := from; !d.After(to) && len(days) < models.MaxMealPlanDays; d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(models.DateLayout))
+	}
+	return days
+}
@@ -0,0 +1,92 @@
+package mealplan
+
+import (
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+)
+
+func testPlan() (models.MealPlan, map[string]models.Recipe) {
+	plan := models.MealPlan{
+		Owner:     "maria",
+		StartDate: "2024-03-04",
+		EndDate:   "2024-03-06",
+		Meals: []models.Meal{
+			{Date: "2024-03-04", Slot: models.Breakfast, RecipeID: "oats", Servings: 4},
+			{Date: "2024-03-04", Slot: models.Dinner, RecipeID: "soup"},
+			{Date: "2024-03-06", Slot: models.Breakfast, RecipeID: "oats"},
+			{Date: "2024-03-06", Slot: models.Lunch, RecipeID: "gone", RecipeDeleted: true},
+		},
+	}
+	recipes := map[string]models.Recipe{
+		"oats": {ID: "oats", Name: "Oatmeal", Ingredients: models.Ingredients{"1 cup rolled oats", "2 cups milk"}, Servings: 2},
+		"soup": {ID: "soup", Name: "Soup", Ingredients: models.Ingredients{"4 cups water", "1 teaspoon salt"}},
+	}
+	return plan, recipes
+}
+
+func TestShoppingEntries(t *testing.T) {
+	plan, recipes := testPlan()
+
+	entries := ShoppingEntries(plan, recipes)
+	if len(entries) != 3 {
+		t.Fatalf("ShoppingEntries() = %d entries, expected 3", len(entries))
+	}
+	if entries[0].Recipe.Name != "Oatmeal" || entries[0].Servings != 4 {
+		t.Errorf("ShoppingEntries()[0] = %s x %d, expected Oatmeal x 4", entries[0].Recipe.Name, entries[0].Servings)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	plan, recipes := testPlan()
+
+	summary := Summarize(plan, recipes, nutrition.Default())
+	if len(summary.Days) != 3 {
+		t.Fatalf("Summarize() = %d days, expected 3", len(summary.Days))
+	}
+	oats := nutrition.Default().Estimate(recipes["oats"]).PerServing
+	if got := summary.Days[0].Nutrients; got != *oats {
+		t.Errorf("Summarize() first day = %+v, expected one serving of oatmeal %+v", got, *oats)
+	}
+	if summary.Days[0].Meals[1].PerServing != nil {
+		t.Error("Summarize() estimated a serving of a recipe without servings")
+	}
+	if len(summary.Days[1].Meals) != 0 || summary.Days[1].Nutrients != (nutrition.Nutrients{}) {
+		t.Errorf("Summarize() second day = %+v, expected no meals", summary.Days[1])
+	}
+	if !summary.Days[2].Meals[1].RecipeDeleted {
+		t.Error("Summarize() did not flag the deleted recipe")
+	}
+	if summary.DailyAverage != *oats {
+		t.Errorf("Summarize() daily average = %+v, expected %+v", summary.DailyAverage, *oats)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	plan, recipes := testPlan()
+	plan.ID = "plan1"
+	recipes["oats"] = models.Recipe{
+		ID:           "oats",
+		Name:         "Oatmeal",
+		Ingredients:  models.Ingredients{"1 cup rolled oats", "2 cups milk"},
+		Instructions: models.Instructions{" Simmer the oats in the milk.\r\n\r\n", "Serve."},
+		Servings:     2,
+	}
+
+	events := Events([]models.MealPlan{plan}, recipes, func(id string) string { return "http://api/recipes/" + id })
+	if len(events) != 3 {
+		t.Fatalf("Events() = %d events, expected 3", len(events))
+	}
+	first := events[0]
+	if first.Summary != "Breakfast: Oatmeal" || first.UID != "plan1-20240304-breakfast-oats@ginrecipes" {
+		t.Errorf("Events()[0] = %q %q", first.Summary, first.UID)
+	}
+	expected := "Serves 4.\n\nIngredients:\n- 2 cups rolled oats\n- 4 cups milk\n\nInstructions:\n1. Simmer the oats in the milk.\n2. Serve.\n\nhttp://api/recipes/oats"
+	if first.Description != expected {
+		t.Errorf("Events()[0].Description = %q, expected %q", first.Description, expected)
+	}
+	if hour := first.Start.Hour(); hour != 8 || first.End.Sub(first.Start) != mealDuration {
+		t.Errorf("Events()[0] from %v to %v, expected an hour from 08:00", first.Start, first.End)
+	}
+}
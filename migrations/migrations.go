@@ -0,0 +1,351 @@
+// Package migrations applies versioned changes to the documents of the
+// MongoDB recipes collection.
+//
+// Every migration has a version and rewrites the fields it names in the
+// documents it changes. Applied versions are recorded in the migrations
+// collection of the same database, and the previous values of the
+// rewritten fields are kept in migrations_backup so Down can put them
+// back. Migrations are idempotent, so an interrupted run can simply be
+// started again.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Migration is one versioned change to the recipes collection.
+type Migration struct {
+	Version int
+	Name    string
+	// Filter narrows the documents Apply is called on; nil selects all.
+	Filter bson.D
+	// Fields are the bson fields Apply may change. Only they are written
+	// back, and their previous values are saved for Down.
+	Fields []string
+	// Apply changes doc and reports whether it did.
+	Apply func(doc *Document) bool
+}
+
+// Document is a stored recipe together with its MongoDB _id.
+type Document struct {
+	ObjectID      any `bson:"_id"`
+	models.Recipe `bson:",inline"`
+}
+
+// Status is the state of one migration. AppliedAt is zero while the
+// migration is pending.
+type Status struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"appliedAt"`
+	Changed   int       `bson:"changed"`
+}
+
+// Result reports what running a migration up or down changed, or would
+// change in a dry run. IDs are the recipe ids of the changed documents.
+type Result struct {
+	Version int
+	Name    string
+	Changed int
+	IDs     []string
+}
+
+// Migrator runs a list of migrations against a recipes collection.
+type Migrator struct {
+	recipes    *mongo.Collection
+	applied    *mongo.Collection
+	backups    *mongo.Collection
+	migrations []Migration
+}
+
+// New returns a Migrator running migrations, which must have distinct
+// versions, against recipes. Most callers pass All.
+func New(recipes *mongo.Collection, migrations []Migration) (*Migrator, error) {
+	if err := check(migrations); err != nil {
+		return nil, err
+	}
+	db := recipes.Database()
+	return &Migrator{
+		recipes:    recipes,
+		applied:    db.Collection("migrations"),
+		backups:    db.Collection("migrations_backup"),
+		migrations: slices.SortedFunc(slices.Values(migrations), byVersion),
+	}, nil
+}
+
+func byVersion(a, b Migration) int {
+	return a.Version - b.Version
+}
+
+// check reports the first migration that cannot be run.
+func check(migrations []Migration) error {
+	seen := map[int]bool{}
+	for _, m := range migrations {
+		switch {
+		case m.Version < 1:
+			return fmt.Errorf("migration %q: version must be positive", m.Name)
+		case seen[m.Version]:
+			return fmt.Errorf("migration %q: duplicate version %d", m.Name, m.Version)
+		case len(m.Fields) == 0 || m.Apply == nil:
+			return fmt.Errorf("migration %d: fields and apply are required", m.Version)
+		}
+		seen[m.Version] = true
+	}
+	return nil
+}
+
+// Status lists every known migration in version order, with the time it
+// was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Version: migration.Version, Name: migration.Name}
+		if status, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt, statuses[i].Changed = status.AppliedAt, status.Changed
+		}
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]Status, error) {
+	cur, err := m.applied.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var statuses []Status
+	if err := cur.All(ctx, &statuses); err != nil {
+		return nil, err
+	}
+	applied := make(map[int]Status, len(statuses))
+	for _, status := range statuses {
+		applied[status.Version] = status
+	}
+	return applied, nil
+}
+
+// pending returns the migrations not in applied, in version order.
+func pending(migrations []Migration, applied map[int]Status) []Migration {
+	var out []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			out = append(out, migration)
+		}
+	}
+	return out
+}
+
+// Up applies every pending migration in version order. With dryRun
+// nothing is written and the results tell which documents would change.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Result, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, migration := range pending(m.migrations, applied) {
+		result, err := m.up(ctx, migration, dryRun)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return results, nil
+}
+
+func (m *Migrator) up(ctx context.Context, migration Migration, dryRun bool) (Result, error) {
+	result := Result{Version: migration.Version, Name: migration.Name}
+	filter := migration.Filter
+	if filter == nil {
+		filter = bson.D{}
+	}
+	cur, err := m.recipes.Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		c, ok, err := plan(migration, cur.Current)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			continue
+		}
+		result.Changed++
+		result.IDs = append(result.IDs, c.recipeID)
+		if dryRun {
+			continue
+		}
+		if _, err := m.backups.InsertOne(ctx, c.backup); err != nil {
+			return result, err
+		}
+		if _, err := m.recipes.UpdateByID(ctx, c.objectID, c.update()); err != nil {
+			return result, err
+		}
+	}
+	if err := cur.Err(); err != nil || dryRun {
+		return result, err
+	}
+	record := Status{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now(), Changed: result.Changed}
+	if _, err := m.applied.InsertOne(ctx, record); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return result, err
+	}
+	return result, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, restoring
+// the saved values of the fields they changed. Edits made to those fields
+// since are overwritten. With dryRun nothing is written.
+func (m *Migrator) Down(ctx context.Context, steps int, dryRun bool) ([]Result, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	for i := len(m.migrations) - 1; i >= 0 && len(results) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		result, err := m.down(ctx, migration, dryRun)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return results, nil
+}
+
+func (m *Migrator) down(ctx context.Context, migration Migration, dryRun bool) (Result, error) {
+	result := Result{Version: migration.Version, Name: migration.Name}
+	// Newest first, so that when an interrupted run saved a document twice
+	// the value from before the first run is restored last.
+	filter := bson.D{{Key: "version", Value: migration.Version}}
+	cur, err := m.backups.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}))
+	if err != nil {
+		return result, err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var b backup
+		if err := cur.Decode(&b); err != nil {
+			return result, err
+		}
+		result.Changed++
+		result.IDs = append(result.IDs, b.RecipeID)
+		if dryRun {
+			continue
+		}
+		if _, err := m.recipes.UpdateByID(ctx, b.ObjectID, b.restore()); err != nil {
+			return result, err
+		}
+	}
+	if err := cur.Err(); err != nil || dryRun {
+		return result, err
+	}
+	if _, err := m.backups.DeleteMany(ctx, filter); err != nil {
+		return result, err
+	}
+	_, err = m.applied.DeleteOne(ctx, bson.D{{Key: "_id", Value: migration.Version}})
+	return result, err
+}
+
+// backup holds the values of the fields a migration changed in one
+// document as they were before. Missing lists the fields it did not have.
+type backup struct {
+	Version  int      `bson:"version"`
+	ObjectID any      `bson:"docId"`
+	RecipeID string   `bson:"recipeId"`
+	Fields   bson.D   `bson:"fields"`
+	Missing  []string `bson:"missing"`
+}
+
+// restore returns the update that puts the saved values back.
+func (b backup) restore() bson.D {
+	update := bson.D{}
+	if len(b.Fields) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: b.Fields})
+	}
+	if len(b.Missing) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unsetFields(b.Missing)})
+	}
+	return update
+}
+
+// change is the rewrite of one document by a migration.
+type change struct {
+	objectID any
+	recipeID string
+	set      bson.D
+	unset    []string
+	backup   backup
+}
+
+// update returns the MongoDB update applying c.
+func (c change) update() bson.D {
+	update := bson.D{}
+	if len(c.set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: c.set})
+	}
+	if len(c.unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unsetFields(c.unset)})
+	}
+	return update
+}
+
+func unsetFields(fields []string) bson.D {
+	unset := make(bson.D, len(fields))
+	for i, field := range fields {
+		unset[i] = bson.E{Key: field, Value: ""}
+	}
+	return unset
+}
+
+// plan runs migration on the stored document raw and works out the
+// update and the backup it needs. ok is false when the document is left
+// unchanged.
+func plan(migration Migration, raw bson.Raw) (c change, ok bool, err error) {
+	var doc Document
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return c, false, err
+	}
+	if !migration.Apply(&doc) {
+		return c, false, nil
+	}
+	data, err := bson.Marshal(doc.Recipe)
+	if err != nil {
+		return c, false, err
+	}
+	migrated := bson.Raw(data)
+	c = change{
+		objectID: doc.ObjectID,
+		recipeID: doc.ID,
+		backup:   backup{Version: migration.Version, ObjectID: doc.ObjectID, RecipeID: doc.ID, Fields: bson.D{}},
+	}
+	for _, field := range migration.Fields {
+		if value, err := migrated.LookupErr(field); err == nil {
+			c.set = append(c.set, bson.E{Key: field, Value: value})
+		} else {
+			c.unset = append(c.unset, field)
+		}
+		if value, err := raw.LookupErr(field); err == nil {
+			c.backup.Fields = append(c.backup.Fields, bson.E{Key: field, Value: value})
+		} else {
+			c.backup.Missing = append(c.backup.Missing, field)
+		}
+	}
+	return c, true, nil
+}
@@ -0,0 +1,167 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAll(t *testing.T) {
+	if err := check(All); err != nil {
+		t.Fatalf("check(All) error = %v", err)
+	}
+	for i, m := range All {
+		if m.Version != i+1 {
+			t.Errorf("All[%d].Version = %d, expected %d", i, m.Version, i+1)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	apply := func(*Document) bool { return false }
+	tests := []struct {
+		name       string
+		migrations []Migration
+		wantErr    bool
+	}{
+		{"Valid", []Migration{{Version: 2, Fields: []string{"a"}, Apply: apply}, {Version: 1, Fields: []string{"b"}, Apply: apply}}, false},
+		{"Duplicate version", []Migration{{Version: 1, Fields: []string{"a"}, Apply: apply}, {Version: 1, Fields: []string{"b"}, Apply: apply}}, true},
+		{"Zero version", []Migration{{Fields: []string{"a"}, Apply: apply}}, true},
+		{"No fields", []Migration{{Version: 1, Apply: apply}}, true},
+		{"No apply", []Migration{{Version: 1, Fields: []string{"a"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := check(tt.migrations); (err != nil) != tt.wantErr {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	got := pending(All, map[int]Status{1: {Version: 1}, 3: {Version: 3}})
+	var versions []int
+	for _, m := range got {
+		versions = append(versions, m.Version)
+	}
+	if !reflect.DeepEqual(versions, []int{2, 4, 5}) {
+		t.Errorf("pending() = %v, expected [2 4 5]", versions)
+	}
+}
+
+// The documents below use lines from recipes.json as they were loaded.
+func TestTrimIngredients(t *testing.T) {
+	doc := Document{Recipe: models.Recipe{Ingredients: models.Ingredients{
+		"4 (6 to 7-ounce) boneless skinless chicken breasts\r",
+		"10 grinds black pepper\r",
+		"1/2 tsp salt",
+	}}}
+	if !trimIngredients(&doc) {
+		t.Fatal("trimIngredients() = false, expected a change")
+	}
+	expected := models.Ingredients{"4 (6 to 7-ounce) boneless skinless chicken breasts", "10 grinds black pepper", "1/2 tsp salt"}
+	if !reflect.DeepEqual(doc.Ingredients, expected) {
+		t.Errorf("trimIngredients() = %q, expected %q", doc.Ingredients, expected)
+	}
+	if trimIngredients(&doc) {
+		t.Error("trimIngredients() changed trimmed ingredients")
+	}
+}
+
+func TestTrimInstructions(t *testing.T) {
+	doc := Document{Recipe: models.Recipe{Instructions: models.Instructions{
+		"Place the chicken in a dish",
+		" Add the chicken breasts to the dish and rub both sides in the mixture",
+		"\r\n\r\nTo cook the chicken: Heat a nonstick skillet or grill pan over high heat",
+		"",
+	}}}
+	if !trimInstructions(&doc) {
+		t.Fatal("trimInstructions() = false, expected a change")
+	}
+	expected := models.Instructions{
+		"Place the chicken in a dish",
+		"Add the chicken breasts to the dish and rub both sides in the mixture",
+		"To cook the chicken: Heat a nonstick skillet or grill pan over high heat",
+		"",
+	}
+	if !reflect.DeepEqual(doc.Instructions, expected) {
+		t.Errorf("trimInstructions() = %q, expected %q", doc.Instructions, expected)
+	}
+	if trimInstructions(&doc) {
+		t.Error("trimInstructions() changed trimmed instructions")
+	}
+}
+
+func TestDefaultPublishedAt(t *testing.T) {
+	created := time.Date(2021, 1, 17, 18, 28, 52, 0, time.UTC)
+	doc := Document{ObjectID: bson.NewObjectIDFromTimestamp(created)}
+	if !defaultPublishedAt(&doc) || !doc.PublishedAt.Equal(created) {
+		t.Errorf("defaultPublishedAt() = %v, expected %v", doc.PublishedAt, created)
+	}
+	if defaultPublishedAt(&doc) {
+		t.Error("defaultPublishedAt() replaced an existing publishedAt")
+	}
+}
+
+func TestMetadataDefaults(t *testing.T) {
+	doc := Document{Recipe: models.Recipe{Tags: models.Tags{"main", "mexican"}}}
+	if !metadataDefaults(&doc) || doc.Cuisine != "mexican" {
+		t.Errorf("metadataDefaults() cuisine = %q, expected mexican", doc.Cuisine)
+	}
+	doc = Document{Recipe: models.Recipe{Tags: models.Tags{"dessert"}}}
+	if metadataDefaults(&doc) {
+		t.Errorf("metadataDefaults() changed a recipe without metadata to derive: %+v", doc.Recipe)
+	}
+}
+
+func TestInitialRevision(t *testing.T) {
+	doc := Document{}
+	if !initialRevision(&doc) || doc.Revision != 1 {
+		t.Errorf("initialRevision() revision = %d, expected 1", doc.Revision)
+	}
+	doc = Document{Recipe: models.Recipe{Revision: 4}}
+	if initialRevision(&doc) || doc.Revision != 4 {
+		t.Errorf("initialRevision() revision = %d, expected 4 unchanged", doc.Revision)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	id := bson.NewObjectID()
+	raw, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: id},
+		{Key: "id", Value: "c0283p3d0cvuglq85log"},
+		{Key: "name", Value: "Grilled chicken"},
+		{Key: "tags", Value: bson.A{"main", "greek"}},
+		{Key: "publishedAt", Value: time.Date(2021, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{Key: "difficulty", Value: "Easy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, ok, err := plan(All[3], raw)
+	if err != nil || !ok {
+		t.Fatalf("plan() = %v, %v, expected a change", ok, err)
+	}
+	if c.objectID != id || c.recipeID != "c0283p3d0cvuglq85log" {
+		t.Errorf("plan() document = %v %s", c.objectID, c.recipeID)
+	}
+	update, _ := bson.MarshalExtJSON(c.update(), false, false)
+	expectedUpdate := `{"$set":{"difficulty":"easy","cuisine":"greek"},"$unset":{"totalTime":"","totalMinutes":""}}`
+	if string(update) != expectedUpdate {
+		t.Errorf("plan() update = %s, expected %s", update, expectedUpdate)
+	}
+	restore, _ := bson.MarshalExtJSON(c.backup.restore(), false, false)
+	expectedRestore := `{"$set":{"difficulty":"Easy"},"$unset":{"cuisine":"","totalTime":"","totalMinutes":""}}`
+	if string(restore) != expectedRestore {
+		t.Errorf("plan() restore = %s, expected %s", restore, expectedRestore)
+	}
+
+	if _, ok, _ := plan(All[2], raw); ok {
+		t.Error("plan() changed a document the migration leaves alone")
+	}
+}
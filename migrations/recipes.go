@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// All lists the migrations of the recipes collection. Append new ones with
+// the next version; never renumber or remove applied ones.
+var All = []Migration{
+	{
+		Version: 1,
+		Name:    "trim-ingredients",
+		Filter:  untrimmed("ingredients"),
+		Fields:  []string{"ingredients"},
+		Apply:   trimIngredients,
+	},
+	{
+		Version: 2,
+		Name:    "trim-instructions",
+		Filter:  untrimmed("instructions"),
+		Fields:  []string{"instructions"},
+		Apply:   trimInstructions,
+	},
+	{
+		Version: 3,
+		Name:    "default-published-at",
+		Filter:  bson.D{{Key: "publishedAt", Value: nil}},
+		Fields:  []string{"publishedAt"},
+		Apply:   defaultPublishedAt,
+	},
+	{
+		Version: 4,
+		Name:    "recipe-metadata-defaults",
+		Fields:  []string{"difficulty", "cuisine", "totalTime", "totalMinutes"},
+		Apply:   metadataDefaults,
+	},
+	{
+		Version: 5,
+		Name:    "initial-revision",
+		Filter:  bson.D{{Key: "revision", Value: bson.D{{Key: "$exists", Value: false}}}},
+		Fields:  []string{"revision"},
+		Apply:   initialRevision,
+	},
+}
+
+// untrimmed selects documents with an item of the array field that starts
+// or ends with white space.
+func untrimmed(field string) bson.D {
+	return bson.D{{Key: field, Value: bson.Regex{Pattern: `^\s|\s$`}}}
+}
+
+// trimIngredients removes the white space around ingredient lines, such
+// as the trailing "\r" left by recipes pasted from Windows editors.
+func trimIngredients(doc *Document) bool {
+	return trimAll(doc.Ingredients)
+}
+
+// trimInstructions removes the white space around instruction steps, such
+// as the leading space left by splitting paragraphs on ". " and the
+// "\r\n\r\n" paragraph separators.
+func trimInstructions(doc *Document) bool {
+	return trimAll(doc.Instructions)
+}
+
+// trimAll trims every item of list in place and reports whether any
+// changed.
+func trimAll[S ~[]string](list S) bool {
+	changed := false
+	for i, item := range list {
+		if trimmed := strings.TrimSpace(item); trimmed != item {
+			list[i], changed = trimmed, true
+		}
+	}
+	return changed
+}
+
+// defaultPublishedAt gives recipes without publishedAt the time their
+// document was created, as recorded in its ObjectID, or else the current
+// time.
+func defaultPublishedAt(doc *Document) bool {
+	if !doc.PublishedAt.IsZero() {
+		return false
+	}
+	doc.PublishedAt = time.Now().UTC()
+	if id, ok := doc.ObjectID.(bson.ObjectID); ok {
+		doc.PublishedAt = id.Timestamp()
+	}
+	return true
+}
+
+// metadataDefaults gives recipes stored before the metadata fields existed
+// the defaults of Recipe.Normalize, such as a cuisine taken from their
+// tags.
+func metadataDefaults(doc *Document) bool {
+	before := doc.Recipe
+	doc.Normalize()
+	return doc.Difficulty != before.Difficulty || doc.Cuisine != before.Cuisine ||
+		doc.TotalTime != before.TotalTime || doc.TotalMinutes != before.TotalMinutes
+}
+
+// initialRevision starts the revision counter of recipes stored before
+// updates were versioned at 1.
+func initialRevision(doc *Document) bool {
+	if doc.Revision > 0 {
+		return false
+	}
+	doc.Revision = 1
+	return true
+}
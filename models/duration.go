@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is an ISO 8601 duration such as "PT1H30M". Only weeks, days,
+// hours, minutes and seconds are accepted, as years and months have no
+// fixed length.
+type Duration string
+
+// ParseDuration converts an ISO 8601 duration to a time.Duration.
+func ParseDuration(s string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(strings.ToUpper(s), "P")
+	date, clock, hasClock := strings.Cut(rest, "T")
+	if !ok || rest == "" || (hasClock && clock == "") {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	days, err := sumDesignators(date, "WD", []time.Duration{7 * 24 * time.Hour, 24 * time.Hour})
+	if err != nil {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	hours, err := sumDesignators(clock, "HMS", []time.Duration{time.Hour, time.Minute, time.Second})
+	if err != nil {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	return days + hours, nil
+}
+
+// sumDesignators adds up the numbers in s, each followed by one of the
+// designators in order, which may each appear at most once and in order.
+func sumDesignators(s, designators string, units []time.Duration) (time.Duration, error) {
+	var total time.Duration
+	next := 0
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, errors.New("expected a number before a designator")
+		}
+		pos := strings.IndexByte(designators[next:], s[i])
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if pos < 0 || err != nil {
+			return 0, errors.New("unexpected designator")
+		}
+		next += pos
+		total += time.Duration(n * float64(units[next]))
+		next++
+		s = s[i+1:]
+	}
+	return total, nil
+}
+
+// FormatDuration renders d as an ISO 8601 duration in hours, minutes and
+// seconds, e.g. "PT1H30M".
+func FormatDuration(d time.Duration) Duration {
+	if d <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return Duration(b.String())
+}
+
+// Value returns the length of d, or zero when d is empty or invalid.
+func (d Duration) Value() time.Duration {
+	if d == "" {
+		return 0
+	}
+	v, _ := ParseDuration(string(d))
+	return v
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of the calendar dates used by meal plans.
+const DateLayout = "2006-01-02"
+
+// MaxMealPlanDays is the longest range of days a meal plan may cover.
+const MaxMealPlanDays = 31
+
+// Slot is the meal of the day a recipe is planned for.
+type Slot string
+
+const (
+	Breakfast Slot = "breakfast"
+	Lunch     Slot = "lunch"
+	Dinner    Slot = "dinner"
+	Snack     Slot = "snack"
+)
+
+// Slots lists the valid slots in the order they happen during a day.
+var Slots = []Slot{Breakfast, Lunch, Dinner, Snack}
+
+// MealPlan schedules recipes over a range of days for one owner. StartDate
+// and EndDate are inclusive calendar dates in DateLayout.
+type MealPlan struct {
+	ID        string    `json:"id,omitempty" bson:"id,omitempty"`
+	Owner     string    `json:"owner" bson:"owner" example:"maria"`
+	Name      string    `json:"name,omitempty" bson:"name,omitempty" example:"First week of March"`
+	StartDate string    `json:"startDate" bson:"startDate" example:"2024-03-04"`
+	EndDate   string    `json:"endDate" bson:"endDate" example:"2024-03-10"`
+	Meals     []Meal    `json:"meals" bson:"meals"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt" example:"2024-03-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt" example:"2024-03-01T00:00:00Z"`
+}
+
+// Meal is one recipe planned for a slot of a day. Servings is how many
+// servings to prepare; zero keeps the recipe's own amounts. RecipeDeleted
+// is set when the recipe is deleted after it was planned.
+type Meal struct {
+	Date          string `json:"date" bson:"date" example:"2024-03-04"`
+	Slot          Slot   `json:"slot" bson:"slot" enums:"breakfast,lunch,dinner,snack" example:"dinner"`
+	RecipeID      string `json:"recipeId" bson:"recipeId" example:"600dcc85a65917cbd1f201b0"`
+	Servings      int    `json:"servings,omitempty" bson:"servings,omitempty" example:"4"`
+	RecipeDeleted bool   `json:"recipeDeleted,omitempty" bson:"recipeDeleted,omitempty"`
+}
+
+// Validate reports the first problem that makes p unusable as a meal plan.
+// Like Recipe.Validate it does not check the ID.
+func (p MealPlan) Validate() error {
+	if strings.TrimSpace(p.Owner) == "" {
+		return errors.New("owner is required")
+	}
+	start, err := time.Parse(DateLayout, p.StartDate)
+	if err != nil {
+		return fmt.Errorf("startDate must be a date like %s", DateLayout)
+	}
+	end, err := time.Parse(DateLayout, p.EndDate)
+	if err != nil {
+		return fmt.Errorf("endDate must be a date like %s", DateLayout)
+	}
+	if end.Before(start) {
+		return errors.New("endDate must not be before startDate")
+	}
+	if end.After(start.AddDate(0, 0, MaxMealPlanDays-1)) {
+		return fmt.Errorf("a meal plan covers at most %d days", MaxMealPlanDays)
+	}
+	for i, meal := range p.Meals {
+		if err := meal.validate(start, end); err != nil {
+			return fmt.Errorf("meals[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m Meal) validate(start, end time.Time) error {
+	date, err := time.Parse(DateLayout, m.Date)
+	if err != nil {
+		return fmt.Errorf("date must be a date like %s", DateLayout)
+	}
+	if date.Before(start) || date.After(end) {
+		return errors.New("date must be within the plan")
+	}
+	if !slices.Contains(Slots, m.Slot) {
+		return fmt.Errorf("unknown slot %q; expected breakfast, lunch, dinner or snack", m.Slot)
+	}
+	if strings.TrimSpace(m.RecipeID) == "" {
+		return errors.New("recipeId is required")
+	}
+	if m.Servings < 0 {
+		return errors.New("servings must not be negative")
+	}
+	return nil
+}
+
+// SortMeals orders the meals of p by date and then slot.
+func (p *MealPlan) SortMeals() {
+	slices.SortStableFunc(p.Meals, func(a, b Meal) int {
+		if c := strings.Compare(a.Date, b.Date); c != 0 {
+			return c
+		}
+		return slices.Index(Slots, a.Slot) - slices.Index(Slots, b.Slot)
+	})
+}
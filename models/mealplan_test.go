@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMealPlan_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *MealPlan)
+		wantErr bool
+	}{
+		{name: "Valid plan", modify: func(p *MealPlan) {}},
+		{name: "No meals", modify: func(p *MealPlan) { p.Meals = nil }},
+		{name: "Blank owner", modify: func(p *MealPlan) { p.Owner = " " }, wantErr: true},
+		{name: "Bad start date", modify: func(p *MealPlan) { p.StartDate = "03/04/2024" }, wantErr: true},
+		{name: "End before start", modify: func(p *MealPlan) { p.EndDate = "2024-03-03" }, wantErr: true},
+		{name: "Longest range", modify: func(p *MealPlan) { p.StartDate, p.EndDate = "2024-03-01", "2024-03-31" }},
+		{name: "Range too long", modify: func(p *MealPlan) { p.StartDate, p.EndDate = "2024-03-01", "2024-04-01" }, wantErr: true},
+		{name: "Centuries", modify: func(p *MealPlan) { p.StartDate, p.EndDate = "0001-01-01", "9999-12-31" }, wantErr: true},
+		{name: "Single day", modify: func(p *MealPlan) { p.EndDate = p.StartDate; p.Meals[0].Date = p.StartDate }},
+		{name: "Meal outside plan", modify: func(p *MealPlan) { p.Meals[0].Date = "2024-03-11" }, wantErr: true},
+		{name: "Unknown slot", modify: func(p *MealPlan) { p.Meals[0].Slot = "brunch" }, wantErr: true},
+		{name: "Missing recipe", modify: func(p *MealPlan) { p.Meals[0].RecipeID = "" }, wantErr: true},
+		{name: "Negative servings", modify: func(p *MealPlan) { p.Meals[0].Servings = -2 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := MealPlan{
+				Owner:     "maria",
+				StartDate: "2024-03-04",
+				EndDate:   "2024-03-10",
+				Meals:     []Meal{{Date: "2024-03-05", Slot: Dinner, RecipeID: "abc", Servings: 4}},
+			}
+			tt.modify(&plan)
+			err := plan.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MealPlan.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMealPlan_SortMeals(t *testing.T) {
+	plan := MealPlan{Meals: []Meal{
+		{Date: "2024-03-05", Slot: Breakfast, RecipeID: "c"},
+		{Date: "2024-03-04", Slot: Dinner, RecipeID: "b"},
+		{Date: "2024-03-04", Slot: Breakfast, RecipeID: "a"},
+		{Date: "2024-03-04", Slot: Lunch, RecipeID: "d"},
+	}}
+	plan.SortMeals()
+
+	var ids []string
+	for _, meal := range plan.Meals {
+		ids = append(ids, meal.RecipeID)
+	}
+	if got := strings.Join(ids, ","); got != "a,d,b,c" {
+		t.Errorf("MealPlan.SortMeals() = %s, expected a,d,b,c", got)
+	}
+}
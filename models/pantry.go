@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Pantry is what one owner has on hand. Exclude lists ingredients the
+// owner never wants to cook with, such as "no nuts" or "pork".
+type Pantry struct {
+	Owner     string       `json:"owner" bson:"owner" example:"maria"`
+	Items     []PantryItem `json:"items" bson:"items"`
+	Exclude   []string     `json:"exclude,omitempty" bson:"exclude,omitempty" example:"no nuts"`
+	UpdatedAt time.Time    `json:"updatedAt" bson:"updatedAt" example:"2024-03-01T00:00:00Z"`
+}
+
+// PantryItem is an ingredient on hand. Amount is free text and only kept
+// for the owner's reference.
+type PantryItem struct {
+	Name   string `json:"name" bson:"name" example:"chicken breasts"`
+	Amount string `json:"amount,omitempty" bson:"amount,omitempty" example:"2 pounds"`
+}
+
+// Validate reports the first problem that makes p unusable as a pantry.
+func (p Pantry) Validate() error {
+	if strings.TrimSpace(p.Owner) == "" {
+		return errors.New("owner is required")
+	}
+	for _, item := range p.Items {
+		if strings.TrimSpace(item.Name) == "" {
+			return errors.New("item names must not be blank")
+		}
+	}
+	for _, exclusion := range p.Exclude {
+		if strings.TrimSpace(exclusion) == "" {
+			return errors.New("exclusions must not be blank")
+		}
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"slices"
+	"strings"
 	"time"
 )
 
@@ -18,10 +22,127 @@ func (t Tags) Contains(key string) bool {
 // swagger:parameters recipes newRecipe
 type Recipe struct {
 	//swagger:ignore
-	Id           string       `json:"id,omitempty" bson:"id,omitempty"`
+	ID           string       `json:"id,omitempty" bson:"id,omitempty"`
 	Name         string       `json:"name" bson:"name" example:"Chocolate Cake"`
 	Tags         Tags         `json:"tags" bson:"tags" example:"dessert,sweet"`
 	Ingredients  Ingredients  `json:"ingredients" bson:"ingredients" example:"ingredient1,ingredient2"`
 	Instructions Instructions `json:"instructions" bson:"instructions" example:"instruction1,instruction2"`
 	PublishedAt  time.Time    `json:"publishedAt" bson:"publishedAt" example:"2024-01-01T00:00:00Z"`
+	Servings     int          `json:"servings,omitempty" bson:"servings,omitempty" example:"4"`
+	Yield        string       `json:"yield,omitempty" bson:"yield,omitempty" example:"1 loaf"`
+	PrepTime     Duration     `json:"prepTime,omitempty" bson:"prepTime,omitempty" swaggertype:"string" example:"PT20M"`
+	CookTime     Duration     `json:"cookTime,omitempty" bson:"cookTime,omitempty" swaggertype:"string" example:"PT45M"`
+	TotalTime    Duration     `json:"totalTime,omitempty" bson:"totalTime,omitempty" swaggertype:"string" example:"PT1H5M"`
+	Difficulty   Difficulty   `json:"difficulty,omitempty" bson:"difficulty,omitempty" enums:"easy,medium,hard" example:"easy"`
+	Cuisine      string       `json:"cuisine,omitempty" bson:"cuisine,omitempty" example:"italian"`
+	Author       string       `json:"author,omitempty" bson:"author,omitempty" example:"Maria Rojas"`
+	SourceURL    string       `json:"sourceUrl,omitempty" bson:"sourceUrl,omitempty" example:"https://example.com/chocolate-cake"`
+	Attribution  string       `json:"attribution,omitempty" bson:"attribution,omitempty" example:"Adapted from Grandma's recipe box"`
+	// Revision counts the writes to the recipe, starting at 1. The store
+	// maintains it; the API exposes it as the ETag.
+	Revision int `json:"revision" bson:"revision" readonly:"true" example:"3"`
+	// DeletedAt is set while the recipe is in the trash.
+	DeletedAt time.Time `json:"deletedAt,omitzero" bson:"deletedAt,omitempty" readonly:"true" example:"2024-03-01T09:30:00Z"`
+	// TotalMinutes mirrors TotalTime in whole minutes so the database can
+	// filter on it. Normalize keeps it up to date.
+	TotalMinutes int `json:"-" bson:"totalMinutes,omitempty"`
+}
+
+// Difficulty is how demanding a recipe is to cook.
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"
+	Medium Difficulty = "medium"
+	Hard   Difficulty = "hard"
+)
+
+// Difficulties lists the valid difficulties from easiest to hardest.
+var Difficulties = []Difficulty{Easy, Medium, Hard}
+
+// Cuisines are the tags Normalize recognizes as the cuisine of a recipe.
+var Cuisines = []string{
+	"american", "asian", "chinese", "french", "german", "greek", "indian", "italian", "japanese",
+	"korean", "mediterranean", "mexican", "middle eastern", "spanish", "thai", "vietnamese",
+}
+
+// Validate reports the first problem that makes r unusable as a recipe.
+// It does not check the ID, which is assigned by the API on creation.
+func (r Recipe) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(r.Ingredients) == 0 {
+		return errors.New("at least one ingredient is required")
+	}
+	if len(r.Instructions) == 0 {
+		return errors.New("at least one instruction is required")
+	}
+	if r.Servings < 0 {
+		return errors.New("servings must not be negative")
+	}
+	for _, tag := range r.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return errors.New("tags must not be blank")
+		}
+	}
+	return r.ValidateMetadata()
+}
+
+// ValidateMetadata reports the first problem with the optional metadata of
+// r: its times, difficulty and source URL. Empty fields are valid.
+func (r Recipe) ValidateMetadata() error {
+	for _, field := range []struct {
+		name  string
+		value Duration
+	}{{"prepTime", r.PrepTime}, {"cookTime", r.CookTime}, {"totalTime", r.TotalTime}} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := ParseDuration(string(field.value)); err != nil {
+			return fmt.Errorf("%s must be an ISO 8601 duration like PT1H30M", field.name)
+		}
+	}
+	if r.TotalTime != "" && r.TotalTime.Value() < r.PrepTime.Value()+r.CookTime.Value() {
+		return errors.New("totalTime must not be shorter than prepTime and cookTime together")
+	}
+	if r.Difficulty != "" && !slices.Contains(Difficulties, Difficulty(strings.ToLower(string(r.Difficulty)))) {
+		return fmt.Errorf("unknown difficulty %q; expected easy, medium or hard", r.Difficulty)
+	}
+	if r.SourceURL != "" {
+		u, err := url.Parse(r.SourceURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("sourceUrl must be an absolute http or https URL")
+		}
+	}
+	return nil
+}
+
+// Normalize tidies r and fills in its metadata defaults. White space around
+// ingredient lines and instruction steps is removed, difficulty and cuisine
+// are lower-cased, a missing totalTime is the sum of prepTime and cookTime,
+// and a missing cuisine is taken from the first tag naming one of
+// Cuisines. Recipes stored before these rules existed get the same
+// treatment from database migrations.
+func (r *Recipe) Normalize() {
+	for i, line := range r.Ingredients {
+		r.Ingredients[i] = strings.TrimSpace(line)
+	}
+	for i, step := range r.Instructions {
+		r.Instructions[i] = strings.TrimSpace(step)
+	}
+	r.Difficulty = Difficulty(strings.ToLower(strings.TrimSpace(string(r.Difficulty))))
+	r.Cuisine = strings.ToLower(strings.TrimSpace(r.Cuisine))
+	if r.TotalTime == "" && (r.PrepTime != "" || r.CookTime != "") {
+		r.TotalTime = FormatDuration(r.PrepTime.Value() + r.CookTime.Value())
+	}
+	r.TotalMinutes = int(r.TotalTime.Value().Round(time.Minute) / time.Minute)
+	if r.Cuisine == "" {
+		for _, tag := range r.Tags {
+			if tag = strings.ToLower(tag); slices.Contains(Cuisines, tag) {
+				r.Cuisine = tag
+				break
+			}
+		}
+	}
 }
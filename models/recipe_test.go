@@ -126,3 +126,122 @@ func TestRecipe_EmptyFields(t *testing.T) {
 		t.Error("Expected PublishedAt to be zero time")
 	}
 }
+
+func TestRecipe_Validate(t *testing.T) {
+	valid := Recipe{
+		Name:         "Test Recipe",
+		Tags:         Tags{"italian"},
+		Ingredients:  Ingredients{"ingredient1"},
+		Instructions: Instructions{"step1"},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(r *Recipe)
+		wantErr bool
+	}{
+		{name: "Valid recipe", modify: func(r *Recipe) {}},
+		{name: "Blank name", modify: func(r *Recipe) { r.Name = "  " }, wantErr: true},
+		{name: "No ingredients", modify: func(r *Recipe) { r.Ingredients = nil }, wantErr: true},
+		{name: "No instructions", modify: func(r *Recipe) { r.Instructions = Instructions{} }, wantErr: true},
+		{name: "Blank tag", modify: func(r *Recipe) { r.Tags = Tags{"italian", ""} }, wantErr: true},
+		{name: "No tags", modify: func(r *Recipe) { r.Tags = nil }},
+		{name: "Servings", modify: func(r *Recipe) { r.Servings = 4 }},
+		{name: "Negative servings", modify: func(r *Recipe) { r.Servings = -1 }, wantErr: true},
+		{name: "Times", modify: func(r *Recipe) { r.PrepTime, r.CookTime, r.TotalTime = "PT20M", "PT1H", "PT1H30M" }},
+		{name: "Malformed time", modify: func(r *Recipe) { r.CookTime = "45 minutes" }, wantErr: true},
+		{name: "Total shorter than steps", modify: func(r *Recipe) { r.PrepTime, r.TotalTime = "PT1H", "PT30M" }, wantErr: true},
+		{name: "Difficulty ignores case", modify: func(r *Recipe) { r.Difficulty = "Easy" }},
+		{name: "Unknown difficulty", modify: func(r *Recipe) { r.Difficulty = "trivial" }, wantErr: true},
+		{name: "Source URL", modify: func(r *Recipe) { r.SourceURL = "https://example.com/pizza" }},
+		{name: "Relative source URL", modify: func(r *Recipe) { r.SourceURL = "/pizza" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := valid
+			tt.modify(&recipe)
+			err := recipe.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Recipe.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecipe_Normalize(t *testing.T) {
+	recipe := Recipe{
+		Tags:         Tags{"main", "Mexican", "italian"},
+		Ingredients:  Ingredients{"10 grinds black pepper\r"},
+		Instructions: Instructions{" Add the chicken", "\r\n\r\nTo cook the chicken: Heat a pan"},
+		PrepTime:     "PT20M",
+		CookTime:     "PT1H15M",
+		Difficulty:   " Medium",
+	}
+	recipe.Normalize()
+	if recipe.TotalTime != "PT1H35M" || recipe.TotalMinutes != 95 {
+		t.Errorf("Normalize() total = %s (%d minutes), expected PT1H35M (95 minutes)", recipe.TotalTime, recipe.TotalMinutes)
+	}
+	if recipe.Cuisine != "mexican" {
+		t.Errorf("Normalize() cuisine = %q, expected mexican", recipe.Cuisine)
+	}
+	if recipe.Difficulty != Medium {
+		t.Errorf("Normalize() difficulty = %q, expected medium", recipe.Difficulty)
+	}
+	if recipe.Ingredients[0] != "10 grinds black pepper" || recipe.Instructions[0] != "Add the chicken" ||
+		recipe.Instructions[1] != "To cook the chicken: Heat a pan" {
+		t.Errorf("Normalize() = %q, %q, expected trimmed lines", recipe.Ingredients, recipe.Instructions)
+	}
+
+	recipe = Recipe{Tags: Tags{"italian"}, Cuisine: "Thai", TotalTime: "PT45M", CookTime: "PT30M"}
+	recipe.Normalize()
+	if recipe.Cuisine != "thai" || recipe.TotalTime != "PT45M" || recipe.TotalMinutes != 45 {
+		t.Errorf("Normalize() = %q, %s, %d, expected the given cuisine and total time kept", recipe.Cuisine, recipe.TotalTime, recipe.TotalMinutes)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "PT45M", expected: 45 * time.Minute},
+		{input: "PT1H30M", expected: 90 * time.Minute},
+		{input: "pt1h", expected: time.Hour},
+		{input: "P1DT2H", expected: 26 * time.Hour},
+		{input: "P1W", expected: 7 * 24 * time.Hour},
+		{input: "PT1.5H", expected: 90 * time.Minute},
+		{input: "PT90S", expected: 90 * time.Second},
+		{input: "P", wantErr: true},
+		{input: "PT", wantErr: true},
+		{input: "P1M", wantErr: true},
+		{input: "PT30M1H", wantErr: true},
+		{input: "PTM", wantErr: true},
+		{input: "45M", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ParseDuration(%q) = %v, %v, expected %v, wantErr %v", tt.input, got, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected Duration
+	}{
+		{0, "PT0S"},
+		{45 * time.Minute, "PT45M"},
+		{26 * time.Hour, "PT26H"},
+		{90*time.Minute + 30*time.Second, "PT1H30M30S"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.expected {
+			t.Errorf("FormatDuration(%v) = %s, expected %s", tt.d, got, tt.expected)
+		}
+	}
+}
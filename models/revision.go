@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RevisionAction is the kind of write that produced a recipe revision.
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "create"
+	RevisionUpdated  RevisionAction = "update"
+	RevisionRestored RevisionAction = "restore"
+)
+
+// RecipeRevision is the recipe as it was stored by one write. Revisions
+// are never changed once recorded. ChangedBy is whoever the request named
+// as its user, if anyone, and RestoredFrom the revision a restore copied.
+type RecipeRevision struct {
+	RecipeID     string         `json:"recipeId" bson:"recipeId" example:"600dcc85a65917cbd1f201b0"`
+	Revision     int            `json:"revision" bson:"revision" example:"3"`
+	Action       RevisionAction `json:"action" bson:"action" enums:"create,update,restore" example:"update"`
+	ChangedAt    time.Time      `json:"changedAt" bson:"changedAt" example:"2024-03-01T09:30:00Z"`
+	ChangedBy    string         `json:"changedBy,omitempty" bson:"changedBy,omitempty" example:"maria"`
+	RestoredFrom int            `json:"restoredFrom,omitempty" bson:"restoredFrom,omitempty" example:"1"`
+	// Recipe is left out of revision lists.
+	Recipe *Recipe `json:"recipe,omitempty" bson:"recipe,omitempty"`
+}
@@ -0,0 +1,142 @@
+package nutrition
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Estimate is the estimated nutrition of a recipe. PerServing is only set
+// when the recipe says how many servings it makes. Unmatched lists the
+// ingredient lines no food was found for, and Unmeasured those whose food
+// was found but whose amount could not be turned into a weight, such as
+// "salt and pepper, to taste".
+type Estimate struct {
+	Servings    int                   `json:"servings,omitempty" example:"4"`
+	Total       Nutrients             `json:"total"`
+	PerServing  *Nutrients            `json:"perServing,omitempty"`
+	Ingredients []IngredientNutrition `json:"ingredients"`
+	Unmatched   []string              `json:"unmatched"`
+	Unmeasured  []string              `json:"unmeasured"`
+}
+
+// IngredientNutrition is the contribution of one ingredient line.
+type IngredientNutrition struct {
+	Ingredient string    `json:"ingredient" example:"3 3/4 cups (490 g) bread flour"`
+	Food       string    `json:"food" example:"bread flour"`
+	Grams      float64   `json:"grams" example:"490"`
+	Nutrients  Nutrients `json:"nutrients"`
+}
+
+// Estimate adds up the nutrients of the ingredients of recipe.
+func (t *Table) Estimate(recipe models.Recipe) Estimate {
+	est := Estimate{
+		Servings:    recipe.Servings,
+		Ingredients: []IngredientNutrition{},
+		Unmatched:   []string{},
+		Unmeasured:  []string{},
+	}
+	for _, ing := range ingredient.ParseAll(recipe.Ingredients) {
+		if ing.Unparsed {
+			continue
+		}
+		line := strings.TrimSpace(ing.Raw)
+		food, ok := t.Match(ing.Item)
+		if !ok {
+			est.Unmatched = append(est.Unmatched, line)
+			continue
+		}
+		grams, ok := weigh(ing, food)
+		if !ok {
+			est.Unmeasured = append(est.Unmeasured, line)
+			continue
+		}
+		n := food.Per100g.Scale(grams / 100)
+		est.Total = est.Total.Add(n)
+		est.Ingredients = append(est.Ingredients, IngredientNutrition{
+			Ingredient: line,
+			Food:       food.Name,
+			Grams:      round(grams),
+			Nutrients:  n.Rounded(),
+		})
+	}
+	if recipe.Servings > 0 {
+		per := est.Total.Scale(1 / float64(recipe.Servings)).Rounded()
+		est.PerServing = &per
+	}
+	est.Total = est.Total.Rounded()
+	return est
+}
+
+// smallMeasures are the volumes in milliliters of units too vague to
+// convert in recipes but close enough for an estimate.
+var smallMeasures = map[string]float64{"pinch": 0.3, "dash": 0.6}
+
+// weigh returns the weight in grams of ing. A weight is used as given,
+// whether it is the main amount or an alternate like the "(490 g)" of
+// "3 3/4 cups (490 g) bread flour"; volumes are converted with the food's
+// density. Other units need a unit weight from the table or must be a
+// pinch or dash, and bare counts use the piece weight.
+func weigh(ing ingredient.Ingredient, food *Food) (float64, bool) {
+	if ing.Quantity == nil {
+		return 0, false
+	}
+	count := ing.Quantity.Mean()
+	primary := ingredient.Measure{Quantity: *ing.Quantity, Unit: ing.Unit}
+	if grams, dim, ok := primary.Base(); ok && dim == ingredient.Mass {
+		return grams, true
+	}
+	if a := ing.Alternate; a != nil {
+		if grams, ok := food.grams(*a); ok {
+			if a.Each {
+				grams *= count
+			}
+			return grams, true
+		}
+	}
+	if grams, ok := food.grams(primary); ok {
+		return grams, true
+	}
+	if w, ok := food.UnitWeights[ing.Unit]; ok {
+		return count * w, true
+	}
+	if ml, ok := smallMeasures[ing.Unit]; ok {
+		return food.grams(ingredient.Measure{Quantity: ingredient.Quantity{Value: count * ml}, Unit: "milliliter"})
+	}
+	if ing.Unit == "" && food.PieceWeight > 0 {
+		return count * food.PieceWeight, true
+	}
+	return 0, false
+}
+
+// grams converts a weight or volume of food to grams.
+func (f *Food) grams(m ingredient.Measure) (float64, bool) {
+	amount, dim, ok := m.Base()
+	switch {
+	case !ok:
+		return 0, false
+	case dim == ingredient.Mass:
+		return amount, true
+	case f.Density > 0:
+		return amount * f.Density, true
+	default:
+		return amount, true
+	}
+}
+
+// Rounded returns n rounded to one decimal place.
+func (n Nutrients) Rounded() Nutrients {
+	return Nutrients{
+		Calories:      round(n.Calories),
+		Protein:       round(n.Protein),
+		Fat:           round(n.Fat),
+		Carbohydrates: round(n.Carbohydrates),
+		Sodium:        round(n.Sodium),
+	}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,111 @@
+package nutrition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+func TestTable_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"boneless skinless chicken breasts", "chicken breast"},
+		{"chicken stock", "broth"},
+		{"rice vinegar", "vinegar"},
+		{"large eggs", "egg"},
+		{"egg yolks", "egg yolk"},
+		{"unsalted butter", "unsalted butter"},
+		{"Parmesan cheese", "parmesan"},
+		{"all-purpose flour", "all-purpose flour"},
+		{"warm water", "water"},
+		{"Sherry Vinaigrette", ""},
+	}
+
+	table := Default()
+	for _, tt := range tests {
+		food, ok := table.Match(tt.name)
+		got := ""
+		if ok {
+			got = food.Name
+		}
+		if got != tt.expected {
+			t.Errorf("Match(%q) = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestTable_Estimate(t *testing.T) {
+	recipe := models.Recipe{
+		Ingredients: models.Ingredients{
+			"1 1/2 cups (355 ml) warm water (105F°-115F°)",
+			"3 3/4 cups (490 g) bread flour",
+			"2 large eggs",
+			"1 package (8 ounces) cream cheese",
+			"salt and pepper",
+			"1 serving [Sherry Vinaigrette](http://www.xanthir.com/recipes/showrecipe.php?id=id59)",
+			"<hr>",
+		},
+		Servings: 2,
+	}
+
+	est := Default().Estimate(recipe)
+
+	grams := map[string]float64{}
+	for _, ing := range est.Ingredients {
+		grams[ing.Food] = ing.Grams
+	}
+	expected := map[string]float64{"water": 355, "bread flour": 490, "egg": 100, "cream cheese": 226.8}
+	for food, g := range expected {
+		if grams[food] != g {
+			t.Errorf("Estimate() grams of %s = %v, expected %v", food, grams[food], g)
+		}
+	}
+	if len(est.Unmatched) != 1 || est.Unmatched[0] != recipe.Ingredients[5] {
+		t.Errorf("Estimate() unmatched = %q, expected the vinaigrette", est.Unmatched)
+	}
+	if len(est.Unmeasured) != 1 || est.Unmeasured[0] != "salt and pepper" {
+		t.Errorf("Estimate() unmeasured = %q, expected salt and pepper", est.Unmeasured)
+	}
+	// 1768.9 kcal of bread flour, 143 of egg and 775.6 of cream cheese.
+	if est.Total.Calories != 2687.5 {
+		t.Errorf("Estimate() calories = %v, expected 2687.5", est.Total.Calories)
+	}
+	if est.PerServing == nil || est.PerServing.Calories != 1343.8 {
+		t.Errorf("Estimate() per serving = %+v, expected 1343.8 calories", est.PerServing)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foods.json")
+	overrides := `[
+		{"name": "water", "match": ["water"], "per100g": {"sodium": 10}, "density": 1},
+		{"name": "sherry vinaigrette", "match": ["vinaigrette"], "per100g": {"calories": 450, "fat": 50}, "unitWeights": {"serving": 30}}
+	]`
+	if err := os.WriteFile(path, []byte(overrides), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	est := table.Estimate(models.Recipe{Ingredients: models.Ingredients{
+		"1 cup water",
+		"1 serving [Sherry Vinaigrette](http://example.com)",
+		"1 cup sugar",
+	}})
+	if len(est.Unmatched) != 0 {
+		t.Errorf("Load() table left %q unmatched", est.Unmatched)
+	}
+	if est.Total.Sodium != 23.7+2 {
+		t.Errorf("Load() table sodium = %v, expected %v", est.Total.Sodium, 23.7+2)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+}
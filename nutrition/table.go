@@ -0,0 +1,161 @@
+// Package nutrition estimates the nutrients in a recipe by matching its
+// parsed ingredients against an offline table of foods.
+package nutrition
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+)
+
+// Nutrients are the tracked nutrients of an amount of food. Sodium is in
+// milligrams, calories in kcal and the rest in grams.
+type Nutrients struct {
+	Calories      float64 `json:"calories" example:"412.5"`
+	Protein       float64 `json:"protein" example:"18.2"`
+	Fat           float64 `json:"fat" example:"21"`
+	Carbohydrates float64 `json:"carbohydrates" example:"35.4"`
+	Sodium        float64 `json:"sodium" example:"780"`
+}
+
+// Add returns the sum of n and o.
+func (n Nutrients) Add(o Nutrients) Nutrients {
+	return Nutrients{
+		Calories:      n.Calories + o.Calories,
+		Protein:       n.Protein + o.Protein,
+		Fat:           n.Fat + o.Fat,
+		Carbohydrates: n.Carbohydrates + o.Carbohydrates,
+		Sodium:        n.Sodium + o.Sodium,
+	}
+}
+
+// Scale returns n multiplied by f.
+func (n Nutrients) Scale(f float64) Nutrients {
+	return Nutrients{
+		Calories:      n.Calories * f,
+		Protein:       n.Protein * f,
+		Fat:           n.Fat * f,
+		Carbohydrates: n.Carbohydrates * f,
+		Sodium:        n.Sodium * f,
+	}
+}
+
+// Food is one entry of the nutrient table. Match lists the ingredient
+// names that refer to it; Density (g/ml) converts volumes to weights,
+// PieceWeight (g) is the weight of one item counted without a unit, and
+// UnitWeights gives the weight of units such as a clove or a can. Aisle is
+// the store section shopping lists file the food under.
+type Food struct {
+	Name        string             `json:"name"`
+	Aisle       string             `json:"aisle,omitempty"`
+	Match       []string           `json:"match"`
+	Per100g     Nutrients          `json:"per100g"`
+	Density     float64            `json:"density,omitempty"`
+	PieceWeight float64            `json:"pieceWeight,omitempty"`
+	UnitWeights map[string]float64 `json:"unitWeights,omitempty"`
+}
+
+// Table maps ingredient names to foods.
+type Table struct {
+	foods   []Food
+	phrases []phrase
+}
+
+// phrase is one normalized Match entry of a food.
+type phrase struct {
+	words []string
+	food  int
+}
+
+//go:embed foods.json
+var defaultFoods []byte
+
+// Default returns the table bundled with the API.
+var Default = sync.OnceValue(func() *Table {
+	var foods []Food
+	if err := json.Unmarshal(defaultFoods, &foods); err != nil {
+		panic("nutrition: bundled table: " + err.Error())
+	}
+	return NewTable(foods)
+})
+
+// NewTable builds a table from foods.
+func NewTable(foods []Food) *Table {
+	t := &Table{foods: foods}
+	for i, food := range foods {
+		for _, name := range append([]string{food.Name}, food.Match...) {
+			if words := ingredient.Words(name); len(words) > 0 {
+				t.phrases = append(t.phrases, phrase{words: words, food: i})
+			}
+		}
+	}
+	return t
+}
+
+// Load returns the bundled table with the foods in the JSON file at path
+// added to it. A food in the file replaces the bundled one of the same
+// name, so a deployment can correct or extend the defaults.
+func Load(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overrides []Food
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("nutrition table %s: %w", path, err)
+	}
+	foods := slices.Clone(Default().foods)
+	for _, food := range overrides {
+		i := slices.IndexFunc(foods, func(f Food) bool { return strings.EqualFold(f.Name, food.Name) })
+		if i >= 0 {
+			foods[i] = food
+		} else {
+			foods = append(foods, food)
+		}
+	}
+	return NewTable(foods), nil
+}
+
+// Match returns the food an ingredient name refers to. Every word of a
+// match phrase must occur in the name; longer phrases win, then phrases
+// containing the name's last word, which is usually the noun ("rice
+// vinegar" is vinegar, not rice).
+func (t *Table) Match(name string) (*Food, bool) {
+	words := ingredient.Words(name)
+	if len(words) == 0 {
+		return nil, false
+	}
+	last := words[len(words)-1]
+	best, bestScore := -1, 0
+	for _, p := range t.phrases {
+		if !containsAll(words, p.words) {
+			continue
+		}
+		score := 2 * len(p.words)
+		if slices.Contains(p.words, last) {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = p.food, score
+		}
+	}
+	if best < 0 {
+		return nil, false
+	}
+	return &t.foods[best], true
+}
+
+func containsAll(words, subset []string) bool {
+	for _, w := range subset {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package pantry
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+)
+
+// exclusionGroups expands dietary exclusions into the ingredients they
+// rule out. Each entry is a name whose words must all occur in an
+// ingredient. The groups err on the side of excluding: "no dairy" also
+// rules out peanut butter.
+var exclusionGroups = map[string][]string{
+	"nut": {
+		"nut", "almond", "walnut", "pecan", "peanut", "cashew", "pistachio",
+		"hazelnut", "macadamia", "praline", "marzipan", "nutella",
+	},
+	"pork": {
+		"pork", "bacon", "ham", "sausage", "prosciutto", "pancetta", "chorizo",
+		"salami", "pepperoni", "lard",
+	},
+	"meat": {
+		"meat", "beef", "steak", "chicken", "turkey", "lamb", "veal", "duck",
+		"pork", "bacon", "ham", "sausage", "prosciutto", "pancetta", "chorizo",
+		"salami", "pepperoni", "lard", "gelatin",
+	},
+	"fish": {
+		"fish", "salmon", "tuna", "cod", "tilapia", "halibut", "trout",
+		"anchovy", "sardine", "mackerel",
+	},
+	"shellfish": {
+		"shellfish", "shrimp", "prawn", "crab", "lobster", "scallop", "clam",
+		"mussel", "oyster",
+	},
+	"dairy": {
+		"dairy", "milk", "butter", "cheese", "cream", "yogurt", "buttermilk",
+		"mozzarella", "parmesan", "ricotta", "feta", "mascarpone", "ghee", "whey",
+	},
+	"egg": {"egg", "mayonnaise", "meringue"},
+	"gluten": {
+		"gluten", "flour", "bread", "breadcrumb", "pasta", "noodle", "cracker",
+		"wheat", "barley", "rye", "couscous", "soy sauce",
+	},
+}
+
+func init() {
+	exclusionGroups["seafood"] = slices.Concat(exclusionGroups["fish"], exclusionGroups["shellfish"])
+}
+
+// exclusion is an ingredient to stay away from, as the words of each name
+// that rules a recipe out.
+type exclusion [][]string
+
+// parseExclusion reads exclusions written as "nuts", "no nuts", "without
+// pork", "-pork" or "gluten-free". Known groups such as nuts, dairy or
+// meat expand to their members.
+func parseExclusion(s string) (exclusion, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "-")
+	for _, prefix := range []string{"no ", "without ", "avoid "} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(s, "free"), "-"))
+	words := ingredient.Words(s)
+	if len(words) == 0 {
+		return nil, false
+	}
+	if members, ok := exclusionGroups[strings.Join(words, " ")]; ok {
+		ex := make(exclusion, len(members))
+		for i, member := range members {
+			ex[i] = ingredient.Words(member)
+		}
+		return ex, true
+	}
+	return exclusion{words}, true
+}
+
+// matches reports whether an ingredient with the given words is excluded.
+func (e exclusion) matches(words []string) bool {
+	for _, name := range e {
+		if containsAll(words, name) {
+			return true
+		}
+	}
+	return false
+}
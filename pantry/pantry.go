@@ -0,0 +1,142 @@
+// Package pantry ranks recipes by how much of them can be cooked from the
+// ingredients on hand, leaving out recipes with excluded ingredients.
+package pantry
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+)
+
+// Staples are the items a pantry search assumes on hand unless told
+// otherwise.
+var Staples = []string{"water", "salt", "black pepper"}
+
+// Coverage is how much of a recipe the ingredients on hand cover. Score is
+// the covered share of the recipe's ingredients, from 0 to 1. Missing
+// lists the ingredient lines that are not covered.
+type Coverage struct {
+	Score   float64  `json:"score" example:"0.75"`
+	Covered int      `json:"covered" example:"6"`
+	Total   int      `json:"total" example:"8"`
+	Missing []string `json:"missing"`
+}
+
+// Matcher decides which recipe ingredients are covered by the items on
+// hand and which are excluded.
+type Matcher struct {
+	table      *nutrition.Table
+	have       []term
+	exclusions []exclusion
+}
+
+// term is a name on hand, resolved once.
+type term struct {
+	words []string
+	food  *nutrition.Food
+}
+
+// NewMatcher returns a Matcher for the given items on hand and exclusions.
+// table identifies the food an ingredient refers to, so that "scallions"
+// on hand cover "green onions".
+func NewMatcher(table *nutrition.Table, have, exclude []string) *Matcher {
+	m := &Matcher{table: table}
+	for _, name := range have {
+		t := term{words: ingredient.Words(name)}
+		if len(t.words) == 0 {
+			continue
+		}
+		t.food, _ = table.Match(name)
+		m.have = append(m.have, t)
+	}
+	for _, e := range exclude {
+		if ex, ok := parseExclusion(e); ok {
+			m.exclusions = append(m.exclusions, ex)
+		}
+	}
+	return m
+}
+
+// Match reports how much of recipe is covered, or false when the recipe
+// contains an excluded ingredient. Lines that are not ingredients, such as
+// section headings, are ignored.
+func (m *Matcher) Match(recipe models.Recipe) (Coverage, bool) {
+	c := Coverage{Missing: []string{}}
+	for _, ing := range ingredient.ParseAll(recipe.Ingredients) {
+		words := ingredient.Words(ing.Item)
+		if ing.Unparsed || len(words) == 0 {
+			continue
+		}
+		for _, ex := range m.exclusions {
+			if ex.matches(words) {
+				return Coverage{}, false
+			}
+		}
+		c.Total++
+		if m.covers(ing.Item, words) {
+			c.Covered++
+		} else {
+			c.Missing = append(c.Missing, strings.TrimSpace(ing.Raw))
+		}
+	}
+	if c.Total > 0 {
+		c.Score = float64(c.Covered) / float64(c.Total)
+	}
+	return c, true
+}
+
+// covers reports whether an item on hand provides the ingredient item.
+// When both name a known food they must be the same food, or the
+// ingredient a more specific cut of it, as "chicken breast" is of
+// "chicken". Otherwise every word of the item on hand must occur in the
+// ingredient.
+func (m *Matcher) covers(item string, words []string) bool {
+	food, known := m.table.Match(item)
+	for _, t := range m.have {
+		if known && t.food != nil {
+			if food == t.food || hasPrefix(ingredient.Words(food.Name), ingredient.Words(t.food.Name)) {
+				return true
+			}
+			continue
+		}
+		if containsAll(words, t.words) {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is a recipe with its coverage.
+type Result struct {
+	Recipe   models.Recipe
+	Coverage Coverage
+}
+
+// Sort orders results best first: by score, then by fewest missing
+// ingredients, then by name.
+func Sort(results []Result) {
+	slices.SortStableFunc(results, func(a, b Result) int {
+		return cmp.Or(
+			cmp.Compare(b.Coverage.Score, a.Coverage.Score),
+			cmp.Compare(len(a.Coverage.Missing), len(b.Coverage.Missing)),
+			strings.Compare(a.Recipe.Name, b.Recipe.Name),
+		)
+	})
+}
+
+func containsAll(words, subset []string) bool {
+	for _, w := range subset {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefix(words, prefix []string) bool {
+	return len(prefix) <= len(words) && slices.Equal(words[:len(prefix)], prefix)
+}
@@ -0,0 +1,89 @@
+package pantry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+)
+
+var lemonChicken = models.Recipe{
+	Name: "Lemon Chicken",
+	Ingredients: models.Ingredients{
+		"2 boneless skinless chicken breasts",
+		"1 lemon, juiced",
+		"2 tablespoons olive oil",
+		"3 cloves garlic, minced",
+		"Salt and pepper to taste",
+		"1/4 cup chopped walnuts",
+	},
+}
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		have     []string
+		exclude  []string
+		excluded bool
+		covered  int
+		missing  string
+	}{
+		{
+			name:    "Specific cut of a food on hand",
+			have:    []string{"chicken", "lemons", "olive oil", "salt"},
+			covered: 4,
+			missing: "3 cloves garlic, minced|1/4 cup chopped walnuts",
+		},
+		{
+			name:    "Different food with a shared word",
+			have:    []string{"peanut butter", "garlic powder"},
+			covered: 0,
+		},
+		{
+			name:    "Unknown food by words",
+			have:    []string{"walnut"},
+			covered: 1,
+		},
+		{name: "Excluded group", have: []string{"chicken"}, exclude: []string{"no nuts"}, excluded: true},
+		{name: "Excluded ingredient", exclude: []string{"-garlic"}, excluded: true},
+		{name: "Free of an absent group", exclude: []string{"dairy-free"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(nutrition.Default(), tt.have, tt.exclude)
+			got, ok := m.Match(lemonChicken)
+			if ok == tt.excluded {
+				t.Fatalf("Match() ok = %v, expected %v", ok, !tt.excluded)
+			}
+			if tt.excluded {
+				return
+			}
+			if got.Total != 6 || got.Covered != tt.covered {
+				t.Errorf("Match() covered %d of %d, expected %d of 6", got.Covered, got.Total, tt.covered)
+			}
+			if tt.missing != "" && strings.Join(got.Missing, "|") != tt.missing {
+				t.Errorf("Match() missing = %q, expected %q", got.Missing, tt.missing)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	results := []Result{
+		{Recipe: models.Recipe{Name: "B"}, Coverage: Coverage{Score: 0.5, Missing: []string{"x", "y"}}},
+		{Recipe: models.Recipe{Name: "C"}, Coverage: Coverage{Score: 0.5, Missing: []string{"x"}}},
+		{Recipe: models.Recipe{Name: "A"}, Coverage: Coverage{Score: 1}},
+		{Recipe: models.Recipe{Name: "D"}, Coverage: Coverage{Score: 0.5, Missing: []string{"x"}}},
+	}
+	Sort(results)
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Recipe.Name)
+	}
+	if got := strings.Join(names, ","); got != "A,C,D,B" {
+		t.Errorf("Sort() = %s, expected A,C,D,B", got)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// defaultTrashRetention is how long deleted recipes stay in the trash when
+// TRASH_RETENTION is not set.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// maxPurgeInterval bounds the time between two purges, so recipes are
+// removed soon after their retention period ends.
+const maxPurgeInterval = time.Hour
+
+// trashRetention returns how long deleted recipes stay in the trash, read
+// from TRASH_RETENTION as a Go duration such as 720h. Zero keeps them
+// forever.
+func trashRetention() (time.Duration, error) {
+	value := os.Getenv("TRASH_RETENTION")
+	if value == "" {
+		return defaultTrashRetention, nil
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("TRASH_RETENTION must be a non-negative duration, got %q", value)
+	}
+	return retention, nil
+}
+
+// purgeTrash permanently removes the recipes deleted before cutoff along
+// with their revision history, and returns their ids. The history goes
+// first, so that when deleting it fails the recipe stays in the trash and
+// the next run tries again.
+func purgeTrash(ctx context.Context, backends stores, cutoff time.Time) ([]string, error) {
+	trash, err := backends.recipes.Trash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, recipe := range trash {
+		if !recipe.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if _, err := backends.revisions.Delete(ctx, recipe.ID); err != nil {
+			return nil, fmt.Errorf("revisions of %s: %w", recipe.ID, err)
+		}
+	}
+	return backends.recipes.Purge(ctx, cutoff)
+}
+
+// runPurger purges the recipes that have been in the trash for longer than
+// retention, at startup and then periodically until ctx is done. Failures
+// are logged and retried at the next run.
+func runPurger(ctx context.Context, backends stores, retention time.Duration) {
+	ticker := time.NewTicker(min(retention, maxPurgeInterval))
+	defer ticker.Stop()
+	for {
+		ids, err := purgeTrash(ctx, backends, time.Now().Add(-retention))
+		if len(ids) > 0 {
+			log.Printf("Purged %d deleted recipes", len(ids))
+		}
+		if err != nil {
+			log.Println("Error purging deleted recipes: ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
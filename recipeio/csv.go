@@ -0,0 +1,251 @@
+package recipeio
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// CSV layout
+//
+// A CSV export starts with a header row naming the columns below; the
+// importer matches columns by name, so they may appear in any order and
+// optional ones may be left out.
+//
+// List fields (tags, ingredients, instructions) are flattened into one
+// cell by joining their items with "|". Within every text cell, the
+// characters that would make this ambiguous or be altered by CSV readers
+// are escaped with a backslash:
+//
+//	\\  backslash
+//	\|  pipe
+//	\r  carriage return
+//	\n  line feed
+//
+// An empty list is an empty cell and a list holding a single empty item
+// is the lone escape \e. servings is a decimal integer, or empty when
+// unknown. publishedAt is an RFC 3339 timestamp with nanoseconds, or
+// empty for the zero time. The metadata columns that follow it, from yield
+// to attribution, are text cells; the times hold ISO 8601 durations.
+
+// column maps one CSV column to a recipe field.
+type column struct {
+	name     string
+	required bool
+	get      func(r models.Recipe) string
+	set      func(r *models.Recipe, cell string) error
+}
+
+var csvColumns = []column{
+	{
+		name:     "id",
+		required: true,
+		get:      func(r models.Recipe) string { return escapeCell(r.ID) },
+		set:      func(r *models.Recipe, cell string) (err error) { r.ID, err = unescapeCell(cell); return },
+	},
+	{
+		name:     "name",
+		required: true,
+		get:      func(r models.Recipe) string { return escapeCell(r.Name) },
+		set:      func(r *models.Recipe, cell string) (err error) { r.Name, err = unescapeCell(cell); return },
+	},
+	{
+		name: "tags",
+		get:  func(r models.Recipe) string { return joinList(r.Tags) },
+		set:  func(r *models.Recipe, cell string) (err error) { r.Tags, err = splitList[models.Tags](cell); return },
+	},
+	{
+		name: "ingredients",
+		get:  func(r models.Recipe) string { return joinList(r.Ingredients) },
+		set: func(r *models.Recipe, cell string) (err error) {
+			r.Ingredients, err = splitList[models.Ingredients](cell)
+			return
+		},
+	},
+	{
+		name: "instructions",
+		get:  func(r models.Recipe) string { return joinList(r.Instructions) },
+		set: func(r *models.Recipe, cell string) (err error) {
+			r.Instructions, err = splitList[models.Instructions](cell)
+			return
+		},
+	},
+	{
+		name: "servings",
+		get: func(r models.Recipe) string {
+			if r.Servings == 0 {
+				return ""
+			}
+			return strconv.Itoa(r.Servings)
+		},
+		set: func(r *models.Recipe, cell string) (err error) {
+			if cell == "" {
+				return nil
+			}
+			r.Servings, err = strconv.Atoi(cell)
+			return err
+		},
+	},
+	{
+		name: "publishedAt",
+		get: func(r models.Recipe) string {
+			if r.PublishedAt.IsZero() {
+				return ""
+			}
+			return r.PublishedAt.Format(time.RFC3339Nano)
+		},
+		set: func(r *models.Recipe, cell string) (err error) {
+			if cell == "" {
+				return nil
+			}
+			r.PublishedAt, err = time.Parse(time.RFC3339Nano, cell)
+			return err
+		},
+	},
+	textColumn("yield", func(r *models.Recipe) *string { return &r.Yield }),
+	textColumn("prepTime", func(r *models.Recipe) *models.Duration { return &r.PrepTime }),
+	textColumn("cookTime", func(r *models.Recipe) *models.Duration { return &r.CookTime }),
+	textColumn("totalTime", func(r *models.Recipe) *models.Duration { return &r.TotalTime }),
+	textColumn("difficulty", func(r *models.Recipe) *models.Difficulty { return &r.Difficulty }),
+	textColumn("cuisine", func(r *models.Recipe) *string { return &r.Cuisine }),
+	textColumn("author", func(r *models.Recipe) *string { return &r.Author }),
+	textColumn("sourceUrl", func(r *models.Recipe) *string { return &r.SourceURL }),
+	textColumn("attribution", func(r *models.Recipe) *string { return &r.Attribution }),
+}
+
+// textColumn is an optional column holding the escaped text of the string
+// field that field points to.
+func textColumn[T ~string](name string, field func(r *models.Recipe) *T) column {
+	return column{
+		name: name,
+		get:  func(r models.Recipe) string { return escapeCell(string(*field(&r))) },
+		set: func(r *models.Recipe, cell string) error {
+			text, err := unescapeCell(cell)
+			*field(r) = T(text)
+			return err
+		},
+	}
+}
+
+func csvHeader() []string {
+	header := make([]string, len(csvColumns))
+	for i, col := range csvColumns {
+		header[i] = col.name
+	}
+	return header
+}
+
+func csvRow(recipe models.Recipe) []string {
+	row := make([]string, len(csvColumns))
+	for i, col := range csvColumns {
+		row[i] = col.get(recipe)
+	}
+	return row
+}
+
+// csvColumnsFor resolves a header row to columns, rejecting unknown and
+// duplicate names and missing required columns.
+func csvColumnsFor(header []string) ([]column, error) {
+	cols := make([]column, len(header))
+	seen := make(map[string]bool)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		found := false
+		for _, col := range csvColumns {
+			if col.name == name {
+				cols[i], found = col, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		seen[name] = true
+	}
+	for _, col := range csvColumns {
+		if col.required && !seen[col.name] {
+			return nil, fmt.Errorf("missing CSV column %q", col.name)
+		}
+	}
+	return cols, nil
+}
+
+var cellEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", `\r`, "\n", `\n`)
+
+func escapeCell(s string) string {
+	return cellEscaper.Replace(s)
+}
+
+func joinList[T ~[]string](items T) string {
+	if len(items) == 1 && items[0] == "" {
+		return `\e`
+	}
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = escapeCell(item)
+	}
+	return strings.Join(escaped, "|")
+}
+
+func unescapeCell(cell string) (string, error) {
+	items, err := splitCell(cell, false)
+	if err != nil {
+		return "", err
+	}
+	return items[0], nil
+}
+
+func splitList[T ~[]string](cell string) (T, error) {
+	switch cell {
+	case "":
+		return nil, nil
+	case `\e`:
+		return T{""}, nil
+	}
+	items, err := splitCell(cell, true)
+	return T(items), err
+}
+
+var errBadEscape = errors.New("invalid escape sequence")
+
+// splitCell unescapes cell, splitting it at unescaped pipes when split is
+// set.
+func splitCell(cell string, split bool) ([]string, error) {
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(cell); i++ {
+		c := cell[i]
+		switch {
+		case c == '|' && split:
+			items = append(items, b.String())
+			b.Reset()
+		case c == '\\':
+			if i+1 == len(cell) {
+				return nil, errBadEscape
+			}
+			i++
+			switch cell[i] {
+			case '\\':
+				b.WriteByte('\\')
+			case '|':
+				b.WriteByte('|')
+			case 'r':
+				b.WriteByte('\r')
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				return nil, errBadEscape
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return append(items, b.String()), nil
+}
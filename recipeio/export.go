@@ -0,0 +1,40 @@
+package recipeio
+
+import (
+	"context"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// exportBatchSize is the number of recipes fetched from the store per
+// page while exporting.
+const exportBatchSize = 500
+
+// Export writes every recipe of s matching filter to w, page by page, and
+// closes w. afterPage, when not nil, is called after each page has been
+// flushed so HTTP handlers can push it to the client. It returns the
+// number of recipes written.
+func Export(ctx context.Context, s store.RecipeStore, w Writer, filter store.Filter, afterPage func()) (int, error) {
+	count := 0
+	opts := store.ListOptions{Filter: filter, Limit: exportBatchSize}
+	err := store.Walk(ctx, s, opts, func(recipe models.Recipe) error {
+		if err := w.Write(recipe); err != nil {
+			return err
+		}
+		count++
+		if count%exportBatchSize == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if afterPage != nil {
+				afterPage()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, w.Close()
+}
@@ -0,0 +1,119 @@
+package recipeio
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+func listAll(t *testing.T, s store.RecipeStore) []models.Recipe {
+	t.Helper()
+	var recipes []models.Recipe
+	err := store.Walk(context.Background(), s, store.ListOptions{}, func(recipe models.Recipe) error {
+		recipes = append(recipes, recipe)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	return recipes
+}
+
+func TestExport_RoundTripsSeedFile(t *testing.T) {
+	ctx := context.Background()
+	seed := store.NewMemoryStore()
+	importFile(t, seed, "../recipes.json", ImportOptions{DefaultPublishedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)})
+	expected := listAll(t, seed)
+
+	for _, format := range []Format{JSON, NDJSON, CSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			n, err := Export(ctx, seed, w, store.Filter{}, nil)
+			if err != nil || n != len(expected) {
+				t.Fatalf("Export() = %d, %v, expected %d recipes", n, err, len(expected))
+			}
+
+			restored := store.NewMemoryStore()
+			rd, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			report, err := Import(ctx, restored, rd, ImportOptions{})
+			if err != nil || report.Created != len(expected) {
+				t.Fatalf("Import() = %+v, %v, expected %d created", report, err, len(expected))
+			}
+			for i, recipe := range listAll(t, restored) {
+				if !reflect.DeepEqual(recipe, expected[i]) {
+					t.Fatalf("recipe %s changed in %s round trip:\n got %#v\nwant %#v", recipe.ID, format, recipe, expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExport_EmptyStore(t *testing.T) {
+	expected := map[Format]string{JSON: "[]\n", NDJSON: "", CSV: "id,name,tags,ingredients,instructions,servings,publishedAt," +
+		"yield,prepTime,cookTime,totalTime,difficulty,cuisine,author,sourceUrl,attribution\n"}
+	for format, want := range expected {
+		var buf bytes.Buffer
+		w, _ := NewWriter(&buf, format)
+		if _, err := Export(context.Background(), store.NewMemoryStore(), w, store.Filter{}, nil); err != nil {
+			t.Fatalf("Export(%s) error = %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("Export(%s) = %q, expected %q", format, buf.String(), want)
+		}
+	}
+}
+
+func TestCSVCellEncoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		items models.Ingredients
+		cell  string
+	}{
+		{"Empty list", nil, ""},
+		{"Single empty item", models.Ingredients{""}, `\e`},
+		{"Plain items", models.Ingredients{"salt", "pepper"}, "salt|pepper"},
+		{"Empty items kept", models.Ingredients{"", "salt", ""}, "|salt|"},
+		{"Escapes", models.Ingredients{"a|b", `c\d`, "1 lemon\r", "step\r\n\r\nnext"}, `a\|b|c\\d|1 lemon\r|step\r\n\r\nnext`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinList(tt.items); got != tt.cell {
+				t.Errorf("joinList() = %q, expected %q", got, tt.cell)
+			}
+			got, err := splitList[models.Ingredients](tt.cell)
+			if err != nil {
+				t.Fatalf("splitList() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.items) {
+				t.Errorf("splitList() = %q, expected %q", got, tt.items)
+			}
+		})
+	}
+
+	if _, err := splitList[models.Tags](`bad\x`); err == nil {
+		t.Error("splitList() accepted an unknown escape")
+	}
+}
+
+func TestNewReader_CSVHeaderErrors(t *testing.T) {
+	for _, header := range []string{"id,name,colour", "name,tags", "id,name,name"} {
+		rd, _ := NewReader(strings.NewReader(header+"\n"), CSV)
+		if _, err := rd.Next(); err == nil {
+			t.Errorf("Next() with header %q succeeded, expected an error", header)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package recipeio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// ImportOptions controls Import.
+type ImportOptions struct {
+	// DryRun reports what would change without writing to the store.
+	DryRun bool
+	// DefaultPublishedAt is assigned to records without publishedAt.
+	DefaultPublishedAt time.Time
+}
+
+// RecordError describes a record that was not imported.
+type RecordError struct {
+	Position int
+	ID       string
+	Err      error
+}
+
+func (e RecordError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("record %d: %v", e.Position, e.Err)
+	}
+	return fmt.Sprintf("record %d (%s): %v", e.Position, e.ID, e.Err)
+}
+
+// ImportReport counts the outcome of every record of an import.
+type ImportReport struct {
+	Created int
+	// Updated records replace a stored recipe, taking it out of the trash
+	// when it was deleted.
+	Updated int
+	// Skipped records are identical to the stored recipe.
+	Skipped int
+	Invalid int
+	Errors  []RecordError
+}
+
+// Import upserts every valid record of rd into s by id. Invalid records
+// are counted and described in the report without stopping the import;
+// the returned error is reserved for input and store failures.
+func Import(ctx context.Context, s store.RecipeStore, rd *Reader, opts ImportOptions) (ImportReport, error) {
+	var report ImportReport
+	var trashed map[string]bool
+	for {
+		record, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err != nil {
+			return report, err
+		}
+		recipe := record.Recipe
+		if record.Err == nil {
+			record.Err = validateRecord(&recipe, opts)
+		}
+		if record.Err != nil {
+			report.Invalid++
+			report.Errors = append(report.Errors, RecordError{Position: record.Position, ID: recipe.ID, Err: record.Err})
+			continue
+		}
+
+		existing, err := s.Get(ctx, recipe.ID)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+		case err != nil:
+			return report, err
+		case sameRecipe(*existing, recipe):
+			report.Skipped++
+			continue
+		}
+		created := existing == nil
+		if opts.DryRun {
+			// Get does not see the trash, which Upsert would empty.
+			if created && trashed == nil {
+				if trashed, err = trashedIDs(ctx, s); err != nil {
+					return report, err
+				}
+			}
+			created = created && !trashed[recipe.ID]
+		} else if created, err = s.Upsert(ctx, &recipe); err != nil {
+			return report, fmt.Errorf("record %d (%s): %w", record.Position, recipe.ID, err)
+		}
+		if created {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+}
+
+// trashedIDs returns the ids of the recipes in the trash of s.
+func trashedIDs(ctx context.Context, s store.RecipeStore) (map[string]bool, error) {
+	recipes, err := s.Trash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(recipes))
+	for _, recipe := range recipes {
+		ids[recipe.ID] = true
+	}
+	return ids, nil
+}
+
+// validateRecord checks an imported recipe and fills in defaults.
+func validateRecord(recipe *models.Recipe, opts ImportOptions) error {
+	if recipe.ID == "" {
+		return errors.New("id is required")
+	}
+	if err := recipe.Validate(); err != nil {
+		return err
+	}
+	if recipe.PublishedAt.IsZero() {
+		recipe.PublishedAt = opts.DefaultPublishedAt
+	}
+	recipe.Normalize()
+	return nil
+}
+
+// sameRecipe compares recipes at the millisecond precision MongoDB keeps
+// for dates, so re-importing an unchanged file skips every record. The
+// revision is ignored, as the store assigns it.
+func sameRecipe(a, b models.Recipe) bool {
+	if !a.PublishedAt.Truncate(time.Millisecond).Equal(b.PublishedAt.Truncate(time.Millisecond)) {
+		return false
+	}
+	a.PublishedAt, b.PublishedAt = time.Time{}, time.Time{}
+	a.Revision, b.Revision = 0, 0
+	return reflect.DeepEqual(a, b)
+}
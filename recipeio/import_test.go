@@ -0,0 +1,160 @@
+package recipeio
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+func importFile(t *testing.T, s store.RecipeStore, path string, opts ImportOptions) ImportReport {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+	rd, err := NewReader(f, FormatFromPath(path))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	report, err := Import(context.Background(), s, rd, opts)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	return report
+}
+
+func TestImport_SeedFileIsIdempotent(t *testing.T) {
+	s := store.NewMemoryStore()
+	defaultPublished := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := ImportOptions{DefaultPublishedAt: defaultPublished}
+
+	report := importFile(t, s, "../recipes.json", opts)
+	if report.Created != 493 || report.Updated != 0 || report.Skipped != 0 || report.Invalid != 0 {
+		t.Fatalf("first import = %+v, expected 493 created", report)
+	}
+	pizza, err := s.Get(context.Background(), "c0283p3d0cvuglq1085log")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !pizza.PublishedAt.Equal(defaultPublished) {
+		t.Errorf("missing publishedAt = %v, expected default %v", pizza.PublishedAt, defaultPublished)
+	}
+
+	report = importFile(t, s, "../recipes.json", opts)
+	if report.Skipped != 493 || report.Created != 0 || report.Updated != 0 {
+		t.Errorf("second import = %+v, expected 493 skipped", report)
+	}
+}
+
+func TestImport_NDJSON(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore(models.Recipe{
+		ID: "a", Name: "Old name", Ingredients: models.Ingredients{"x"}, Instructions: models.Instructions{"y"},
+	})
+	input := strings.Join([]string{
+		`{"id":"a","name":"New name","ingredients":["x"],"instructions":["y"],"publishedAt":"2021-01-17T19:28:52Z"}`,
+		``,
+		`{"id":"b","name":"Soup","ingredients":["water"],"instructions":["boil"],"publishedAt":"2021-01-17T19:28:52Z"}`,
+		`{"id":"c","name":"","ingredients":["x"],"instructions":["y"]}`,
+		`{"name":"No id","ingredients":["x"],"instructions":["y"]}`,
+		`{"id":"d","name":"Typo","ingredient":["x"],"instructions":["y"]}`,
+		`not json`,
+	}, "\n")
+
+	for _, dryRun := range []bool{true, false} {
+		rd, err := NewReader(strings.NewReader(input), "")
+		if err != nil {
+			t.Fatalf("NewReader() error = %v", err)
+		}
+		report, err := Import(ctx, s, rd, ImportOptions{DryRun: dryRun})
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if report.Created != 1 || report.Updated != 1 || report.Invalid != 4 {
+			t.Errorf("Import(dryRun=%v) = %+v, expected 1 created, 1 updated, 4 invalid", dryRun, report)
+		}
+		positions := make([]int, len(report.Errors))
+		for i, recordErr := range report.Errors {
+			positions[i] = recordErr.Position
+		}
+		if len(positions) != 4 || positions[0] != 4 || positions[3] != 7 {
+			t.Errorf("invalid record lines = %v, expected [4 5 6 7]", positions)
+		}
+
+		_, err = s.Get(ctx, "b")
+		if dryRun && err == nil {
+			t.Error("dry run created recipe b")
+		}
+		if !dryRun && err != nil {
+			t.Errorf("Get(b) after import error = %v", err)
+		}
+	}
+}
+
+func TestImport_CSVMalformedRows(t *testing.T) {
+	input := strings.Join([]string{
+		"id,name,ingredients,instructions",
+		"a,Soup,water,boil",
+		"b,Short row",
+		`c,"Bad "quote",water,boil`,
+		"d,Stew,beef|water,simmer",
+	}, "\n")
+	rd, err := NewReader(strings.NewReader(input), CSV)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	report, err := Import(context.Background(), store.NewMemoryStore(), rd, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Created != 2 || report.Invalid != 2 {
+		t.Errorf("Import() = %+v, expected 2 created, 2 invalid", report)
+	}
+	if len(report.Errors) == 2 && (report.Errors[0].Position != 3 || report.Errors[1].Position != 4) {
+		t.Errorf("invalid records = %v, expected rows 3 and 4", report.Errors)
+	}
+}
+
+func TestImport_Trashed(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore(models.Recipe{
+		ID: "a", Name: "Soup", Ingredients: models.Ingredients{"water"}, Instructions: models.Instructions{"boil"},
+	})
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	input := `{"id":"a","name":"Soup","ingredients":["water"],"instructions":["boil"],"publishedAt":"2021-01-17T19:28:52Z"}`
+
+	for _, dryRun := range []bool{true, false} {
+		rd, err := NewReader(strings.NewReader(input), NDJSON)
+		if err != nil {
+			t.Fatalf("NewReader() error = %v", err)
+		}
+		report, err := Import(ctx, s, rd, ImportOptions{DryRun: dryRun})
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if report.Created != 0 || report.Updated != 1 {
+			t.Errorf("Import(dryRun=%v) = %+v, expected the trashed recipe updated", dryRun, report)
+		}
+	}
+	if _, err := s.Get(ctx, "a"); err != nil {
+		t.Errorf("Get() after import error = %v, expected the recipe out of the trash", err)
+	}
+}
+
+func TestNewReader_RejectsNonArrayJSON(t *testing.T) {
+	rd, err := NewReader(strings.NewReader(`{"id":"a"}`), JSON)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	if _, err := rd.Next(); err == nil {
+		t.Error("Next() on a JSON object succeeded, expected an error")
+	}
+}
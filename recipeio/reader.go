@@ -0,0 +1,213 @@
+// Package recipeio reads and writes recipe collections in the JSON,
+// NDJSON and CSV interchange formats used by the import and export
+// commands.
+package recipeio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Format is a recipe interchange format.
+type Format string
+
+const (
+	// JSON is a single array of recipes, like recipes.json.
+	JSON Format = "json"
+	// NDJSON is one recipe object per line.
+	NDJSON Format = "ndjson"
+	// CSV is a header row followed by one recipe per row, see csv.go for
+	// the cell encoding.
+	CSV Format = "csv"
+)
+
+// ParseFormat validates a format name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case JSON, NDJSON, CSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// FormatFromPath guesses the format from a file extension, returning ""
+// when the extension is not recognized.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON
+	case ".ndjson", ".jsonl":
+		return NDJSON
+	case ".csv":
+		return CSV
+	}
+	return ""
+}
+
+// Record is a recipe read from an input together with its position. Err
+// is set when the record could not be decoded; Recipe is then partial.
+type Record struct {
+	// Position is the 1-based index of the record in a JSON array, its
+	// line number in NDJSON or its row number, counting the header, in CSV.
+	Position int
+	Recipe   models.Recipe
+	Err      error
+}
+
+// Reader decodes recipes one at a time without loading the whole input.
+type Reader struct {
+	format   Format
+	dec      *json.Decoder
+	lines    *bufio.Scanner
+	csv      *csv.Reader
+	columns  []column
+	position int
+	started  bool
+}
+
+// NewReader returns a Reader for r. An empty format is detected from the
+// first non-blank byte: "[" selects JSON, anything else NDJSON; CSV must
+// be requested explicitly.
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	br := bufio.NewReader(r)
+	if format == "" {
+		format = NDJSON
+		for {
+			b, err := br.ReadByte()
+			if err != nil {
+				break
+			}
+			if !strings.ContainsRune(" \t\r\n", rune(b)) {
+				if b == '[' {
+					format = JSON
+				}
+				br.UnreadByte()
+				break
+			}
+		}
+	}
+	rd := &Reader{format: format}
+	switch format {
+	case JSON:
+		rd.dec = json.NewDecoder(br)
+	case NDJSON:
+		rd.lines = bufio.NewScanner(br)
+		rd.lines.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	case CSV:
+		rd.csv = csv.NewReader(br)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return rd, nil
+}
+
+// Next returns the next record or io.EOF after the last one. Malformed
+// records are returned with Err set so callers can report and skip them;
+// a non-nil error from Next means the input cannot be read further.
+func (rd *Reader) Next() (Record, error) {
+	switch rd.format {
+	case JSON:
+		return rd.nextJSON()
+	case CSV:
+		return rd.nextCSV()
+	}
+	return rd.nextNDJSON()
+}
+
+func (rd *Reader) nextJSON() (Record, error) {
+	if !rd.started {
+		rd.started = true
+		tok, err := rd.dec.Token()
+		if err == io.EOF {
+			return Record{}, io.EOF
+		}
+		if err != nil {
+			return Record{}, err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return Record{}, errors.New("JSON input must be an array of recipes")
+		}
+	}
+	if !rd.dec.More() {
+		if _, err := rd.dec.Token(); err != nil {
+			return Record{}, err
+		}
+		return Record{}, io.EOF
+	}
+	rd.position++
+	var raw json.RawMessage
+	if err := rd.dec.Decode(&raw); err != nil {
+		return Record{}, err
+	}
+	return decodeRecord(rd.position, raw), nil
+}
+
+func (rd *Reader) nextNDJSON() (Record, error) {
+	for rd.lines.Scan() {
+		rd.position++
+		line := bytes.TrimSpace(rd.lines.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return decodeRecord(rd.position, line), nil
+	}
+	if err := rd.lines.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+func (rd *Reader) nextCSV() (Record, error) {
+	if !rd.started {
+		rd.started = true
+		header, err := rd.csv.Read()
+		if err != nil {
+			return Record{}, err
+		}
+		rd.position++
+		if rd.columns, err = csvColumnsFor(header); err != nil {
+			return Record{}, err
+		}
+	}
+	// A malformed row, such as one with missing cells, is an invalid
+	// record; only read failures stop the import.
+	row, err := rd.csv.Read()
+	var parseErr *csv.ParseError
+	if err != nil && !errors.As(err, &parseErr) {
+		return Record{}, err
+	}
+	rd.position++
+	record := Record{Position: rd.position}
+	if parseErr != nil {
+		record.Err = parseErr.Err
+		return record, nil
+	}
+	for i, col := range rd.columns {
+		if err := col.set(&record.Recipe, row[i]); err != nil {
+			record.Err = fmt.Errorf("column %s: %w", col.name, err)
+			break
+		}
+	}
+	return record, nil
+}
+
+// decodeRecord decodes a single recipe object, rejecting fields that are
+// not part of models.Recipe.
+func decodeRecord(position int, data []byte) Record {
+	record := Record{Position: position}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&record.Recipe); err != nil {
+		record.Err = err
+	}
+	return record
+}
@@ -0,0 +1,119 @@
+package recipeio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Writer encodes recipes one at a time in a Format.
+type Writer interface {
+	// Write encodes one recipe.
+	Write(recipe models.Recipe) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+	// Close terminates the output, e.g. the closing bracket of a JSON
+	// array, and flushes it. It does not close the underlying writer.
+	Close() error
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case CSV:
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// NewWriter returns a Writer encoding recipes to w in format.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case JSON:
+		return &jsonWriter{w: w}, nil
+	case NDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case CSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// jsonWriter writes a JSON array, one recipe per line.
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (jw *jsonWriter) Write(recipe models.Recipe) error {
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if jw.count == 0 {
+		sep = "[\n"
+	}
+	jw.count++
+	if _, err := io.WriteString(jw.w, sep); err != nil {
+		return err
+	}
+	_, err = jw.w.Write(data)
+	return err
+}
+
+func (jw *jsonWriter) Flush() error { return nil }
+
+func (jw *jsonWriter) Close() error {
+	end := "\n]\n"
+	if jw.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(jw.w, end)
+	return err
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (nw *ndjsonWriter) Write(recipe models.Recipe) error { return nw.enc.Encode(recipe) }
+func (nw *ndjsonWriter) Flush() error                     { return nil }
+func (nw *ndjsonWriter) Close() error                     { return nil }
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (cw *csvWriter) writeHeader() error {
+	if cw.headerWritten {
+		return nil
+	}
+	cw.headerWritten = true
+	return cw.w.Write(csvHeader())
+}
+
+func (cw *csvWriter) Write(recipe models.Recipe) error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	return cw.w.Write(csvRow(recipe))
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	return cw.Flush()
+}
@@ -0,0 +1,77 @@
+package searchindex
+
+import (
+	"strings"
+	"unicode"
+
+	porterstemmer "github.com/blevesearch/go-porterstemmer"
+)
+
+// stopWords are not indexed since they occur in nearly every recipe.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "in": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// analyze splits text into lower-case words and returns them paired with
+// their Porter stems, skipping stop words.
+func analyze(text string) (words, stems []string) {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range fields {
+		if stopWords[word] {
+			continue
+		}
+		words = append(words, word)
+		stems = append(stems, stem(word))
+	}
+	return words, stems
+}
+
+func stem(word string) string {
+	return porterstemmer.StemString(word)
+}
+
+// maxEdits is the typo tolerance for a term of the given length: none for
+// short words, one edit from four runes and two from eight.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// editDistance returns the Levenshtein distance between a and b, or
+// limit+1 as soon as it is known to exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,253 @@
+// Package searchindex is an embedded, file-backed full-text index over
+// recipes for deployments without a search service.
+//
+// It stores Porter-stemmed terms weighted by field, like the MongoDB text
+// index, and adds prefix queries and typo-tolerant fuzzy matching so that
+// "mozarella" still finds "mozzarella". The index lives in memory and is
+// snapshotted to a single file by Flush, which callers run periodically
+// and before exiting.
+package searchindex
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// formatVersion is bumped whenever the snapshot layout or the analyzer
+// changes; older snapshots are rejected and must be rebuilt.
+const formatVersion = 1
+
+// ErrStaleIndex is returned by Open when the snapshot was written by an
+// incompatible version and needs a rebuild.
+var ErrStaleIndex = errors.New("search index format is outdated, rebuild it")
+
+// document is the indexed form of a recipe.
+type document struct {
+	// Terms maps a stem to its field-weighted frequency in the recipe.
+	Terms map[string]float64
+	// Words maps a stem to the words of the recipe it was derived from.
+	Words map[string][]string
+}
+
+// snapshot is the on-disk representation of an Index.
+type snapshot struct {
+	Version int
+	Docs    map[string]document
+}
+
+// Index is an inverted index over recipes. It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	path     string
+	docs     map[string]document
+	postings map[string]map[string]float64
+	// changes counts the writes to docs, guarded by mu.
+	changes uint64
+
+	// saveMu serializes Flush; saved is the value of changes last written
+	// to path, guarded by saveMu.
+	saveMu sync.Mutex
+	saved  uint64
+}
+
+// Open loads the index stored at path, or returns an empty index that will
+// be saved there when path does not exist yet. An empty path keeps the
+// index in memory only.
+func Open(path string) (*Index, error) {
+	idx := &Index{
+		path:     path,
+		docs:     make(map[string]document),
+		postings: make(map[string]map[string]float64),
+	}
+	if path == "" {
+		return idx, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("reading search index %s: %w", path, err)
+	}
+	if snap.Version != formatVersion {
+		return nil, ErrStaleIndex
+	}
+	for id, doc := range snap.Docs {
+		idx.add(id, doc)
+	}
+	return idx, nil
+}
+
+// Len returns the number of indexed recipes.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Index adds or replaces recipe in the index. The change reaches the file
+// at the next Flush.
+func (idx *Index) Index(recipe models.Recipe) {
+	doc := newDocument(recipe)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(recipe.ID)
+	idx.add(recipe.ID, doc)
+	idx.changes++
+}
+
+// Delete removes the recipe with the given id. Deleting an unknown id is
+// not an error. The change reaches the file at the next Flush.
+func (idx *Index) Delete(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	idx.changes++
+}
+
+// Reset replaces the whole content of the index with recipes and saves it.
+func (idx *Index) Reset(recipes []models.Recipe) error {
+	idx.mu.Lock()
+	idx.docs = make(map[string]document, len(recipes))
+	idx.postings = make(map[string]map[string]float64)
+	for _, recipe := range recipes {
+		idx.add(recipe.ID, newDocument(recipe))
+	}
+	idx.changes++
+	idx.mu.Unlock()
+	return idx.Flush()
+}
+
+// Flush saves the index to its file when it changed since the last save.
+// Searches and writes go on while the file is written, from a copy of
+// the index taken under the lock.
+func (idx *Index) Flush() error {
+	if idx.path == "" {
+		return nil
+	}
+	idx.saveMu.Lock()
+	defer idx.saveMu.Unlock()
+	idx.mu.RLock()
+	changes := idx.changes
+	if changes == idx.saved {
+		idx.mu.RUnlock()
+		return nil
+	}
+	// Documents are never changed once added, so a shallow copy is a
+	// consistent snapshot.
+	docs := maps.Clone(idx.docs)
+	idx.mu.RUnlock()
+	if err := idx.save(docs); err != nil {
+		return err
+	}
+	idx.saved = changes
+	return nil
+}
+
+// FlushEvery calls Flush every interval until ctx is done. Failures are
+// logged and retried at the next tick; callers flush once more when they
+// stop.
+func (idx *Index) FlushEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := idx.Flush(); err != nil {
+				log.Println("Error saving search index: ", err)
+			}
+		}
+	}
+}
+
+func newDocument(recipe models.Recipe) document {
+	doc := document{Terms: make(map[string]float64), Words: make(map[string][]string)}
+	addText := func(text string, weight float64) {
+		words, stems := analyze(text)
+		for i, s := range stems {
+			doc.Terms[s] += weight
+			if !contains(doc.Words[s], words[i]) {
+				doc.Words[s] = append(doc.Words[s], words[i])
+			}
+		}
+	}
+	addText(recipe.Name, fulltext.NameWeight)
+	for _, ingredient := range recipe.Ingredients {
+		addText(ingredient, fulltext.IngredientsWeight)
+	}
+	for _, instruction := range recipe.Instructions {
+		addText(instruction, fulltext.InstructionsWeight)
+	}
+	return doc
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// add and remove maintain docs and postings; callers hold mu.
+func (idx *Index) add(id string, doc document) {
+	idx.docs[id] = doc
+	for term, weight := range doc.Terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]float64)
+		}
+		idx.postings[term][id] = weight
+	}
+}
+
+func (idx *Index) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.Terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// save writes a snapshot of docs next to path and renames it into place
+// so a crash never leaves a truncated index behind. Callers hold saveMu.
+func (idx *Index) save(docs map[string]document) error {
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), filepath.Base(idx.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := gob.NewEncoder(tmp).Encode(snapshot{Version: formatVersion, Docs: docs}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), idx.path)
+}
@@ -0,0 +1,213 @@
+package searchindex
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+func testRecipes() []models.Recipe {
+	return []models.Recipe{
+		{
+			ID:          "pizza",
+			Name:        "Homemade Pizza",
+			Ingredients: models.Ingredients{"feta cheese, firm mozzarella cheese, grated", "3 3/4 cups (490 g) bread flour"},
+			Instructions: models.Instructions{
+				"Place the warm water in the large bowl of a heavy duty stand mixer.",
+			},
+		},
+		{
+			ID:           "chicken",
+			Name:         "Oregano Marinated Chicken",
+			Ingredients:  models.Ingredients{"4 (6 to 7-ounce) boneless skinless chicken breasts\r", "1 teaspoon dried oregano\r"},
+			Instructions: models.Instructions{"Marinate the chicken before baking it."},
+		},
+	}
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx, _ := Open("")
+	if err := idx.Reset(testRecipes()); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+		words    []string
+	}{
+		{"Exact term", "mozzarella", []string{"pizza"}, []string{"mozzarella"}},
+		{"Typo", "mozarella", []string{"pizza"}, []string{"mozzarella"}},
+		{"Two typos in long word", "mozarela", []string{"pizza"}, []string{"mozzarella"}},
+		{"Prefix", "oreg*", []string{"chicken"}, []string{"oregano"}},
+		{"Stemming", "breast bakes", []string{"chicken"}, []string{"breasts", "baking"}},
+		{"Name outranks instructions", "pizza chicken", []string{"chicken", "pizza"}, nil},
+		{"Phrase words required", `"stand mixer" chicken`, []string{}, nil},
+		{"Short words are not fuzzy", "fet", []string{}, nil},
+		{"No match", "chocolate", []string{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := idx.Search(fulltext.Parse(tt.query), 10)
+			if got := hitIDs(hits); !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("Search(%q) = %v, expected %v", tt.query, got, tt.expected)
+			}
+			if tt.words != nil && !reflect.DeepEqual(hits[0].Words, tt.words) {
+				t.Errorf("Search(%q) words = %v, expected %v", tt.query, hits[0].Words, tt.words)
+			}
+		})
+	}
+}
+
+func TestIndex_PersistsAcrossOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recipes.idx")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for _, recipe := range testRecipes() {
+		idx.Index(recipe)
+	}
+	idx.Delete("pizza")
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Stat() before Flush() error = %v, expected no file", err)
+	}
+	if err := idx.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	info, _ := os.Stat(path)
+	if err := idx.Flush(); err != nil {
+		t.Fatalf("Flush() unchanged error = %v", err)
+	}
+	if again, _ := os.Stat(path); !again.ModTime().Equal(info.ModTime()) || !os.SameFile(info, again) {
+		t.Errorf("Flush() without changes wrote the file again")
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if reopened.Len() != 1 {
+		t.Errorf("Len() = %d, expected 1", reopened.Len())
+	}
+	if got := hitIDs(reopened.Search(fulltext.Parse("chicken mozzarella"), 10)); !reflect.DeepEqual(got, []string{"chicken"}) {
+		t.Errorf("Search() after reopen = %v, expected [chicken]", got)
+	}
+}
+
+func TestStore_KeepsIndexInSync(t *testing.T) {
+	ctx := context.Background()
+	backend := store.NewMemoryStore(testRecipes()...)
+	idx, _ := Open("")
+	if n, err := Rebuild(ctx, backend, idx); err != nil || n != 2 {
+		t.Fatalf("Rebuild() = %d, %v, expected 2 recipes", n, err)
+	}
+	s := NewStore(backend, idx)
+
+	if err := s.Create(ctx, &models.Recipe{ID: "cake", Name: "Chocolate Cake"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := s.Update(ctx, "pizza", &models.Recipe{Name: "Margherita"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := s.Delete(ctx, "chicken"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"chocolat", []string{"cake"}},
+		{"margherita", []string{"pizza"}},
+		{"mozzarella", []string{}},
+		{"oregano", []string{}},
+	}
+	for _, tt := range tests {
+		results, err := s.TextSearch(ctx, fulltext.Parse(tt.query), 10)
+		if err != nil {
+			t.Fatalf("TextSearch() error = %v", err)
+		}
+		ids := make([]string, len(results))
+		for i, result := range results {
+			ids[i] = result.Recipe.ID
+		}
+		if !reflect.DeepEqual(ids, tt.expected) {
+			t.Errorf("TextSearch(%q) = %v, expected %v", tt.query, ids, tt.expected)
+		}
+	}
+
+	if _, err := s.Restore(ctx, "chicken"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if results, _ := s.TextSearch(ctx, fulltext.Parse("oregano"), 10); len(results) != 1 || results[0].Recipe.ID != "chicken" {
+		t.Errorf("TextSearch(oregano) after Restore() = %+v, expected chicken", results)
+	}
+}
+
+func TestStore_WithTransaction(t *testing.T) {
+	ctx := context.Background()
+	idx, _ := Open("")
+	s := NewStore(store.NewMemoryStore(testRecipes()...), idx)
+
+	failed := errors.New("failed")
+	err := s.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Create(ctx, &models.Recipe{ID: "cake", Name: "Chocolate Cake"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTransaction() error = %v, expected failed", err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("Len() after rollback = %d, expected nothing indexed", idx.Len())
+	}
+
+	err = s.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.Create(ctx, &models.Recipe{ID: "cake", Name: "Chocolate Cake"})
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+	if got := hitIDs(idx.Search(fulltext.Parse("chocolate"), 10)); !reflect.DeepEqual(got, []string{"cake"}) {
+		t.Errorf("Search() after commit = %v, expected [cake]", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		limit    int
+		expected int
+	}{
+		{"mozarella", "mozzarella", 2, 1},
+		{"oregano", "oregano", 1, 0},
+		{"flour", "floor", 1, 1},
+		{"sugar", "salt", 1, 2},
+		{"a", "abcdef", 2, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.expected {
+			t.Errorf("editDistance(%q, %q, %d) = %d, expected %d", tt.a, tt.b, tt.limit, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package searchindex
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+)
+
+// Match quality multipliers applied to the score of non-exact matches.
+const (
+	prefixQuality = 0.8
+	fuzzyQuality  = 0.5
+)
+
+// Hit is a recipe matching a search with its relevance. Words are the
+// recipe words that matched, which may differ from the query after
+// stemming, prefix or fuzzy expansion.
+type Hit struct {
+	ID    string
+	Score float64
+	Words []string
+}
+
+// clause is one query word expanded to the indexed stems it matches, with
+// the quality of each match.
+type clause map[string]float64
+
+// Search returns up to limit hits for q, best first. Terms and prefixes
+// are alternatives; every word of every phrase is required. Terms without
+// an exact match fall back to fuzzy matching. Phrase word order is not
+// checked by the index.
+func (idx *Index) Search(q fulltext.Query, limit int) []Hit {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var optional, required []clause
+	for _, term := range q.Terms {
+		optional = append(optional, idx.expandTerm(term))
+	}
+	for _, prefix := range q.Prefixes {
+		optional = append(optional, idx.expandPrefix(prefix))
+	}
+	for _, phrase := range q.Phrases {
+		for _, word := range phrase {
+			required = append(required, idx.expandTerm(word))
+		}
+	}
+
+	scores := make(map[string]*Hit)
+	candidates := idx.candidates(optional, required)
+	for id := range candidates {
+		doc := idx.docs[id]
+		hit := &Hit{ID: id}
+		matchedOptional := len(optional) == 0
+		matchedRequired := true
+		for _, c := range optional {
+			if idx.scoreClause(c, doc, hit) {
+				matchedOptional = true
+			}
+		}
+		for _, c := range required {
+			if !idx.scoreClause(c, doc, hit) {
+				matchedRequired = false
+			}
+		}
+		if matchedOptional && matchedRequired && hit.Score > 0 {
+			scores[id] = hit
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for _, hit := range scores {
+		hits = append(hits, *hit)
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// expandTerm matches term exactly after stemming, or else every indexed
+// stem within its typo tolerance.
+func (idx *Index) expandTerm(term string) clause {
+	s := stem(term)
+	if _, ok := idx.postings[s]; ok {
+		return clause{s: 1}
+	}
+	c := clause{}
+	if k := maxEdits(s); k > 0 {
+		for indexed := range idx.postings {
+			if d := editDistance(s, indexed, k); d <= k {
+				c[indexed] = fuzzyQuality / float64(d)
+			}
+		}
+	}
+	return c
+}
+
+// expandPrefix matches every indexed stem starting with the stem of
+// prefix.
+func (idx *Index) expandPrefix(prefix string) clause {
+	s := stem(prefix)
+	c := clause{}
+	for indexed := range idx.postings {
+		if strings.HasPrefix(indexed, s) {
+			c[indexed] = prefixQuality
+		}
+	}
+	return c
+}
+
+// candidates returns the ids of documents containing at least one stem of
+// the clauses.
+func (idx *Index) candidates(clauses ...[]clause) map[string]bool {
+	ids := make(map[string]bool)
+	for _, group := range clauses {
+		for _, c := range group {
+			for s := range c {
+				for id := range idx.postings[s] {
+					ids[id] = true
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// scoreClause adds the TF-IDF contribution of c for doc to hit and
+// reports whether the document matched the clause.
+func (idx *Index) scoreClause(c clause, doc document, hit *Hit) bool {
+	matched := false
+	for s, quality := range c {
+		weight, ok := doc.Terms[s]
+		if !ok {
+			continue
+		}
+		idf := 1 + math.Log(float64(len(idx.docs))/float64(len(idx.postings[s])))
+		hit.Score += quality * weight * idf
+		for _, word := range doc.Words[s] {
+			if !contains(hit.Words, word) {
+				hit.Words = append(hit.Words, word)
+			}
+		}
+		matched = true
+	}
+	return matched
+}
@@ -0,0 +1,145 @@
+package searchindex
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/store"
+)
+
+// Store wraps a RecipeStore so that every write is mirrored into an Index
+// and full-text search is answered by the index instead of the backend.
+// Writes made in a transaction reach the index once it commits.
+type Store struct {
+	store.RecipeStore
+	index *Index
+}
+
+// NewStore returns a Store keeping index in sync with s.
+func NewStore(s store.RecipeStore, index *Index) *Store {
+	return &Store{RecipeStore: s, index: index}
+}
+
+// Create implements store.RecipeStore.
+func (s *Store) Create(ctx context.Context, recipe *models.Recipe) error {
+	if err := s.RecipeStore.Create(ctx, recipe); err != nil {
+		return err
+	}
+	s.reindex(ctx, *recipe)
+	return nil
+}
+
+// Update implements store.RecipeStore.
+func (s *Store) Update(ctx context.Context, id string, recipe *models.Recipe) (*models.Recipe, error) {
+	updated, err := s.RecipeStore.Update(ctx, id, recipe)
+	if err != nil {
+		return nil, err
+	}
+	s.reindex(ctx, *updated)
+	return updated, nil
+}
+
+// Upsert implements store.RecipeStore.
+func (s *Store) Upsert(ctx context.Context, recipe *models.Recipe) (bool, error) {
+	created, err := s.RecipeStore.Upsert(ctx, recipe)
+	if err != nil {
+		return false, err
+	}
+	s.reindex(ctx, *recipe)
+	return created, nil
+}
+
+// Delete implements store.RecipeStore.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if err := s.RecipeStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.afterCommit(ctx, func() { s.index.Delete(id) })
+	return nil
+}
+
+// WithTransaction implements store.Transactor when the wrapped store does,
+// and returns store.ErrNoTransactions otherwise.
+func (s *Store) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := s.RecipeStore.(store.Transactor)
+	if !ok {
+		return store.ErrNoTransactions
+	}
+	var pending []func()
+	err := tx.WithTransaction(ctx, func(ctx context.Context) error {
+		pending = pending[:0]
+		return fn(context.WithValue(ctx, pendingKey{}, &pending))
+	})
+	if err != nil {
+		return err
+	}
+	for _, apply := range pending {
+		apply()
+	}
+	return nil
+}
+
+// pendingKey is the context key of the index changes a transaction makes
+// once it commits.
+type pendingKey struct{}
+
+// afterCommit applies a change to the index, or queues it until the
+// transaction of ctx commits.
+func (s *Store) afterCommit(ctx context.Context, apply func()) {
+	if pending, ok := ctx.Value(pendingKey{}).(*[]func()); ok {
+		*pending = append(*pending, apply)
+		return
+	}
+	apply()
+}
+
+// Restore implements store.RecipeStore, indexing the recipe again.
+func (s *Store) Restore(ctx context.Context, id string) (*models.Recipe, error) {
+	restored, err := s.RecipeStore.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.reindex(ctx, *restored)
+	return restored, nil
+}
+
+// TextSearch implements store.RecipeStore from the index, loading the
+// matching recipes from the wrapped store. Hits whose recipe has since
+// disappeared from the store are skipped.
+func (s *Store) TextSearch(ctx context.Context, query fulltext.Query, limit int) ([]store.ScoredRecipe, error) {
+	hits := s.index.Search(query, store.ListOptions{Limit: limit}.EffectiveLimit())
+	results := make([]store.ScoredRecipe, 0, len(hits))
+	for _, hit := range hits {
+		recipe, err := s.RecipeStore.Get(ctx, hit.ID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, store.ScoredRecipe{Recipe: *recipe, Score: hit.Score, Terms: hit.Words})
+	}
+	return results, nil
+}
+
+// reindex updates the index after a successful write. The index is saved
+// to its file by Flush, not here.
+func (s *Store) reindex(ctx context.Context, recipe models.Recipe) {
+	s.afterCommit(ctx, func() { s.index.Index(recipe) })
+}
+
+// Rebuild replaces the content of index with every recipe of s and
+// returns the number of recipes indexed.
+func Rebuild(ctx context.Context, s store.RecipeStore, index *Index) (int, error) {
+	recipes := make([]models.Recipe, 0)
+	err := store.Walk(ctx, s, store.ListOptions{Limit: store.MaxListLimit}, func(recipe models.Recipe) error {
+		recipes = append(recipes, recipe)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(recipes), index.Reset(recipes)
+}
@@ -0,0 +1,41 @@
+package shopping
+
+import (
+	"strings"
+)
+
+// Text renders l as plain text: each aisle in capitals followed by its
+// items, indented.
+func (l List) Text() string {
+	var b strings.Builder
+	for i, aisle := range l.Aisles {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.ToUpper(aisle.Name) + "\n")
+		for _, item := range aisle.Items {
+			b.WriteString("  " + item.Text + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders l as a Markdown checklist with a heading per aisle.
+func (l List) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Shopping list\n")
+	for _, aisle := range l.Aisles {
+		b.WriteString("\n## " + capitalize(aisle.Name) + "\n\n")
+		for _, item := range aisle.Items {
+			b.WriteString("- [ ] " + item.Text + "\n")
+		}
+	}
+	return b.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,224 @@
+// Package shopping builds consolidated shopping lists from recipes,
+// merging the same ingredient across recipes and grouping items by store
+// aisle.
+package shopping
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+)
+
+// ErrNoServings is returned when servings are requested for a recipe that
+// does not say how many it makes.
+var ErrNoServings = errors.New("recipe does not specify servings and cannot be scaled")
+
+// Entry is one recipe to shop for. A zero Servings keeps the recipe's own
+// amounts.
+type Entry struct {
+	Recipe   models.Recipe
+	Servings int
+}
+
+// List is a shopping list grouped by aisle.
+type List struct {
+	Aisles []Aisle `json:"aisles"`
+}
+
+// Aisle is a store section and the items to pick up there.
+type Aisle struct {
+	Name  string `json:"name" example:"produce"`
+	Items []Item `json:"items"`
+}
+
+// Item is one ingredient to buy. Amounts holds one total per kind of
+// measure, since a volume, a weight and a count cannot be added up; it is
+// empty when no recipe gives an amount, as for "salt, to taste".
+type Item struct {
+	Name    string   `json:"name" example:"all-purpose flour"`
+	Amounts []Amount `json:"amounts"`
+	Text    string   `json:"text" example:"2 1/4 cups all-purpose flour"`
+	Recipes []string `json:"recipes"`
+}
+
+// Amount is a total quantity of an item.
+type Amount struct {
+	Quantity float64 `json:"quantity" example:"2.25"`
+	Unit     string  `json:"unit,omitempty" example:"cup"`
+}
+
+// aisleOrder is the order aisles are listed in, roughly the walk through a
+// grocery store. Aisles not listed come after these, alphabetically.
+var aisleOrder = []string{"produce", "bakery", "meat and seafood", "dairy and eggs", "baking", "spices", "pantry", "beverages", "frozen", otherAisle}
+
+const otherAisle = "other"
+
+// descriptors are words that describe how an ingredient is prepared or
+// sized rather than what to buy; they are ignored when merging, so that
+// "large eggs" and "eggs" are one item.
+var descriptors = map[string]bool{
+	"large": true, "small": true, "medium": true, "fresh": true, "freshly": true,
+	"finely": true, "coarsely": true, "roughly": true, "thinly": true, "chopped": true,
+	"minced": true, "diced": true, "sliced": true, "grated": true, "shredded": true,
+	"peeled": true, "cold": true, "warm": true, "lukewarm": true, "softened": true,
+	"melted": true, "room": true, "temperature": true, "extra": true, "ripe": true,
+}
+
+// group accumulates the amounts of one item.
+type group struct {
+	name    string
+	aisle   string
+	base    map[ingredient.Dimension]float64
+	counts  map[string]float64
+	system  ingredient.System
+	recipes []string
+}
+
+// Build merges the ingredients of entries into a shopping list, rescaling
+// recipes with servings first. Volumes and weights are summed after
+// conversion and expressed in sys, or in the system the first recipe used
+// for the item when sys is zero. table supplies the aisle of each item.
+func Build(entries []Entry, table *nutrition.Table, sys ingredient.System) (List, error) {
+	groups := map[string]*group{}
+	var order []string
+	for _, entry := range entries {
+		factor := 1.0
+		if entry.Servings > 0 && entry.Servings != entry.Recipe.Servings {
+			if entry.Recipe.Servings == 0 {
+				return List{}, ErrNoServings
+			}
+			factor = float64(entry.Servings) / float64(entry.Recipe.Servings)
+		}
+		for _, ing := range ingredient.ParseAll(entry.Recipe.Ingredients) {
+			if ing.Unparsed {
+				continue
+			}
+			key := itemKey(ing.Item)
+			if key == "" {
+				continue
+			}
+			g, ok := groups[key]
+			if !ok {
+				g = &group{
+					name:   strings.ToLower(ing.Item),
+					aisle:  aisleOf(table, ing.Item),
+					base:   map[ingredient.Dimension]float64{},
+					counts: map[string]float64{},
+					system: sys,
+				}
+				groups[key] = g
+				order = append(order, key)
+			}
+			g.add(ing, factor)
+			if !slices.Contains(g.recipes, entry.Recipe.Name) {
+				g.recipes = append(g.recipes, entry.Recipe.Name)
+			}
+		}
+	}
+
+	byAisle := map[string][]Item{}
+	for _, key := range order {
+		g := groups[key]
+		byAisle[g.aisle] = append(byAisle[g.aisle], g.item())
+	}
+	list := List{Aisles: []Aisle{}}
+	for _, name := range sortedAisles(byAisle) {
+		items := byAisle[name]
+		slices.SortStableFunc(items, func(a, b Item) int { return strings.Compare(a.Name, b.Name) })
+		list.Aisles = append(list.Aisles, Aisle{Name: name, Items: items})
+	}
+	return list, nil
+}
+
+// itemKey identifies an ingredient for merging: its singular words without
+// descriptors.
+func itemKey(item string) string {
+	var words []string
+	for _, w := range ingredient.Words(item) {
+		if !descriptors[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func aisleOf(table *nutrition.Table, item string) string {
+	if food, ok := table.Match(item); ok && food.Aisle != "" {
+		return food.Aisle
+	}
+	return otherAisle
+}
+
+func (g *group) add(ing ingredient.Ingredient, factor float64) {
+	if len(ing.Item) < len(g.name) {
+		g.name = strings.ToLower(ing.Item)
+	}
+	if ing.Quantity == nil {
+		return
+	}
+	m := ingredient.Measure{Quantity: *ing.Quantity, Unit: ing.Unit}
+	if amount, dim, ok := m.Base(); ok {
+		if g.system == 0 {
+			_, g.system, _ = ingredient.UnitDimension(ing.Unit)
+		}
+		g.base[dim] += amount * factor
+		return
+	}
+	g.counts[ing.Unit] += ing.Quantity.Mean() * factor
+}
+
+func (g *group) item() Item {
+	item := Item{Name: g.name, Amounts: []Amount{}, Recipes: g.recipes}
+	var measures []ingredient.Measure
+	for _, dim := range []ingredient.Dimension{ingredient.Volume, ingredient.Mass} {
+		if amount, ok := g.base[dim]; ok {
+			measures = append(measures, ingredient.FromBase(amount, dim, g.system))
+		}
+	}
+	units := make([]string, 0, len(g.counts))
+	for unit := range g.counts {
+		units = append(units, unit)
+	}
+	slices.Sort(units)
+	for _, unit := range units {
+		measures = append(measures, ingredient.Measure{Quantity: ingredient.Quantity{Value: g.counts[unit]}, Unit: unit}.Tidy(0))
+	}
+	for _, m := range measures {
+		item.Amounts = append(item.Amounts, Amount{Quantity: m.Quantity.Value, Unit: m.Unit})
+	}
+	item.Text = itemText(g.name, measures)
+	return item
+}
+
+// itemText renders an item as "2 cups + 1 tablespoon olive oil".
+func itemText(name string, measures []ingredient.Measure) string {
+	parts := make([]string, len(measures))
+	for i, m := range measures {
+		parts[i] = m.String()
+	}
+	return strings.TrimSpace(strings.Join(parts, " + ") + " " + name)
+}
+
+func sortedAisles(byAisle map[string][]Item) []string {
+	names := make([]string, 0, len(byAisle))
+	for name := range byAisle {
+		names = append(names, name)
+	}
+	rank := func(name string) int {
+		if i := slices.Index(aisleOrder, name); i >= 0 {
+			return i
+		}
+		return len(aisleOrder)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra - rb
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
@@ -0,0 +1,113 @@
+package shopping
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"github.com/mrojasb2000/GinRecipes/nutrition"
+)
+
+var (
+	pancakes = models.Recipe{
+		Name: "Pancakes",
+		Ingredients: models.Ingredients{
+			"1 1/2 cups all-purpose flour",
+			"2 tablespoons sugar",
+			"1 large egg",
+			"1 1/4 cups milk",
+			"Pinch of salt",
+		},
+		Servings: 4,
+	}
+	crepes = models.Recipe{
+		Name: "Crepes",
+		Ingredients: models.Ingredients{
+			"1 cup all-purpose flour\r",
+			"2 eggs",
+			"120 ml milk",
+			"salt, to taste",
+			"<hr>",
+			"1 pint strawberries, sliced",
+		},
+	}
+)
+
+func TestBuild(t *testing.T) {
+	list, err := Build([]Entry{{Recipe: pancakes, Servings: 8}, {Recipe: crepes}}, nutrition.Default(), 0)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	expected := map[string][]string{
+		"produce":        {"2 cups strawberries"},
+		"dairy and eggs": {"4 eggs", "3 cups milk"},
+		"baking":         {"4 cups all-purpose flour", "1/4 cup sugar"},
+		"spices":         {"2 pinches salt"},
+	}
+	got := map[string][]string{}
+	var aisles []string
+	for _, aisle := range list.Aisles {
+		aisles = append(aisles, aisle.Name)
+		for _, item := range aisle.Items {
+			got[aisle.Name] = append(got[aisle.Name], item.Text)
+		}
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Build() = %q, expected %q", got, expected)
+	}
+	if order := []string{"produce", "dairy and eggs", "baking", "spices"}; !reflect.DeepEqual(aisles, order) {
+		t.Errorf("Build() aisles = %q, expected %q", aisles, order)
+	}
+	if recipes := list.Aisles[1].Items[0].Recipes; !reflect.DeepEqual(recipes, []string{"Pancakes", "Crepes"}) {
+		t.Errorf("Build() egg recipes = %q, expected both recipes", recipes)
+	}
+}
+
+func TestBuild_Metric(t *testing.T) {
+	list, err := Build([]Entry{{Recipe: crepes}}, nutrition.Default(), ingredient.Metric)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for _, aisle := range list.Aisles {
+		for _, item := range aisle.Items {
+			if item.Name == "all-purpose flour" && item.Text != "235 milliliters all-purpose flour" {
+				t.Errorf("Build() flour = %q, expected 235 milliliters", item.Text)
+			}
+		}
+	}
+}
+
+func TestBuild_NoServings(t *testing.T) {
+	if _, err := Build([]Entry{{Recipe: crepes, Servings: 2}}, nutrition.Default(), 0); err != ErrNoServings {
+		t.Errorf("Build() error = %v, expected %v", err, ErrNoServings)
+	}
+}
+
+func TestBuild_Empty(t *testing.T) {
+	list, err := Build(nil, nutrition.Default(), 0)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got, _ := json.Marshal(list); string(got) != `{"aisles":[]}` {
+		t.Errorf("Build() = %s, expected no aisles", got)
+	}
+}
+
+func TestList_Formats(t *testing.T) {
+	list := List{Aisles: []Aisle{
+		{Name: "produce", Items: []Item{{Text: "2 lemons"}, {Text: "1 bunch kale"}}},
+		{Name: "spices", Items: []Item{{Text: "salt"}}},
+	}}
+
+	text := "PRODUCE\n  2 lemons\n  1 bunch kale\n\nSPICES\n  salt\n"
+	if got := list.Text(); got != text {
+		t.Errorf("Text() = %q, expected %q", got, text)
+	}
+	markdown := "# Shopping list\n\n## Produce\n\n- [ ] 2 lemons\n- [ ] 1 bunch kale\n\n## Spices\n\n- [ ] salt\n"
+	if got := list.Markdown(); got != markdown {
+		t.Errorf("Markdown() = %q, expected %q", got, markdown)
+	}
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+// or was issued for a different ordering.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursor is the position of the last recipe of a page. It records the
+// ordering it was issued for together with the sort key and id of that
+// recipe.
+type cursor struct {
+	Sort        SortField `json:"s"`
+	Descending  bool      `json:"d,omitempty"`
+	Name        string    `json:"n,omitempty"`
+	PublishedAt time.Time `json:"p"`
+	ID          string    `json:"i"`
+}
+
+func newCursor(recipe models.Recipe, opts ListOptions) cursor {
+	c := cursor{Sort: opts.sort(), Descending: opts.Descending, ID: recipe.ID}
+	switch c.Sort {
+	case SortByName:
+		c.Name = recipe.Name
+	default:
+		c.PublishedAt = recipe.PublishedAt
+	}
+	return c
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses the cursor of opts, returning nil for the first page.
+func decodeCursor(opts ListOptions) (*cursor, error) {
+	if opts.Cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(opts.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != opts.sort() || c.Descending != opts.Descending {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// recipe returns a recipe holding the cursor's sort key and id, suitable
+// for ListOptions.compare.
+func (c cursor) recipe() models.Recipe {
+	return models.Recipe{ID: c.ID, Name: c.Name, PublishedAt: c.PublishedAt}
+}
+
+// paginate trims recipes, which hold up to limit+1 entries following the
+// cursor, to a Page.
+func paginate(recipes []models.Recipe, opts ListOptions) *Page {
+	limit := opts.EffectiveLimit()
+	page := &Page{Recipes: recipes}
+	if len(recipes) > limit {
+		page.Recipes = recipes[:limit]
+		page.NextCursor = newCursor(recipes[limit-1], opts).encode()
+	}
+	return page
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mrojasb2000/GinRecipes/ingredient"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// IngredientQuery selects recipes by the ingredients they use. Include and
+// Exclude hold ingredient names normalized by NewIngredientQuery, so
+// "Chicken Breasts" is stored as "chicken breast".
+type IngredientQuery struct {
+	Include []string
+	Exclude []string
+}
+
+// NewIngredientQuery normalizes ingredient names into an IngredientQuery,
+// dropping duplicates and names without words.
+func NewIngredientQuery(include, exclude []string) IngredientQuery {
+	return IngredientQuery{Include: normalizeTerms(include), Exclude: normalizeTerms(exclude)}
+}
+
+// Empty reports whether q has no ingredient to look for.
+func (q IngredientQuery) Empty() bool {
+	return len(q.Include) == 0
+}
+
+func normalizeTerms(names []string) []string {
+	var terms []string
+	for _, name := range names {
+		term := strings.Join(ingredient.Words(name), " ")
+		if term != "" && !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Match scores recipe against q. A term matches an ingredient line that
+// contains every word of it in any order, so "chicken" matches "2 chicken
+// breasts". The score is the share of Include terms matched. Recipes with
+// an excluded ingredient or without any included one do not match.
+func (q IngredientQuery) Match(recipe models.Recipe) (ScoredRecipe, bool) {
+	lines := make([][]string, len(recipe.Ingredients))
+	for i, line := range recipe.Ingredients {
+		lines[i] = ingredient.Words(line)
+	}
+	for _, term := range q.Exclude {
+		if termMatches(term, lines) {
+			return ScoredRecipe{}, false
+		}
+	}
+	var matched []string
+	for _, term := range q.Include {
+		if termMatches(term, lines) {
+			matched = append(matched, term)
+		}
+	}
+	if len(matched) == 0 {
+		return ScoredRecipe{}, false
+	}
+	return ScoredRecipe{
+		Recipe: recipe,
+		Score:  float64(len(matched)) / float64(len(q.Include)),
+		Terms:  matched,
+	}, true
+}
+
+func termMatches(term string, lines [][]string) bool {
+	for _, words := range lines {
+		found := true
+		for _, w := range strings.Fields(term) {
+			if !slices.Contains(words, w) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// termRegex matches the ingredient lines containing every word of term, in
+// singular or plural, the way Match does, for use in MongoDB queries.
+func termRegex(term string) string {
+	var b strings.Builder
+	for _, w := range strings.Fields(term) {
+		forms := ingredient.Forms(w)
+		for i, form := range forms {
+			forms[i] = regexp.QuoteMeta(form)
+		}
+		b.WriteString(`(?=.*\b(?:` + strings.Join(forms, "|") + `)\b)`)
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// ErrMealPlanNotFound is returned when no meal plan matches the requested
+// id.
+var ErrMealPlanNotFound = errors.New("meal plan not found")
+
+// MealPlanStore is the storage contract for meal plans. It is implemented
+// alongside RecipeStore by the MongoDB and in-memory backends.
+type MealPlanStore interface {
+	// Create persists a new meal plan. The plan must already carry its id.
+	Create(ctx context.Context, plan *models.MealPlan) error
+	// Get returns the meal plan with the given id or ErrMealPlanNotFound.
+	Get(ctx context.Context, id string) (*models.MealPlan, error)
+	// List returns the meal plans of owner, or of every owner when owner
+	// is empty, ordered by start date and then id.
+	List(ctx context.Context, owner string) ([]models.MealPlan, error)
+	// Update replaces the owner, name, dates and meals of the meal plan
+	// with the given id and returns the stored result.
+	Update(ctx context.Context, id string, plan *models.MealPlan) (*models.MealPlan, error)
+	// Delete removes the meal plan with the given id or returns
+	// ErrMealPlanNotFound.
+	Delete(ctx context.Context, id string) error
+	// FlagDeletedRecipe marks every meal planned with recipeID as
+	// RecipeDeleted and returns the number of meal plans changed.
+	FlagDeletedRecipe(ctx context.Context, recipeID string) (int, error)
+	// ClearDeletedRecipe undoes FlagDeletedRecipe once the recipe is
+	// restored and returns the number of meal plans changed.
+	ClearDeletedRecipe(ctx context.Context, recipeID string) (int, error)
+}
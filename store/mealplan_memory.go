@@ -0,0 +1,126 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// MemoryMealPlanStore is a MealPlanStore backed by a map guarded by a
+// mutex.
+type MemoryMealPlanStore struct {
+	mu    sync.RWMutex
+	plans map[string]*models.MealPlan
+}
+
+// NewMemoryMealPlanStore returns an empty MemoryMealPlanStore.
+func NewMemoryMealPlanStore() *MemoryMealPlanStore {
+	return &MemoryMealPlanStore{plans: make(map[string]*models.MealPlan)}
+}
+
+// Create implements MealPlanStore.
+func (s *MemoryMealPlanStore) Create(_ context.Context, plan *models.MealPlan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.plans[plan.ID]; ok {
+		return ErrDuplicateID
+	}
+	s.plans[plan.ID] = clonePlan(plan)
+	return nil
+}
+
+// Get implements MealPlanStore.
+func (s *MemoryMealPlanStore) Get(_ context.Context, id string) (*models.MealPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	plan, ok := s.plans[id]
+	if !ok {
+		return nil, ErrMealPlanNotFound
+	}
+	return clonePlan(plan), nil
+}
+
+// List implements MealPlanStore.
+func (s *MemoryMealPlanStore) List(_ context.Context, owner string) ([]models.MealPlan, error) {
+	s.mu.RLock()
+	plans := make([]models.MealPlan, 0)
+	for _, plan := range s.plans {
+		if owner == "" || plan.Owner == owner {
+			plans = append(plans, *clonePlan(plan))
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(plans, func(a, b models.MealPlan) int {
+		return cmp.Or(cmp.Compare(a.StartDate, b.StartDate), cmp.Compare(a.ID, b.ID))
+	})
+	return plans, nil
+}
+
+// Update implements MealPlanStore.
+func (s *MemoryMealPlanStore) Update(_ context.Context, id string, plan *models.MealPlan) (*models.MealPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.plans[id]
+	if !ok {
+		return nil, ErrMealPlanNotFound
+	}
+	stored.Owner = plan.Owner
+	stored.Name = plan.Name
+	stored.StartDate = plan.StartDate
+	stored.EndDate = plan.EndDate
+	stored.Meals = slices.Clone(plan.Meals)
+	stored.UpdatedAt = plan.UpdatedAt
+	return clonePlan(stored), nil
+}
+
+// Delete implements MealPlanStore.
+func (s *MemoryMealPlanStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.plans[id]; !ok {
+		return ErrMealPlanNotFound
+	}
+	delete(s.plans, id)
+	return nil
+}
+
+// FlagDeletedRecipe implements MealPlanStore.
+func (s *MemoryMealPlanStore) FlagDeletedRecipe(_ context.Context, recipeID string) (int, error) {
+	return s.setRecipeDeleted(recipeID, true), nil
+}
+
+// ClearDeletedRecipe implements MealPlanStore.
+func (s *MemoryMealPlanStore) ClearDeletedRecipe(_ context.Context, recipeID string) (int, error) {
+	return s.setRecipeDeleted(recipeID, false), nil
+}
+
+// setRecipeDeleted sets RecipeDeleted on every meal planned with recipeID
+// and returns the number of meal plans changed.
+func (s *MemoryMealPlanStore) setRecipeDeleted(recipeID string, deleted bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := 0
+	for _, plan := range s.plans {
+		flagged := false
+		for i := range plan.Meals {
+			if meal := &plan.Meals[i]; meal.RecipeID == recipeID && meal.RecipeDeleted != deleted {
+				meal.RecipeDeleted, flagged = deleted, true
+			}
+		}
+		if flagged {
+			changed++
+		}
+	}
+	return changed
+}
+
+// clonePlan copies plan so callers never share its meals with the store.
+func clonePlan(plan *models.MealPlan) *models.MealPlan {
+	cloned := *plan
+	cloned.Meals = slices.Clone(plan.Meals)
+	return &cloned
+}
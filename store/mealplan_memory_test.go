@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+func TestMemoryMealPlanStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryMealPlanStore()
+	plans := []models.MealPlan{
+		{ID: "p2", Owner: "maria", StartDate: "2024-03-11", Meals: []models.Meal{{RecipeID: "soup"}}},
+		{ID: "p1", Owner: "maria", StartDate: "2024-03-04", Meals: []models.Meal{{RecipeID: "pizza"}, {RecipeID: "soup"}}},
+		{ID: "p3", Owner: "tom", StartDate: "2024-03-04", Meals: []models.Meal{{RecipeID: "pizza"}}},
+	}
+	for i := range plans {
+		if err := s.Create(ctx, &plans[i]); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := s.Create(ctx, &plans[0]); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("Create() duplicate error = %v, want ErrDuplicateID", err)
+	}
+
+	listed, err := s.List(ctx, "maria")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != "p1" || listed[1].ID != "p2" {
+		t.Errorf("List() = %v, want p1 then p2", listed)
+	}
+
+	changed, err := s.FlagDeletedRecipe(ctx, "soup")
+	if err != nil {
+		t.Fatalf("FlagDeletedRecipe() error = %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("FlagDeletedRecipe() = %d, want 2", changed)
+	}
+	got, err := s.Get(ctx, "p1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Meals[0].RecipeDeleted || !got.Meals[1].RecipeDeleted {
+		t.Errorf("Get() meals = %+v, want only soup flagged", got.Meals)
+	}
+	if plans[1].Meals[1].RecipeDeleted {
+		t.Error("FlagDeletedRecipe() changed the caller's plan")
+	}
+	if changed, _ := s.ClearDeletedRecipe(ctx, "soup"); changed != 2 {
+		t.Errorf("ClearDeletedRecipe() = %d, want 2", changed)
+	}
+	if got, _ := s.Get(ctx, "p1"); got.Meals[1].RecipeDeleted {
+		t.Errorf("Get() meals = %+v, want soup no longer flagged", got.Meals)
+	}
+
+	if err := s.Delete(ctx, "p1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "p1"); !errors.Is(err, ErrMealPlanNotFound) {
+		t.Errorf("Get() deleted error = %v, want ErrMealPlanNotFound", err)
+	}
+	if _, err := s.Update(ctx, "p1", &plans[1]); !errors.Is(err, ErrMealPlanNotFound) {
+		t.Errorf("Update() deleted error = %v, want ErrMealPlanNotFound", err)
+	}
+}
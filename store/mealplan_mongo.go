@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoMealPlanStore is a MealPlanStore backed by a MongoDB collection.
+// Like MongoStore it addresses documents by their "id" field.
+type MongoMealPlanStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoMealPlanStore returns a MongoMealPlanStore operating on
+// collection.
+func NewMongoMealPlanStore(collection *mongo.Collection) *MongoMealPlanStore {
+	return &MongoMealPlanStore{collection: collection}
+}
+
+// EnsureIndexes creates a unique index on the meal plan id, an index
+// backing List and a multikey index on the planned recipes used by
+// FlagDeletedRecipe and ClearDeletedRecipe.
+func (s *MongoMealPlanStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "startDate", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "meals.recipeId", Value: 1}}},
+	})
+	return err
+}
+
+// Create implements MealPlanStore.
+func (s *MongoMealPlanStore) Create(ctx context.Context, plan *models.MealPlan) error {
+	if _, err := s.collection.InsertOne(ctx, plan); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateID
+		}
+		return err
+	}
+	return nil
+}
+
+// Get implements MealPlanStore.
+func (s *MongoMealPlanStore) Get(ctx context.Context, id string) (*models.MealPlan, error) {
+	var plan models.MealPlan
+	err := s.collection.FindOne(ctx, bson.M{"id": id}).Decode(&plan)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMealPlanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// List implements MealPlanStore.
+func (s *MongoMealPlanStore) List(ctx context.Context, owner string) ([]models.MealPlan, error) {
+	filter := bson.M{}
+	if owner != "" {
+		filter["owner"] = owner
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "startDate", Value: 1}, {Key: "id", Value: 1}})
+	cur, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	plans := make([]models.MealPlan, 0)
+	if err := cur.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// Update implements MealPlanStore.
+func (s *MongoMealPlanStore) Update(ctx context.Context, id string, plan *models.MealPlan) (*models.MealPlan, error) {
+	update := bson.D{{
+		Key: "$set", Value: bson.D{
+			{Key: "owner", Value: plan.Owner},
+			{Key: "name", Value: plan.Name},
+			{Key: "startDate", Value: plan.StartDate},
+			{Key: "endDate", Value: plan.EndDate},
+			{Key: "meals", Value: plan.Meals},
+			{Key: "updatedAt", Value: plan.UpdatedAt},
+		}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.MealPlan
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"id": id}, update, opts).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMealPlanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete implements MealPlanStore.
+func (s *MongoMealPlanStore) Delete(ctx context.Context, id string) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrMealPlanNotFound
+	}
+	return nil
+}
+
+// FlagDeletedRecipe implements MealPlanStore with a single update that
+// flags the matching meals of every plan in place.
+func (s *MongoMealPlanStore) FlagDeletedRecipe(ctx context.Context, recipeID string) (int, error) {
+	return s.setRecipeDeleted(ctx, recipeID, true)
+}
+
+// ClearDeletedRecipe implements MealPlanStore like FlagDeletedRecipe.
+func (s *MongoMealPlanStore) ClearDeletedRecipe(ctx context.Context, recipeID string) (int, error) {
+	return s.setRecipeDeleted(ctx, recipeID, false)
+}
+
+// setRecipeDeleted sets recipeDeleted on the meals planned with recipeID.
+func (s *MongoMealPlanStore) setRecipeDeleted(ctx context.Context, recipeID string, deleted bool) (int, error) {
+	update := bson.M{"$set": bson.M{"meals.$[meal].recipeDeleted": deleted}}
+	opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"meal.recipeId": recipeID}})
+	result, err := s.collection.UpdateMany(ctx, bson.M{"meals.recipeId": recipeID}, update, opts)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
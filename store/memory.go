@@ -0,0 +1,311 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// MemoryStore is a RecipeStore backed by a map guarded by a mutex.
+// Recipes are kept in insertion order so listings are deterministic.
+// Deleted recipes move to a separate map, so nothing but the trash
+// methods ever sees them.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	recipes map[string]*models.Recipe
+	order   []string
+	trash   map[string]*models.Recipe
+}
+
+// NewMemoryStore returns a MemoryStore seeded with the given recipes.
+func NewMemoryStore(seed ...models.Recipe) *MemoryStore {
+	s := &MemoryStore{recipes: make(map[string]*models.Recipe), trash: make(map[string]*models.Recipe)}
+	for i := range seed {
+		recipe := seed[i]
+		recipe.Revision = max(recipe.Revision, 1)
+		s.recipes[recipe.ID] = &recipe
+		s.order = append(s.order, recipe.ID)
+	}
+	return s
+}
+
+// Create implements RecipeStore.
+func (s *MemoryStore) Create(ctx context.Context, recipe *models.Recipe) error {
+	defer s.lock(ctx)()
+	if _, ok := s.recipes[recipe.ID]; ok {
+		return ErrDuplicateID
+	}
+	if _, ok := s.trash[recipe.ID]; ok {
+		return ErrDuplicateID
+	}
+	recipe.Revision = 1
+	recipe.DeletedAt = time.Time{}
+	stored := *recipe
+	s.recipes[recipe.ID] = &stored
+	s.order = append(s.order, recipe.ID)
+	return nil
+}
+
+// Get implements RecipeStore.
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.Recipe, error) {
+	defer s.rlock(ctx)()
+	recipe, ok := s.recipes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	found := *recipe
+	return &found, nil
+}
+
+// List implements RecipeStore.
+func (s *MemoryStore) List(ctx context.Context, opts ListOptions) (*Page, error) {
+	after, err := decodeCursor(opts)
+	if err != nil {
+		return nil, err
+	}
+	unlock := s.rlock(ctx)
+	recipes := make([]models.Recipe, 0, len(s.order))
+	for _, id := range s.order {
+		recipe := s.recipes[id]
+		if !opts.Filter.Matches(*recipe) {
+			continue
+		}
+		if after != nil && opts.compare(after.recipe(), *recipe) >= 0 {
+			continue
+		}
+		recipes = append(recipes, *recipe)
+	}
+	unlock()
+
+	slices.SortFunc(recipes, opts.compare)
+	if limit := opts.EffectiveLimit(); len(recipes) > limit+1 {
+		recipes = recipes[:limit+1]
+	}
+	return paginate(recipes, opts), nil
+}
+
+// Update implements RecipeStore.
+func (s *MemoryStore) Update(ctx context.Context, id string, recipe *models.Recipe) (*models.Recipe, error) {
+	defer s.lock(ctx)()
+	stored, ok := s.recipes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if recipe.Revision != 0 && recipe.Revision != stored.Revision {
+		return nil, ErrRevisionMismatch
+	}
+	stored.Revision++
+	stored.Name = recipe.Name
+	stored.Tags = recipe.Tags
+	stored.Ingredients = recipe.Ingredients
+	stored.Instructions = recipe.Instructions
+	stored.Servings = recipe.Servings
+	stored.Yield = recipe.Yield
+	stored.PrepTime = recipe.PrepTime
+	stored.CookTime = recipe.CookTime
+	stored.TotalTime = recipe.TotalTime
+	stored.TotalMinutes = recipe.TotalMinutes
+	stored.Difficulty = recipe.Difficulty
+	stored.Cuisine = recipe.Cuisine
+	stored.Author = recipe.Author
+	stored.SourceURL = recipe.SourceURL
+	stored.Attribution = recipe.Attribution
+	updated := *stored
+	return &updated, nil
+}
+
+// Upsert implements RecipeStore.
+func (s *MemoryStore) Upsert(ctx context.Context, recipe *models.Recipe) (bool, error) {
+	defer s.lock(ctx)()
+	existing, exists := s.recipes[recipe.ID]
+	trashed, deleted := s.trash[recipe.ID]
+	recipe.Revision = 1
+	switch {
+	case exists:
+		recipe.Revision = existing.Revision + 1
+	case deleted:
+		recipe.Revision = trashed.Revision + 1
+		delete(s.trash, recipe.ID)
+	}
+	recipe.DeletedAt = time.Time{}
+	stored := *recipe
+	s.recipes[recipe.ID] = &stored
+	if !exists {
+		s.order = append(s.order, recipe.ID)
+	}
+	return !exists && !deleted, nil
+}
+
+// Delete implements RecipeStore.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	defer s.lock(ctx)()
+	recipe, ok := s.recipes[id]
+	if !ok {
+		return ErrNotFound
+	}
+	recipe.DeletedAt = time.Now()
+	s.trash[id] = recipe
+	delete(s.recipes, id)
+	s.order = slices.DeleteFunc(s.order, func(existing string) bool { return existing == id })
+	return nil
+}
+
+// Trash implements RecipeStore.
+func (s *MemoryStore) Trash(ctx context.Context) ([]models.Recipe, error) {
+	unlock := s.rlock(ctx)
+	recipes := make([]models.Recipe, 0, len(s.trash))
+	for _, recipe := range s.trash {
+		recipes = append(recipes, *recipe)
+	}
+	unlock()
+	slices.SortFunc(recipes, func(a, b models.Recipe) int {
+		if c := b.DeletedAt.Compare(a.DeletedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return recipes, nil
+}
+
+// Restore implements RecipeStore.
+func (s *MemoryStore) Restore(ctx context.Context, id string) (*models.Recipe, error) {
+	defer s.lock(ctx)()
+	recipe, ok := s.trash[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	recipe.DeletedAt = time.Time{}
+	s.recipes[id] = recipe
+	s.order = append(s.order, id)
+	delete(s.trash, id)
+	restored := *recipe
+	return &restored, nil
+}
+
+// Purge implements RecipeStore.
+func (s *MemoryStore) Purge(ctx context.Context, cutoff time.Time) ([]string, error) {
+	defer s.lock(ctx)()
+	ids := make([]string, 0)
+	for id, recipe := range s.trash {
+		if recipe.DeletedAt.Before(cutoff) {
+			ids = append(ids, id)
+			delete(s.trash, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// Search implements RecipeStore.
+func (s *MemoryStore) Search(ctx context.Context, filter Filter) ([]models.Recipe, error) {
+	defer s.rlock(ctx)()
+	recipes := make([]models.Recipe, 0)
+	for _, id := range s.order {
+		if recipe := s.recipes[id]; filter.Matches(*recipe) {
+			recipes = append(recipes, *recipe)
+		}
+	}
+	slices.SortFunc(recipes, ListOptions{}.compare)
+	return recipes, nil
+}
+
+// TextSearch implements RecipeStore by scoring every recipe in memory.
+func (s *MemoryStore) TextSearch(ctx context.Context, query fulltext.Query, limit int) ([]ScoredRecipe, error) {
+	unlock := s.rlock(ctx)
+	results := make([]ScoredRecipe, 0)
+	for _, id := range s.order {
+		recipe := s.recipes[id]
+		if score := fulltext.Score(*recipe, query); score > 0 {
+			results = append(results, ScoredRecipe{Recipe: *recipe, Score: score})
+		}
+	}
+	unlock()
+
+	return rankScored(results, limit), nil
+}
+
+// IngredientSearch implements RecipeStore by matching every recipe in
+// memory.
+func (s *MemoryStore) IngredientSearch(ctx context.Context, query IngredientQuery, limit int) ([]ScoredRecipe, error) {
+	unlock := s.rlock(ctx)
+	results := make([]ScoredRecipe, 0)
+	for _, id := range s.order {
+		if hit, ok := query.Match(*s.recipes[id]); ok {
+			results = append(results, hit)
+		}
+	}
+	unlock()
+	return rankScored(results, limit), nil
+}
+
+// WithTransaction implements Transactor. The store stays locked while fn
+// runs, so no other write interleaves with the transaction, and its
+// content is put back as it was when fn fails.
+func (s *MemoryStore) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(memoryTx{s}) != nil {
+		return fn(ctx)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recipes, trash, order := cloneRecipes(s.recipes), cloneRecipes(s.trash), slices.Clone(s.order)
+	if err := fn(context.WithValue(ctx, memoryTx{s}, true)); err != nil {
+		s.recipes, s.trash, s.order = recipes, trash, order
+		return err
+	}
+	return nil
+}
+
+// memoryTx is the context key marking a transaction of a MemoryStore,
+// during which its methods run under the lock WithTransaction holds.
+type memoryTx struct{ s *MemoryStore }
+
+// lock write-locks the store, unless ctx belongs to a transaction already
+// holding the lock, and returns the function unlocking it.
+func (s *MemoryStore) lock(ctx context.Context) func() {
+	if ctx.Value(memoryTx{s}) != nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// rlock is lock for reading.
+func (s *MemoryStore) rlock(ctx context.Context) func() {
+	if ctx.Value(memoryTx{s}) != nil {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// cloneRecipes copies recipes, so that changes made in place to the
+// stored recipes do not reach the copy.
+func cloneRecipes(recipes map[string]*models.Recipe) map[string]*models.Recipe {
+	cloned := make(map[string]*models.Recipe, len(recipes))
+	for id, recipe := range recipes {
+		copied := *recipe
+		cloned[id] = &copied
+	}
+	return cloned
+}
+
+// rankScored sorts results by descending score and then id and keeps the
+// first limit of them.
+func rankScored(results []ScoredRecipe, limit int) []ScoredRecipe {
+	slices.SortFunc(results, func(a, b ScoredRecipe) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Recipe.ID, b.Recipe.ID)
+	})
+	if limit = (ListOptions{Limit: limit}).EffectiveLimit(); len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+func seedRecipes() []models.Recipe {
+	return []models.Recipe{
+		{ID: "a", Name: "Pizza", Tags: models.Tags{"italian", "pizza"}},
+		{ID: "b", Name: "Pasta", Tags: models.Tags{"italian", "pasta"}},
+	}
+}
+
+func TestMemoryStore_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	if err := s.Create(ctx, &models.Recipe{ID: "x", Name: "Soup"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := s.Create(ctx, &models.Recipe{ID: "x"}); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("Create() duplicate error = %v, want ErrDuplicateID", err)
+	}
+
+	got, err := s.Get(ctx, "x")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Name != "Soup" {
+		t.Errorf("Get() name = %q, want %q", got.Name, "Soup")
+	}
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() missing error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStore_ListPagination(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2021, 1, 17, 19, 28, 52, 0, time.UTC)
+	s := NewMemoryStore(
+		models.Recipe{ID: "c", PublishedAt: base},
+		models.Recipe{ID: "a", PublishedAt: base.Add(time.Hour)},
+		models.Recipe{ID: "b", PublishedAt: base},
+	)
+
+	first, err := s.List(ctx, ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if ids(first.Recipes) != "b,c" || first.NextCursor == "" {
+		t.Fatalf("List() first page = %s cursor %q, want b,c with a cursor", ids(first.Recipes), first.NextCursor)
+	}
+
+	// A recipe written between page requests must not shift the next page.
+	s.Create(ctx, &models.Recipe{ID: "0", PublishedAt: base.Add(-time.Hour)})
+
+	second, err := s.List(ctx, ListOptions{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if ids(second.Recipes) != "a" || second.NextCursor != "" {
+		t.Errorf("List() second page = %s cursor %q, want a without cursor", ids(second.Recipes), second.NextCursor)
+	}
+
+	if _, err := s.List(ctx, ListOptions{Cursor: "%%%"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("List() bad cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func ids(recipes []models.Recipe) string {
+	out := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		out[i] = recipe.ID
+	}
+	return strings.Join(out, ",")
+}
+
+func TestMemoryStore_Update(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(seedRecipes()...)
+
+	updated, err := s.Update(ctx, "a", &models.Recipe{Name: "Calzone", Tags: models.Tags{"italian"}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.ID != "a" || updated.Name != "Calzone" {
+		t.Errorf("Update() = %+v, want id a named Calzone", updated)
+	}
+	if _, err := s.Update(ctx, "missing", &models.Recipe{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update() missing error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStore_Revisions(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(seedRecipes()...)
+
+	if got, _ := s.Get(ctx, "a"); got.Revision != 1 {
+		t.Errorf("Get() seeded revision = %d, want 1", got.Revision)
+	}
+	updated, err := s.Update(ctx, "a", &models.Recipe{Name: "Calzone", Revision: 1})
+	if err != nil || updated.Revision != 2 {
+		t.Fatalf("Update() at revision 1 = %+v, %v, want revision 2", updated, err)
+	}
+	if _, err := s.Update(ctx, "a", &models.Recipe{Name: "Stale", Revision: 1}); !errors.Is(err, ErrRevisionMismatch) {
+		t.Errorf("Update() stale error = %v, want ErrRevisionMismatch", err)
+	}
+	if updated, _ := s.Update(ctx, "a", &models.Recipe{Name: "Any"}); updated.Revision != 3 {
+		t.Errorf("Update() unconditional revision = %d, want 3", updated.Revision)
+	}
+
+	recipe := models.Recipe{ID: "a", Name: "Imported", Revision: 9}
+	if created, _ := s.Upsert(ctx, &recipe); created || recipe.Revision != 4 {
+		t.Errorf("Upsert() existing = %v, revision %d, want false and 4", created, recipe.Revision)
+	}
+	recipe = models.Recipe{ID: "new", Name: "New", Revision: 9}
+	if created, _ := s.Upsert(ctx, &recipe); !created || recipe.Revision != 1 {
+		t.Errorf("Upsert() new = %v, revision %d, want true and 1", created, recipe.Revision)
+	}
+}
+
+func TestMemoryStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(seedRecipes()...)
+
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrNotFound", err)
+	}
+	page, _ := s.List(ctx, ListOptions{})
+	if ids(page.Recipes) != "b" {
+		t.Errorf("List() after delete = %s, want only b", ids(page.Recipes))
+	}
+}
+
+func TestMemoryStore_Trash(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(seedRecipes()...)
+
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() deleted error = %v, want ErrNotFound", err)
+	}
+	if err := s.Create(ctx, &models.Recipe{ID: "a", Name: "Again"}); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("Create() deleted id error = %v, want ErrDuplicateID", err)
+	}
+	trash, _ := s.Trash(ctx)
+	if ids(trash) != "a" || trash[0].DeletedAt.IsZero() {
+		t.Fatalf("Trash() = %+v, want a with its deletion date", trash)
+	}
+
+	restored, err := s.Restore(ctx, "a")
+	if err != nil || !restored.DeletedAt.IsZero() {
+		t.Fatalf("Restore() = %+v, %v, want a without deletion date", restored, err)
+	}
+	if _, err := s.Restore(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore() twice error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Get(ctx, "a"); err != nil {
+		t.Errorf("Get() restored error = %v", err)
+	}
+
+	s.Delete(ctx, "a")
+	s.Delete(ctx, "b")
+	if purged, _ := s.Purge(ctx, time.Now().Add(-time.Hour)); len(purged) != 0 {
+		t.Errorf("Purge() recent = %v, want nothing", purged)
+	}
+	if purged, _ := s.Purge(ctx, time.Now().Add(time.Second)); strings.Join(purged, ",") != "a,b" {
+		t.Errorf("Purge() = %v, want a and b", purged)
+	}
+	if trash, _ := s.Trash(ctx); len(trash) != 0 {
+		t.Errorf("Trash() after purge = %s, want empty", ids(trash))
+	}
+	if _, err := s.Restore(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore() purged error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStore_UpsertDeleted(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(seedRecipes()...)
+	s.Delete(ctx, "a")
+
+	recipe := models.Recipe{ID: "a", Name: "Imported"}
+	if created, err := s.Upsert(ctx, &recipe); err != nil || created || recipe.Revision != 2 {
+		t.Errorf("Upsert() deleted = %v, %v, revision %d, want false and 2", created, err, recipe.Revision)
+	}
+	if trash, _ := s.Trash(ctx); len(trash) != 0 {
+		t.Errorf("Trash() after upsert = %s, want empty", ids(trash))
+	}
+	if got, err := s.Get(ctx, "a"); err != nil || got.Name != "Imported" {
+		t.Errorf("Get() = %+v, %v, want the imported recipe", got, err)
+	}
+}
+
+func TestMemoryStore_WithTransaction(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(seedRecipes()...)
+
+	failed := errors.New("failed")
+	err := s.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.Update(ctx, "a", &models.Recipe{Name: "Calzone"}); err != nil {
+			return err
+		}
+		if err := s.Delete(ctx, "b"); err != nil {
+			return err
+		}
+		if err := s.Create(ctx, &models.Recipe{ID: "c", Name: "Soup"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTransaction() error = %v, want failed", err)
+	}
+	page, _ := s.List(ctx, ListOptions{})
+	if ids(page.Recipes) != "a,b" || page.Recipes[0].Name != "Pizza" || page.Recipes[0].Revision != 1 {
+		t.Errorf("List() after rollback = %+v, want a and b unchanged", page.Recipes)
+	}
+
+	err = s.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.Update(ctx, "a", &models.Recipe{Name: "Calzone"})
+		return err
+	})
+	if got, _ := s.Get(ctx, "a"); err != nil || got.Name != "Calzone" {
+		t.Errorf("Get() after commit = %+v, %v, want Calzone", got, err)
+	}
+}
+
+func TestMemoryStore_Search(t *testing.T) {
+	s := NewMemoryStore(seedRecipes()...)
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected string
+	}{
+		{"Single tag", Filter{Tags: []string{"italian"}}, "a,b"},
+		{"All tags", Filter{Tags: []string{"italian", "pizza"}, MatchAllTags: true}, "a"},
+		{"Any tags", Filter{Tags: []string{"pizza", "pasta"}}, "a,b"},
+		{"Exclusion", Filter{Tags: []string{"italian"}, ExcludeTags: []string{"pizza"}}, "b"},
+		{"Only exclusion", Filter{ExcludeTags: []string{"pasta"}}, "a"},
+		{"No match", Filter{Tags: []string{"dessert"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipes, err := s.Search(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if ids(recipes) != tt.expected {
+				t.Errorf("Search() = %s, want %s", ids(recipes), tt.expected)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_IngredientSearch(t *testing.T) {
+	s := NewMemoryStore(
+		models.Recipe{ID: "a", Ingredients: []string{"2 chicken breasts", "1 lemon, juiced", "2 cloves garlic"}},
+		models.Recipe{ID: "b", Ingredients: []string{"1 whole chicken", "4 slices bacon"}},
+		models.Recipe{ID: "c", Ingredients: []string{"2 Lemons", "1 cup sugar"}},
+		models.Recipe{ID: "d", Ingredients: []string{"1 pound pork shoulder"}},
+	)
+
+	tests := []struct {
+		name     string
+		query    IngredientQuery
+		expected string
+		terms    string
+	}{
+		{"Overlap", NewIngredientQuery([]string{"chicken", "lemons"}, nil), "a,b,c", "chicken lemon|chicken|lemon"},
+		{"Plural term", NewIngredientQuery([]string{"Chicken Breasts"}, nil), "a", "chicken breast"},
+		{"Exclusion", NewIngredientQuery([]string{"chicken"}, []string{"bacon"}), "a", "chicken"},
+		{"No match", NewIngredientQuery([]string{"tofu"}, nil), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := s.IngredientSearch(context.Background(), tt.query, 0)
+			if err != nil {
+				t.Fatalf("IngredientSearch() error = %v", err)
+			}
+			var found, terms []string
+			for _, hit := range hits {
+				found = append(found, hit.Recipe.ID)
+				terms = append(terms, strings.Join(hit.Terms, " "))
+			}
+			if got := strings.Join(found, ","); got != tt.expected {
+				t.Errorf("IngredientSearch() = %s, want %s", got, tt.expected)
+			}
+			if got := strings.Join(terms, "|"); got != tt.terms {
+				t.Errorf("IngredientSearch() terms = %s, want %s", got, tt.terms)
+			}
+		})
+	}
+}
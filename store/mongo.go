@@ -0,0 +1,460 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoStore is a RecipeStore backed by a MongoDB collection. Recipes are
+// addressed by their application-level "id" field rather than "_id".
+// Deleted recipes stay in the collection with a deletedAt date until they
+// are purged.
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// live matches the recipes that are not in the trash; a null query also
+// matches documents without the field.
+var live = bson.E{Key: "deletedAt", Value: nil}
+
+// inTrash matches the recipes that are in the trash.
+var inTrash = bson.E{Key: "deletedAt", Value: bson.M{"$ne": nil}}
+
+// NewMongoStore returns a MongoStore operating on collection.
+func NewMongoStore(collection *mongo.Collection) *MongoStore {
+	return &MongoStore{collection: collection}
+}
+
+// Create implements RecipeStore.
+func (s *MongoStore) Create(ctx context.Context, recipe *models.Recipe) error {
+	recipe.Revision = 1
+	recipe.DeletedAt = time.Time{}
+	if _, err := s.collection.InsertOne(ctx, recipe); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateID
+		}
+		return err
+	}
+	return nil
+}
+
+// Get implements RecipeStore.
+func (s *MongoStore) Get(ctx context.Context, id string) (*models.Recipe, error) {
+	var recipe models.Recipe
+	err := s.collection.FindOne(ctx, bson.D{{Key: "id", Value: id}, live}).Decode(&recipe)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// EnsureIndexes creates the indexes the store relies on: a unique index on
+// the recipe id, the compound indexes backing the orderings of List, a
+// multikey index on tags, indexes on the cuisine and total time filters,
+// an index on the deletion date for the trash and the weighted text index
+// used by TextSearch.
+func (s *MongoStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "publishedAt", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "cuisine", Value: 1}}},
+		{Keys: bson.D{{Key: "totalMinutes", Value: 1}}},
+		{Keys: bson.D{{Key: "deletedAt", Value: 1}}},
+		{
+			Keys: bson.D{
+				{Key: "name", Value: "text"},
+				{Key: "ingredients", Value: "text"},
+				{Key: "instructions", Value: "text"},
+			},
+			Options: options.Index().SetWeights(bson.D{
+				{Key: "name", Value: fulltext.NameWeight},
+				{Key: "ingredients", Value: fulltext.IngredientsWeight},
+				{Key: "instructions", Value: fulltext.InstructionsWeight},
+			}),
+		},
+	})
+	return err
+}
+
+// List implements RecipeStore.
+func (s *MongoStore) List(ctx context.Context, opts ListOptions) (*Page, error) {
+	after, err := decodeCursor(opts)
+	if err != nil {
+		return nil, err
+	}
+	findOpts := options.Find().
+		SetSort(sortQuery(opts)).
+		SetLimit(int64(opts.EffectiveLimit() + 1))
+	recipes, err := s.find(ctx, listQuery(opts.Filter, after), findOpts)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(recipes, opts), nil
+}
+
+// TextSearch implements RecipeStore using the weighted text index created
+// by EnsureIndexes.
+func (s *MongoStore) TextSearch(ctx context.Context, query fulltext.Query, limit int) ([]ScoredRecipe, error) {
+	score := bson.M{"$meta": "textScore"}
+	findOpts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "id", Value: 1}}).
+		SetLimit(int64(ListOptions{Limit: limit}.EffectiveLimit()))
+	filter := bson.D{{Key: "$text", Value: bson.M{"$search": query.String()}}, live}
+	cur, err := s.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	results := make([]ScoredRecipe, 0)
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// IngredientSearch implements RecipeStore with an aggregation that scores
+// the recipes on the server; see ingredientPipeline.
+func (s *MongoStore) IngredientSearch(ctx context.Context, query IngredientQuery, limit int) ([]ScoredRecipe, error) {
+	cur, err := s.collection.Aggregate(ctx, ingredientPipeline(query, ListOptions{Limit: limit}.EffectiveLimit()))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var hits []struct {
+		Recipe  models.Recipe `bson:",inline"`
+		Score   float64       `bson:"score"`
+		Matched []string      `bson:"matched"`
+	}
+	if err := cur.All(ctx, &hits); err != nil {
+		return nil, err
+	}
+	results := make([]ScoredRecipe, len(hits))
+	for i, hit := range hits {
+		results[i] = ScoredRecipe{Recipe: hit.Recipe, Score: hit.Score, Terms: hit.Matched}
+	}
+	return results, nil
+}
+
+// Update implements RecipeStore.
+func (s *MongoStore) Update(ctx context.Context, id string, recipe *models.Recipe) (*models.Recipe, error) {
+	update := bson.D{{
+		Key: "$set", Value: bson.D{
+			{Key: "name", Value: recipe.Name},
+			{Key: "instructions", Value: recipe.Instructions},
+			{Key: "ingredients", Value: recipe.Ingredients},
+			{Key: "tags", Value: recipe.Tags},
+			{Key: "servings", Value: recipe.Servings},
+			{Key: "yield", Value: recipe.Yield},
+			{Key: "prepTime", Value: recipe.PrepTime},
+			{Key: "cookTime", Value: recipe.CookTime},
+			{Key: "totalTime", Value: recipe.TotalTime},
+			{Key: "totalMinutes", Value: recipe.TotalMinutes},
+			{Key: "difficulty", Value: recipe.Difficulty},
+			{Key: "cuisine", Value: recipe.Cuisine},
+			{Key: "author", Value: recipe.Author},
+			{Key: "sourceUrl", Value: recipe.SourceURL},
+			{Key: "attribution", Value: recipe.Attribution},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "revision", Value: 1}}},
+	}
+	filter := bson.D{{Key: "id", Value: id}, live}
+	if recipe.Revision != 0 {
+		filter = append(filter, bson.E{Key: "revision", Value: recipe.Revision})
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Recipe
+	err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if recipe.Revision != 0 {
+			if _, err := s.Get(ctx, id); err == nil {
+				return nil, ErrRevisionMismatch
+			}
+		}
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Upsert implements RecipeStore. The replacement is an update pipeline so
+// the stored revision can be incremented in the same operation; as it
+// replaces the whole document, a deleted recipe loses its deletedAt.
+func (s *MongoStore) Upsert(ctx context.Context, recipe *models.Recipe) (bool, error) {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.Before).
+		SetProjection(bson.D{{Key: "revision", Value: 1}})
+	var previous struct {
+		Revision int `bson:"revision"`
+	}
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"id": recipe.ID}, upsertPipeline(recipe), opts).Decode(&previous)
+	created := errors.Is(err, mongo.ErrNoDocuments)
+	if err != nil && !created {
+		return false, err
+	}
+	recipe.Revision = previous.Revision + 1
+	return created, nil
+}
+
+// upsertPipeline replaces a document with recipe, keeping its _id and
+// incrementing its revision. The recipe is wrapped in $literal so that
+// strings starting with "$" are not taken for field paths.
+func upsertPipeline(recipe *models.Recipe) mongo.Pipeline {
+	return mongo.Pipeline{{{Key: "$replaceWith", Value: bson.M{"$mergeObjects": bson.A{
+		bson.M{"$literal": recipe},
+		bson.D{
+			{Key: "_id", Value: "$_id"},
+			{Key: "revision", Value: bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$revision", 0}}, 1}}},
+		},
+	}}}}}
+}
+
+// Delete implements RecipeStore.
+func (s *MongoStore) Delete(ctx context.Context, id string) error {
+	update := bson.M{"$set": bson.M{"deletedAt": time.Now()}}
+	result, err := s.collection.UpdateOne(ctx, bson.D{{Key: "id", Value: id}, live}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Trash implements RecipeStore.
+func (s *MongoStore) Trash(ctx context.Context) ([]models.Recipe, error) {
+	findOpts := options.Find().SetSort(bson.D{{Key: "deletedAt", Value: -1}, {Key: "id", Value: 1}})
+	return s.find(ctx, bson.D{inTrash}, findOpts)
+}
+
+// Restore implements RecipeStore.
+func (s *MongoStore) Restore(ctx context.Context, id string) (*models.Recipe, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	update := bson.M{"$unset": bson.M{"deletedAt": ""}}
+	var recipe models.Recipe
+	err := s.collection.FindOneAndUpdate(ctx, bson.D{{Key: "id", Value: id}, inTrash}, update, opts).Decode(&recipe)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// Purge implements RecipeStore. The ids are read first so they can be
+// returned; the delete repeats the date condition in case a recipe was
+// restored in between.
+func (s *MongoStore) Purge(ctx context.Context, cutoff time.Time) ([]string, error) {
+	expired := bson.D{{Key: "deletedAt", Value: bson.M{"$lt": cutoff}}}
+	findOpts := options.Find().
+		SetProjection(bson.D{{Key: "id", Value: 1}}).
+		SetSort(bson.D{{Key: "id", Value: 1}})
+	recipes, err := s.find(ctx, expired, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	filter := append(bson.D{{Key: "id", Value: bson.M{"$in": ids}}}, expired...)
+	if _, err := s.collection.DeleteMany(ctx, filter); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// WithTransaction implements Transactor with a session of the client of
+// the collection. Transactions need a replica set or a sharded cluster;
+// on a standalone server it fails with ErrNoTransactions.
+func (s *MongoStore) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := s.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		return nil, fn(ctx)
+	})
+	if isNoTransactionsError(err) {
+		return fmt.Errorf("%w: %v", ErrNoTransactions, err)
+	}
+	return err
+}
+
+// isNoTransactionsError reports whether err is a standalone server
+// refusing to run a write in a transaction.
+func isNoTransactionsError(err error) bool {
+	var se mongo.ServerError
+	return errors.As(err, &se) &&
+		se.HasErrorCodeWithMessage(illegalOperation, "Transaction numbers are only allowed on a replica set member or mongos")
+}
+
+// illegalOperation is the code of the server error isNoTransactionsError
+// looks for.
+const illegalOperation = 20
+
+// Search implements RecipeStore.
+func (s *MongoStore) Search(ctx context.Context, filter Filter) ([]models.Recipe, error) {
+	findOpts := options.Find().SetSort(sortQuery(ListOptions{}))
+	return s.find(ctx, listQuery(filter, nil), findOpts)
+}
+
+// find runs filter against the collection and decodes every match.
+func (s *MongoStore) find(ctx context.Context, filter any, opts ...options.Lister[options.FindOptions]) ([]models.Recipe, error) {
+	cur, err := s.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	recipes := make([]models.Recipe, 0)
+	if err := cur.All(ctx, &recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// listQuery translates a Filter and an optional cursor position into a
+// MongoDB query document matching recipes that are not in the trash.
+func listQuery(f Filter, after *cursor) bson.D {
+	and := bson.A{bson.D{live}}
+	if len(f.Tags) > 0 {
+		op := "$in"
+		if f.MatchAllTags {
+			op = "$all"
+		}
+		and = append(and, bson.M{"tags": bson.M{op: f.Tags}})
+	}
+	if len(f.ExcludeTags) > 0 {
+		and = append(and, bson.M{"tags": bson.M{"$nin": f.ExcludeTags}})
+	}
+	if f.Name != "" {
+		and = append(and, bson.M{"name": containsRegex(f.Name)})
+	}
+	if f.Ingredient != "" {
+		and = append(and, bson.M{"ingredients": containsRegex(f.Ingredient)})
+	}
+	if !f.PublishedFrom.IsZero() || !f.PublishedTo.IsZero() {
+		published := bson.M{}
+		if !f.PublishedFrom.IsZero() {
+			published["$gte"] = f.PublishedFrom
+		}
+		if !f.PublishedTo.IsZero() {
+			published["$lte"] = f.PublishedTo
+		}
+		and = append(and, bson.M{"publishedAt": published})
+	}
+	if f.Difficulty != "" {
+		and = append(and, bson.M{"difficulty": f.Difficulty})
+	}
+	if f.Cuisine != "" {
+		and = append(and, bson.M{"cuisine": f.Cuisine})
+	}
+	if f.Author != "" {
+		and = append(and, bson.M{"author": containsRegex(f.Author)})
+	}
+	if f.MaxTotalTime > 0 {
+		and = append(and, bson.M{"totalMinutes": bson.D{{Key: "$gt", Value: 0}, {Key: "$lte", Value: maxMinutes(f.MaxTotalTime)}}})
+	}
+	if after != nil {
+		and = append(and, cursorQuery(after))
+	}
+	return bson.D{{Key: "$and", Value: and}}
+}
+
+// ingredientPipeline builds the aggregation behind IngredientSearch. It
+// first narrows the collection to recipes with an ingredient line matching
+// an included term and none matching an excluded one, leaving out the
+// trash, then collects the
+// matched terms of each recipe, scores it and sorts like the memory store.
+func ingredientPipeline(q IngredientQuery, limit int) mongo.Pipeline {
+	include := make(bson.A, len(q.Include))
+	matched := make(bson.A, len(q.Include))
+	for i, term := range q.Include {
+		pattern := termRegex(term)
+		include[i] = bson.Regex{Pattern: pattern, Options: "i"}
+		matched[i] = bson.M{"$cond": bson.A{
+			bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$ingredients", bson.A{}}},
+				"as":    "line",
+				"in":    bson.M{"$regexMatch": bson.M{"input": "$$line", "regex": pattern, "options": "i"}},
+			}}}},
+			bson.A{term},
+			bson.A{},
+		}}
+	}
+	match := bson.D{{Key: "ingredients", Value: bson.M{"$in": include}}}
+	if len(q.Exclude) > 0 {
+		exclude := make(bson.A, len(q.Exclude))
+		for i, term := range q.Exclude {
+			exclude[i] = bson.Regex{Pattern: termRegex(term), Options: "i"}
+		}
+		match = bson.D{{Key: "$and", Value: bson.A{
+			bson.D{match[0]},
+			bson.M{"ingredients": bson.M{"$nin": exclude}},
+		}}}
+	}
+	match = append(match, live)
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"matched": bson.M{"$concatArrays": matched}}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$divide": bson.A{bson.M{"$size": "$matched"}, len(q.Include)}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+}
+
+// cursorQuery matches the documents that sort strictly after c.
+func cursorQuery(c *cursor) bson.M {
+	op := "$gt"
+	if c.Descending {
+		op = "$lt"
+	}
+	field, value := string(SortByPublishedAt), any(c.PublishedAt)
+	if c.Sort == SortByName {
+		field, value = string(SortByName), c.Name
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.D{{Key: field, Value: value}, {Key: "id", Value: bson.M{op: c.ID}}},
+	}}
+}
+
+// sortQuery returns the sort document matching ListOptions.compare.
+func sortQuery(opts ListOptions) bson.D {
+	direction := 1
+	if opts.Descending {
+		direction = -1
+	}
+	return bson.D{
+		{Key: string(opts.sort()), Value: direction},
+		{Key: "id", Value: direction},
+	}
+}
+
+// containsRegex matches values containing substr, ignoring case.
+func containsRegex(substr string) bson.Regex {
+	return bson.Regex{Pattern: regexp.QuoteMeta(substr), Options: "i"}
+}
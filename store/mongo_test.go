@@ -0,0 +1,116 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestListQuery(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		after    *cursor
+		expected string
+	}{
+		{
+			name:     "Empty filter",
+			expected: `{"$and":[{"deletedAt":null}]}`,
+		},
+		{
+			name:     "Any tags",
+			filter:   Filter{Tags: []string{"italian", "pizza"}},
+			expected: `{"$and":[{"deletedAt":null},{"tags":{"$in":["italian","pizza"]}}]}`,
+		},
+		{
+			name:     "All tags and name",
+			filter:   Filter{Tags: []string{"italian"}, MatchAllTags: true, Name: "pi.za"},
+			expected: `{"$and":[{"deletedAt":null},{"tags":{"$all":["italian"]}},{"name":{"$regularExpression":{"pattern":"pi\\.za","options":"i"}}}]}`,
+		},
+		{
+			name:     "Ingredient and published range",
+			filter:   Filter{Ingredient: "flour", PublishedFrom: from},
+			expected: `{"$and":[{"deletedAt":null},{"ingredients":{"$regularExpression":{"pattern":"flour","options":"i"}}},{"publishedAt":{"$gte":{"$date":{"$numberLong":"1609459200000"}}}}]}`,
+		},
+		{
+			name:     "Metadata",
+			filter:   Filter{Difficulty: models.Easy, Cuisine: "thai", MaxTotalTime: 90 * time.Minute},
+			expected: `{"$and":[{"deletedAt":null},{"difficulty":"easy"},{"cuisine":"thai"},{"totalMinutes":{"$gt":{"$numberInt":"0"},"$lte":{"$numberInt":"90"}}}]}`,
+		},
+		{
+			name:     "Descending name cursor",
+			after:    &cursor{Sort: SortByName, Descending: true, Name: "Pizza", ID: "abc"},
+			expected: `{"$and":[{"deletedAt":null},{"$or":[{"name":{"$lt":"Pizza"}},{"name":"Pizza","id":{"$lt":"abc"}}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.MarshalExtJSON(listQuery(tt.filter, tt.after), true, false)
+			if err != nil {
+				t.Fatalf("MarshalExtJSON() error = %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("listQuery() = %s, expected %s", data, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIngredientPipeline(t *testing.T) {
+	q := NewIngredientQuery([]string{"chicken breasts"}, []string{"pork"})
+	data, err := bson.MarshalExtJSON(ingredientPipeline(q, 10)[0], true, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON() error = %v", err)
+	}
+	expected := `{"$match":{"$and":[{"ingredients":{"$in":[{"$regularExpression":{"pattern":"(?=.*\\b(?:chicken|chickens)\\b)(?=.*\\b(?:breast|breasts)\\b)","options":"i"}}]}},` +
+		`{"ingredients":{"$nin":[{"$regularExpression":{"pattern":"(?=.*\\b(?:pork|porks)\\b)","options":"i"}}]}}],"deletedAt":null}}`
+	if string(data) != expected {
+		t.Errorf("ingredientPipeline() $match = %s, expected %s", data, expected)
+	}
+}
+
+func TestUpsertPipeline(t *testing.T) {
+	got, err := bson.MarshalExtJSON(bson.D{{Key: "p", Value: upsertPipeline(&models.Recipe{ID: "a", Name: "$pizza"})}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`{"$replaceWith":{"$mergeObjects":[{"$literal":{"id":"a","name":"$pizza",`,
+		`{"_id":"$_id","revision":{"$add":[{"$ifNull":["$revision",0]},1]}}]}}`,
+	} {
+		if !strings.Contains(string(got), expected) {
+			t.Errorf("upsertPipeline() = %s, expected it to contain %s", got, expected)
+		}
+	}
+}
+
+func TestIsNoTransactionsError(t *testing.T) {
+	standalone := mongo.CommandError{Code: 20, Name: "IllegalOperation", Message: "Transaction numbers are only allowed on a replica set member or mongos"}
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"Standalone server", standalone, true},
+		{"Wrapped", fmt.Errorf("create: %w", standalone), true},
+		{"Other illegal operation", mongo.CommandError{Code: 20, Message: "cannot drop a view"}, false},
+		{"Other error", errors.New("connection refused"), false},
+		{"No error", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNoTransactionsError(tt.err); got != tt.expected {
+				t.Errorf("isNoTransactionsError(%v) = %v, expected %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
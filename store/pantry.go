@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// ErrPantryNotFound is returned when the owner has no pantry.
+var ErrPantryNotFound = errors.New("pantry not found")
+
+// PantryStore is the storage contract for pantries, one per owner.
+type PantryStore interface {
+	// Get returns the pantry of owner or ErrPantryNotFound.
+	Get(ctx context.Context, owner string) (*models.Pantry, error)
+	// Put stores pantry as a whole under its owner, replacing any
+	// existing pantry, and reports whether it was newly created.
+	Put(ctx context.Context, pantry *models.Pantry) (bool, error)
+	// Delete removes the pantry of owner or returns ErrPantryNotFound.
+	Delete(ctx context.Context, owner string) error
+}
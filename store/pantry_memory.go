@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// MemoryPantryStore is a PantryStore backed by a map guarded by a mutex.
+type MemoryPantryStore struct {
+	mu       sync.RWMutex
+	pantries map[string]*models.Pantry
+}
+
+// NewMemoryPantryStore returns an empty MemoryPantryStore.
+func NewMemoryPantryStore() *MemoryPantryStore {
+	return &MemoryPantryStore{pantries: make(map[string]*models.Pantry)}
+}
+
+// Get implements PantryStore.
+func (s *MemoryPantryStore) Get(_ context.Context, owner string) (*models.Pantry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pantry, ok := s.pantries[owner]
+	if !ok {
+		return nil, ErrPantryNotFound
+	}
+	return clonePantry(pantry), nil
+}
+
+// Put implements PantryStore.
+func (s *MemoryPantryStore) Put(_ context.Context, pantry *models.Pantry) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.pantries[pantry.Owner]
+	s.pantries[pantry.Owner] = clonePantry(pantry)
+	return !exists, nil
+}
+
+// Delete implements PantryStore.
+func (s *MemoryPantryStore) Delete(_ context.Context, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pantries[owner]; !ok {
+		return ErrPantryNotFound
+	}
+	delete(s.pantries, owner)
+	return nil
+}
+
+// clonePantry copies pantry so callers never share its lists with the
+// store.
+func clonePantry(pantry *models.Pantry) *models.Pantry {
+	cloned := *pantry
+	cloned.Items = slices.Clone(pantry.Items)
+	cloned.Exclude = slices.Clone(pantry.Exclude)
+	return &cloned
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoPantryStore is a PantryStore backed by a MongoDB collection holding
+// one document per owner.
+type MongoPantryStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoPantryStore returns a MongoPantryStore operating on collection.
+func NewMongoPantryStore(collection *mongo.Collection) *MongoPantryStore {
+	return &MongoPantryStore{collection: collection}
+}
+
+// EnsureIndexes creates a unique index on the pantry owner.
+func (s *MongoPantryStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "owner", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Get implements PantryStore.
+func (s *MongoPantryStore) Get(ctx context.Context, owner string) (*models.Pantry, error) {
+	var pantry models.Pantry
+	err := s.collection.FindOne(ctx, bson.M{"owner": owner}).Decode(&pantry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrPantryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pantry, nil
+}
+
+// Put implements PantryStore.
+func (s *MongoPantryStore) Put(ctx context.Context, pantry *models.Pantry) (bool, error) {
+	opts := options.Replace().SetUpsert(true)
+	result, err := s.collection.ReplaceOne(ctx, bson.M{"owner": pantry.Owner}, pantry, opts)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// Delete implements PantryStore.
+func (s *MongoPantryStore) Delete(ctx context.Context, owner string) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"owner": owner})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrPantryNotFound
+	}
+	return nil
+}
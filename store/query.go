@@ -0,0 +1,175 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// DefaultListLimit is the page size used when ListOptions.Limit is zero.
+const DefaultListLimit = 100
+
+// MaxListLimit caps the page size a client may request.
+const MaxListLimit = 1000
+
+// SortField names the recipe field a listing is ordered by. Ties are
+// always broken by id so the ordering is total.
+type SortField string
+
+const (
+	SortByPublishedAt SortField = "publishedAt"
+	SortByName        SortField = "name"
+)
+
+// Filter restricts the recipes returned by List. Zero values disable the
+// corresponding criterion.
+type Filter struct {
+	// Tags selects recipes carrying any of the tags, or all of them when
+	// MatchAllTags is set.
+	Tags         []string
+	MatchAllTags bool
+	// ExcludeTags rejects recipes carrying any of the tags.
+	ExcludeTags []string
+	// Name selects recipes whose name contains the substring, ignoring case.
+	Name string
+	// Ingredient selects recipes with at least one ingredient containing
+	// the substring, ignoring case.
+	Ingredient string
+	// PublishedFrom and PublishedTo bound publishedAt, both inclusive.
+	PublishedFrom time.Time
+	PublishedTo   time.Time
+	// Difficulty selects recipes of the given difficulty.
+	Difficulty models.Difficulty
+	// Cuisine selects recipes of the given lower-case cuisine.
+	Cuisine string
+	// Author selects recipes whose author contains the substring, ignoring
+	// case.
+	Author string
+	// MaxTotalTime selects recipes with a totalTime of at most this long.
+	// Recipes without a totalTime never match.
+	MaxTotalTime time.Duration
+}
+
+// ListOptions controls which page of recipes List returns.
+type ListOptions struct {
+	Filter Filter
+	// Sort is the field to order by, SortByPublishedAt when empty.
+	Sort       SortField
+	Descending bool
+	// Limit is the maximum number of recipes in the page. Zero selects
+	// DefaultListLimit and larger values are capped at MaxListLimit.
+	Limit int
+	// Cursor is the opaque NextCursor of a previous page, or empty for the
+	// first page. It is only valid with the same Sort and Descending.
+	Cursor string
+}
+
+// Page is one page of a recipe listing. NextCursor is empty on the last
+// page.
+type Page struct {
+	Recipes    []models.Recipe
+	NextCursor string
+}
+
+// EffectiveLimit returns the page size after applying the defaults and
+// the cap.
+func (o ListOptions) EffectiveLimit() int {
+	switch {
+	case o.Limit <= 0:
+		return DefaultListLimit
+	case o.Limit > MaxListLimit:
+		return MaxListLimit
+	}
+	return o.Limit
+}
+
+// sort returns the effective sort field.
+func (o ListOptions) sort() SortField {
+	if o.Sort == "" {
+		return SortByPublishedAt
+	}
+	return o.Sort
+}
+
+// compare orders a and b according to the options, breaking ties by id.
+func (o ListOptions) compare(a, b models.Recipe) int {
+	var c int
+	switch o.sort() {
+	case SortByName:
+		c = strings.Compare(a.Name, b.Name)
+	default:
+		c = a.PublishedAt.Compare(b.PublishedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if o.Descending {
+		return -c
+	}
+	return c
+}
+
+// Matches reports whether recipe satisfies every criterion of f.
+func (f Filter) Matches(recipe models.Recipe) bool {
+	if len(f.Tags) > 0 {
+		matched := 0
+		for _, tag := range f.Tags {
+			if recipe.Tags.Contains(tag) {
+				matched++
+			}
+		}
+		if matched == 0 || (f.MatchAllTags && matched < len(f.Tags)) {
+			return false
+		}
+	}
+	for _, tag := range f.ExcludeTags {
+		if recipe.Tags.Contains(tag) {
+			return false
+		}
+	}
+	if f.Name != "" && !containsFold(recipe.Name, f.Name) {
+		return false
+	}
+	if f.Ingredient != "" && !anyContainsFold(recipe.Ingredients, f.Ingredient) {
+		return false
+	}
+	if !f.PublishedFrom.IsZero() && recipe.PublishedAt.Before(f.PublishedFrom) {
+		return false
+	}
+	if !f.PublishedTo.IsZero() && recipe.PublishedAt.After(f.PublishedTo) {
+		return false
+	}
+	if f.Difficulty != "" && recipe.Difficulty != f.Difficulty {
+		return false
+	}
+	if f.Cuisine != "" && recipe.Cuisine != f.Cuisine {
+		return false
+	}
+	if f.Author != "" && !containsFold(recipe.Author, f.Author) {
+		return false
+	}
+	if f.MaxTotalTime > 0 && (recipe.TotalMinutes == 0 || recipe.TotalMinutes > maxMinutes(f.MaxTotalTime)) {
+		return false
+	}
+	return true
+}
+
+// maxMinutes converts a MaxTotalTime to the whole minutes stored in
+// Recipe.TotalMinutes, rounding down.
+func maxMinutes(d time.Duration) int {
+	return int(d / time.Minute)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func anyContainsFold(values []string, substr string) bool {
+	for _, value := range values {
+		if containsFold(value, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	published := time.Date(2021, 1, 17, 19, 28, 52, 0, time.UTC)
+	recipe := models.Recipe{
+		Name:        "Homemade Pizza",
+		Tags:        models.Tags{"italian", "pizza", "dinner"},
+		Ingredients: models.Ingredients{"3 3/4 cups (490 g) bread flour", "firm Mozzarella cheese, grated"},
+		PublishedAt: published,
+		Difficulty:  models.Easy,
+		Cuisine:     "italian",
+		Author:      "Maria Rojas",
+		TotalTime:   "PT1H",
+	}
+	recipe.Normalize()
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{"Empty filter", Filter{}, true},
+		{"Any tag matches", Filter{Tags: []string{"dessert", "pizza"}}, true},
+		{"No tag matches", Filter{Tags: []string{"dessert"}}, false},
+		{"All tags match", Filter{Tags: []string{"italian", "pizza"}, MatchAllTags: true}, true},
+		{"Not all tags match", Filter{Tags: []string{"italian", "dessert"}, MatchAllTags: true}, false},
+		{"Name substring ignores case", Filter{Name: "PIZZA"}, true},
+		{"Name substring missing", Filter{Name: "pasta"}, false},
+		{"Ingredient substring ignores case", Filter{Ingredient: "mozzarella"}, true},
+		{"Ingredient substring missing", Filter{Ingredient: "basil"}, false},
+		{"Inside published range", Filter{PublishedFrom: published, PublishedTo: published}, true},
+		{"Before published range", Filter{PublishedFrom: published.Add(time.Second)}, false},
+		{"After published range", Filter{PublishedTo: published.Add(-time.Second)}, false},
+		{"Difficulty", Filter{Difficulty: models.Easy}, true},
+		{"Other difficulty", Filter{Difficulty: models.Hard}, false},
+		{"Cuisine", Filter{Cuisine: "italian"}, true},
+		{"Other cuisine", Filter{Cuisine: "mexican"}, false},
+		{"Author substring ignores case", Filter{Author: "rojas"}, true},
+		{"Within total time", Filter{MaxTotalTime: time.Hour}, true},
+		{"Over total time", Filter{MaxTotalTime: 59 * time.Minute}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(recipe); got != tt.expected {
+				t.Errorf("Filter.Matches() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_ListSortedByNameDescending(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(
+		models.Recipe{ID: "1", Name: "Brownies", Tags: models.Tags{"dessert"}},
+		models.Recipe{ID: "2", Name: "Apple Pie", Tags: models.Tags{"dessert"}},
+		models.Recipe{ID: "3", Name: "Carbonara", Tags: models.Tags{"pasta"}},
+		models.Recipe{ID: "4", Name: "Crumble", Tags: models.Tags{"dessert"}},
+	)
+	opts := ListOptions{
+		Filter:     Filter{Tags: []string{"dessert"}},
+		Sort:       SortByName,
+		Descending: true,
+		Limit:      2,
+	}
+
+	first, err := s.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if ids(first.Recipes) != "4,1" {
+		t.Fatalf("List() first page = %s, want 4,1", ids(first.Recipes))
+	}
+
+	opts.Cursor = first.NextCursor
+	second, err := s.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if ids(second.Recipes) != "2" || second.NextCursor != "" {
+		t.Errorf("List() second page = %s cursor %q, want 2 without cursor", ids(second.Recipes), second.NextCursor)
+	}
+
+	opts.Descending = false
+	if _, err := s.List(ctx, opts); err != ErrInvalidCursor {
+		t.Errorf("List() with cursor of another ordering error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// ErrRevisionNotFound is returned when a recipe has no revision with the
+// requested number.
+var ErrRevisionNotFound = errors.New("recipe revision not found")
+
+// ErrDuplicateRevision is returned by Add when the revision was already
+// recorded. Revisions are immutable, so it is never replaced.
+var ErrDuplicateRevision = errors.New("recipe revision already recorded")
+
+// RevisionStore is the storage contract for the revision history of
+// recipes.
+type RevisionStore interface {
+	// Add records a revision, which must carry its recipe.
+	Add(ctx context.Context, revision *models.RecipeRevision) error
+	// List returns the revisions of a recipe, newest first, without their
+	// recipe. A recipe without history has none.
+	List(ctx context.Context, recipeID string) ([]models.RecipeRevision, error)
+	// Get returns one revision of a recipe or ErrRevisionNotFound.
+	Get(ctx context.Context, recipeID string, revision int) (*models.RecipeRevision, error)
+	// Delete removes the whole history of a recipe, once the recipe itself
+	// is purged, and returns the number of revisions removed.
+	Delete(ctx context.Context, recipeID string) (int, error)
+}
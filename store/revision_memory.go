@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// MemoryRevisionStore is a RevisionStore backed by a map of revisions per
+// recipe guarded by a mutex.
+type MemoryRevisionStore struct {
+	mu        sync.RWMutex
+	revisions map[string][]models.RecipeRevision
+}
+
+// NewMemoryRevisionStore returns an empty MemoryRevisionStore.
+func NewMemoryRevisionStore() *MemoryRevisionStore {
+	return &MemoryRevisionStore{revisions: make(map[string][]models.RecipeRevision)}
+}
+
+// Add implements RevisionStore.
+func (s *MemoryRevisionStore) Add(_ context.Context, revision *models.RecipeRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := s.revisions[revision.RecipeID]
+	i, found := slices.BinarySearchFunc(history, revision.Revision, func(r models.RecipeRevision, n int) int {
+		return r.Revision - n
+	})
+	if found {
+		return ErrDuplicateRevision
+	}
+	stored := *revision
+	stored.Recipe = cloneRecipe(revision.Recipe)
+	s.revisions[revision.RecipeID] = slices.Insert(history, i, stored)
+	return nil
+}
+
+// List implements RevisionStore.
+func (s *MemoryRevisionStore) List(_ context.Context, recipeID string) ([]models.RecipeRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	history := s.revisions[recipeID]
+	list := make([]models.RecipeRevision, len(history))
+	for i, revision := range history {
+		revision.Recipe = nil
+		list[len(history)-1-i] = revision
+	}
+	return list, nil
+}
+
+// Get implements RevisionStore.
+func (s *MemoryRevisionStore) Get(_ context.Context, recipeID string, revision int) (*models.RecipeRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, stored := range s.revisions[recipeID] {
+		if stored.Revision == revision {
+			stored.Recipe = cloneRecipe(stored.Recipe)
+			return &stored, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+// Delete implements RevisionStore.
+func (s *MemoryRevisionStore) Delete(_ context.Context, recipeID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := len(s.revisions[recipeID])
+	delete(s.revisions, recipeID)
+	return removed, nil
+}
+
+// cloneRecipe copies recipe so the history never shares its lists with
+// callers.
+func cloneRecipe(recipe *models.Recipe) *models.Recipe {
+	if recipe == nil {
+		return nil
+	}
+	cloned := *recipe
+	cloned.Tags = slices.Clone(recipe.Tags)
+	cloned.Ingredients = slices.Clone(recipe.Ingredients)
+	cloned.Instructions = slices.Clone(recipe.Instructions)
+	return &cloned
+}
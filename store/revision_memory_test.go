@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+func TestMemoryRevisionStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryRevisionStore()
+	recipe := models.Recipe{ID: "a", Name: "Pizza", Ingredients: models.Ingredients{"dough"}}
+	for _, n := range []int{2, 1, 3} {
+		recipe.Revision = n
+		if err := s.Add(ctx, &models.RecipeRevision{RecipeID: "a", Revision: n, Recipe: &recipe}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := s.Add(ctx, &models.RecipeRevision{RecipeID: "a", Revision: 2, Recipe: &recipe}); !errors.Is(err, ErrDuplicateRevision) {
+		t.Errorf("Add() duplicate error = %v, want ErrDuplicateRevision", err)
+	}
+	recipe.Ingredients[0] = "changed"
+
+	listed, err := s.List(ctx, "a")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listed) != 3 || listed[0].Revision != 3 || listed[2].Revision != 1 || listed[0].Recipe != nil {
+		t.Errorf("List() = %+v, want revisions 3 to 1 without recipes", listed)
+	}
+	if listed, _ := s.List(ctx, "missing"); len(listed) != 0 {
+		t.Errorf("List() missing = %+v, want none", listed)
+	}
+
+	got, err := s.Get(ctx, "a", 2)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Recipe.Revision != 2 || got.Recipe.Ingredients[0] != "dough" {
+		t.Errorf("Get() recipe = %+v, want revision 2 as added", got.Recipe)
+	}
+	got.Recipe.Name = "Changed"
+	if again, _ := s.Get(ctx, "a", 2); again.Recipe.Name != "Pizza" {
+		t.Errorf("Get() after changing a result = %q, want Pizza", again.Recipe.Name)
+	}
+	if _, err := s.Get(ctx, "a", 4); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("Get() missing error = %v, want ErrRevisionNotFound", err)
+	}
+
+	if removed, err := s.Delete(ctx, "a"); err != nil || removed != 3 {
+		t.Errorf("Delete() = %d, %v, want 3", removed, err)
+	}
+	if listed, _ := s.List(ctx, "a"); len(listed) != 0 {
+		t.Errorf("List() after Delete() = %+v, want none", listed)
+	}
+}
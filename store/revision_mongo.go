@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoRevisionStore is a RevisionStore backed by a MongoDB collection
+// holding one document per revision.
+type MongoRevisionStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRevisionStore returns a MongoRevisionStore operating on
+// collection.
+func NewMongoRevisionStore(collection *mongo.Collection) *MongoRevisionStore {
+	return &MongoRevisionStore{collection: collection}
+}
+
+// EnsureIndexes creates the unique index on recipe id and revision that
+// keeps a revision from being recorded twice.
+func (s *MongoRevisionStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "recipeId", Value: 1}, {Key: "revision", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Add implements RevisionStore.
+func (s *MongoRevisionStore) Add(ctx context.Context, revision *models.RecipeRevision) error {
+	_, err := s.collection.InsertOne(ctx, revision)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateRevision
+	}
+	return err
+}
+
+// List implements RevisionStore.
+func (s *MongoRevisionStore) List(ctx context.Context, recipeID string) ([]models.RecipeRevision, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetProjection(bson.D{{Key: "recipe", Value: 0}})
+	cur, err := s.collection.Find(ctx, bson.M{"recipeId": recipeID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	revisions := make([]models.RecipeRevision, 0)
+	if err := cur.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Get implements RevisionStore.
+func (s *MongoRevisionStore) Get(ctx context.Context, recipeID string, revision int) (*models.RecipeRevision, error) {
+	var found models.RecipeRevision
+	err := s.collection.FindOne(ctx, bson.M{"recipeId": recipeID, "revision": revision}).Decode(&found)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+// Delete implements RevisionStore.
+func (s *MongoRevisionStore) Delete(ctx context.Context, recipeID string) (int, error) {
+	result, err := s.collection.DeleteMany(ctx, bson.M{"recipeId": recipeID})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
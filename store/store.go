@@ -0,0 +1,97 @@
+// Package store defines the persistence layer used by the recipes API.
+//
+// Handlers never talk to a database directly; they depend on the
+// RecipeStore interface, which is implemented by a MongoDB backend for
+// production and an in-memory backend for tests and embedded use.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mrojasb2000/GinRecipes/fulltext"
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// ErrNotFound is returned when no recipe matches the requested id.
+var ErrNotFound = errors.New("recipe not found")
+
+// ErrDuplicateID is returned by Create when a recipe with the same id
+// already exists, even in the trash.
+var ErrDuplicateID = errors.New("recipe id already exists")
+
+// ErrRevisionMismatch is returned by Update when the stored recipe is not
+// at the revision the caller expected.
+var ErrRevisionMismatch = errors.New("recipe revision does not match")
+
+// ErrNoTransactions is returned by WithTransaction when the store behind
+// a wrapper cannot run transactions.
+var ErrNoTransactions = errors.New("recipe store does not support transactions")
+
+// RecipeStore is the storage contract for recipes. Deleted recipes are
+// kept in a trash, where only Trash, Restore, Purge and Upsert see them.
+type RecipeStore interface {
+	// Create persists a new recipe at revision 1, which it also sets on
+	// recipe, outside the trash. The recipe must already carry its id.
+	Create(ctx context.Context, recipe *models.Recipe) error
+	// Get returns the recipe with the given id or ErrNotFound.
+	Get(ctx context.Context, id string) (*models.Recipe, error)
+	// List returns one page of recipes ordered by publishedAt and then id,
+	// so clients can page through with NextCursor while writes happen.
+	// A malformed cursor yields ErrInvalidCursor.
+	List(ctx context.Context, opts ListOptions) (*Page, error)
+	// Update replaces everything but the id and publishedAt of the recipe
+	// with the given id, increments its revision and returns the stored
+	// result. When recipe.Revision is not zero the update only happens if
+	// the stored recipe is at that revision, and fails with
+	// ErrRevisionMismatch otherwise.
+	Update(ctx context.Context, id string, recipe *models.Recipe) (*models.Recipe, error)
+	// Upsert stores recipe as a whole under its id, replacing any existing
+	// recipe, and reports whether it was newly created. The revision given
+	// is ignored: the stored one is incremented and set on recipe. A
+	// recipe in the trash is replaced and so taken out of it.
+	Upsert(ctx context.Context, recipe *models.Recipe) (bool, error)
+	// Delete moves the recipe with the given id to the trash, setting its
+	// DeletedAt, or returns ErrNotFound.
+	Delete(ctx context.Context, id string) error
+	// Trash returns the recipes in the trash, most recently deleted first.
+	Trash(ctx context.Context) ([]models.Recipe, error)
+	// Restore takes the recipe with the given id out of the trash and
+	// returns it, or returns ErrNotFound when it is not there.
+	Restore(ctx context.Context, id string) (*models.Recipe, error)
+	// Purge permanently removes the recipes deleted before cutoff and
+	// returns their ids.
+	Purge(ctx context.Context, cutoff time.Time) ([]string, error)
+	// Search returns every recipe matching filter, unpaginated, in the
+	// default List ordering.
+	Search(ctx context.Context, filter Filter) ([]models.Recipe, error)
+	// TextSearch returns up to limit recipes matching a full-text query,
+	// most relevant first. Limit follows the ListOptions.Limit rules.
+	TextSearch(ctx context.Context, query fulltext.Query, limit int) ([]ScoredRecipe, error)
+	// IngredientSearch returns up to limit recipes matching an ingredient
+	// query as described by IngredientQuery.Match, highest score first and
+	// then by id, with the matched terms in Terms. Limit follows the
+	// ListOptions.Limit rules.
+	IngredientSearch(ctx context.Context, query IngredientQuery, limit int) ([]ScoredRecipe, error)
+}
+
+// ScoredRecipe is a search hit with its relevance score. For full-text
+// searches Terms lists the words of the recipe that matched when they
+// differ from the query, e.g. after fuzzy matching, so callers can
+// highlight them; for ingredient searches it lists the matched terms.
+type ScoredRecipe struct {
+	Recipe models.Recipe `bson:",inline"`
+	Score  float64       `bson:"score"`
+	Terms  []string      `bson:"-"`
+}
+
+// Transactor is implemented by the recipe stores that can apply several
+// writes atomically.
+type Transactor interface {
+	// WithTransaction calls fn with a context in which the writes made
+	// through the store take effect together when fn returns nil, and not
+	// at all when it returns an error, which WithTransaction returns. fn
+	// may be called again when a transient error aborts the transaction.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
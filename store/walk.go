@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+
+	"github.com/mrojasb2000/GinRecipes/models"
+)
+
+// Walk calls fn for every recipe matching opts.Filter, following the
+// pagination cursors of s.List so the collection is never loaded at once.
+// opts.Cursor is the starting point and opts.Limit the batch size. Walk
+// stops at the first error returned by fn or the store.
+func Walk(ctx context.Context, s RecipeStore, opts ListOptions, fn func(models.Recipe) error) error {
+	for {
+		page, err := s.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, recipe := range page.Recipes {
+			if err := fn(recipe); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}